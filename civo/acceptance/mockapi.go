@@ -0,0 +1,26 @@
+package acceptance
+
+import (
+	"os"
+
+	"github.com/civo/terraform-provider-civo/internal/mockapi"
+)
+
+// MockAPI is the in-process fake Civo API the acceptance tests run against
+// when CIVO_MOCK_API is set, it's nil otherwise
+var MockAPI *mockapi.Server
+
+func init() {
+	if os.Getenv("CIVO_MOCK_API") == "" {
+		return
+	}
+
+	// The provider reads its endpoint and credentials from the environment
+	// when it's configured, so pointing these at the fake is all it takes
+	MockAPI = mockapi.NewServer()
+	os.Setenv("CIVO_API_URL", MockAPI.URL)
+	os.Setenv("CIVO_TOKEN", mockapi.Token)
+	if os.Getenv("CIVO_REGION") == "" {
+		os.Setenv("CIVO_REGION", mockapi.DefaultRegion)
+	}
+}
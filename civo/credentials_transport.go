@@ -0,0 +1,72 @@
+package civo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// credentialsTransport names the source of the token in the authentication errors of the API,
+// as with skip_credentials_validation the first request is the one that checks the token.
+// When no token was found, every request fails without reaching the API
+type credentialsTransport struct {
+	next   http.RoundTripper
+	source string
+	err    error
+}
+
+func (t *credentialsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		reason := fmt.Sprintf("no Civo token was found in the CIVO_TOKEN environment variable, the credentials_file or ~/.civo.json: %s", t.err)
+		return credentialsErrorResponse(req, "authentication_failed", reason), nil
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var apiError struct {
+		Code   string `json:"code"`
+		Reason string `json:"reason"`
+	}
+	json.Unmarshal(body, &apiError)
+	if apiError.Code == "" {
+		apiError.Code = "database_account_not_found"
+	}
+
+	reason := fmt.Sprintf("the Civo token from %s is invalid, please go to https://dashboard.civo.com/security to generate one", t.source)
+	if apiError.Reason != "" {
+		reason = fmt.Sprintf("%s: %s", reason, apiError.Reason)
+	}
+	return credentialsErrorResponse(req, apiError.Code, reason), nil
+}
+
+// credentialsErrorResponse builds a 401 response in the format of the API errors, so civogo
+// returns the matching error
+func credentialsErrorResponse(req *http.Request, code, reason string) *http.Response {
+	body, _ := json.Marshal(map[string]string{"code": code, "reason": reason})
+	return &http.Response{
+		Status:        strconv.Itoa(http.StatusUnauthorized) + " " + http.StatusText(http.StatusUnauthorized),
+		StatusCode:    http.StatusUnauthorized,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
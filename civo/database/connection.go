@@ -0,0 +1,289 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/civo/civogo"
+	"github.com/go-sql-driver/mysql"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// jdbcSSLModes are the sslMode values of MySQL Connector/J for the sslmode argument
+var jdbcSSLModes = map[string]string{
+	"disable":     "DISABLED",
+	"prefer":      "PREFERRED",
+	"require":     "REQUIRED",
+	"verify-full": "VERIFY_IDENTITY",
+}
+
+// databaseConnection holds what an app needs to connect to a database, and renders it in
+// the formats the clients expect
+type databaseConnection struct {
+	engine   string
+	host     string
+	port     int
+	username string
+	password string
+	// database is the logical database, the clients pick their default when it's empty
+	database string
+	// sslMode is one of the sslmode values, it is left out of the URI when it's empty
+	sslMode string
+}
+
+func (c databaseConnection) address() string {
+	return net.JoinHostPort(c.host, strconv.Itoa(c.port))
+}
+
+// uri returns a mysql:// or postgresql:// URI
+func (c databaseConnection) uri() string {
+	if c.host == "" {
+		return ""
+	}
+
+	u := &url.URL{
+		Scheme: "postgresql",
+		User:   url.UserPassword(c.username, c.password),
+		Host:   c.address(),
+	}
+	if c.database != "" {
+		u.Path = "/" + c.database
+	}
+	query := url.Values{}
+	if c.engine == engineMySQL {
+		u.Scheme = "mysql"
+		if c.sslMode != "" {
+			query.Set("ssl-mode", jdbcSSLModes[c.sslMode])
+		}
+	} else if c.sslMode != "" {
+		query.Set("sslmode", c.sslMode)
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// jdbcURL returns the URL for the JDBC driver of the engine, with the credentials
+func (c databaseConnection) jdbcURL() string {
+	if c.host == "" {
+		return ""
+	}
+
+	query := url.Values{"user": {c.username}, "password": {c.password}}
+	driver := "postgresql"
+	if c.engine == engineMySQL {
+		driver = "mysql"
+		if c.sslMode != "" {
+			query.Set("sslMode", jdbcSSLModes[c.sslMode])
+		}
+	} else if c.sslMode != "" {
+		query.Set("sslmode", c.sslMode)
+	}
+	return fmt.Sprintf("jdbc:%s://%s/%s?%s", driver, c.address(), url.PathEscape(c.database), query.Encode())
+}
+
+// libpqDSN returns the keyword/value connection string of libpq, only PostgreSQL has one
+func (c databaseConnection) libpqDSN() string {
+	if c.host == "" || c.engine != enginePostgreSQL {
+		return ""
+	}
+
+	parts := []string{
+		"host=" + libpqValue(c.host),
+		"port=" + strconv.Itoa(c.port),
+	}
+	if c.database != "" {
+		parts = append(parts, "dbname="+libpqValue(c.database))
+	}
+	parts = append(parts, "user="+libpqValue(c.username), "password="+libpqValue(c.password))
+	if c.sslMode != "" {
+		parts = append(parts, "sslmode="+c.sslMode)
+	}
+	return strings.Join(parts, " ")
+}
+
+// goDSN returns the data source name for the Go driver of the engine, go-sql-driver/mysql
+// for MySQL, and a URI lib/pq and pgx both accept for PostgreSQL
+func (c databaseConnection) goDSN() string {
+	if c.host == "" {
+		return ""
+	}
+	if c.engine != engineMySQL {
+		return c.uri()
+	}
+
+	config := mysql.NewConfig()
+	config.User = c.username
+	config.Passwd = c.password
+	config.Net = "tcp"
+	config.Addr = c.address()
+	config.DBName = c.database
+	config.TLSConfig = sslModes[c.sslMode]
+	return config.FormatDSN()
+}
+
+// databaseConnectionURIs returns the URIs of the database for its admin user, on the DNS
+// endpoint and on the private IP
+func databaseConnectionURIs(db *civogo.Database) (string, string) {
+	connection := databaseConnection{
+		engine:   strings.ToLower(db.Software),
+		host:     fmt.Sprintf("%s.db.civo.com", db.ID),
+		port:     db.Port,
+		username: db.Username,
+		password: db.Password,
+	}
+	private := connection
+	private.host = db.PrivateIPv4
+	return connection.uri(), private.uri()
+}
+
+// libpqValue quotes a value of a libpq connection string when it needs to be
+func libpqValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, ` '\`) {
+		return value
+	}
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return "'" + strings.ReplaceAll(value, "'", `\'`) + "'"
+}
+
+// readCACertificate returns the CA certificate of a database when fetch is set. The CA is
+// only presented by the server and not everyone running Terraform can reach it, so a failed
+// fetch is a warning and the certificate is empty
+func readCACertificate(ctx context.Context, fetch bool, name, engine, address string) (string, diag.Diagnostics) {
+	if !fetch {
+		return "", nil
+	}
+
+	fetchCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	caCertificate, err := fetchCACertificate(fetchCtx, engine, address)
+	if err != nil {
+		return "", diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("The CA certificate of the database %s couldn't be read", name),
+			Detail:   fmt.Sprintf("ca_certificate is empty, the CA is read from the server at %s: %s", address, err),
+		}}
+	}
+	return caCertificate, nil
+}
+
+// fetchCACertificate connects to the database and returns the PEM encoded certificate at
+// the top of the chain the server presents. The engines only switch to TLS once the client
+// asks for it, so the handshake starts in the protocol of the engine
+func fetchCACertificate(ctx context.Context, engine, address string) (string, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	switch engine {
+	case engineMySQL:
+		err = startMySQLTLS(conn)
+	case enginePostgreSQL:
+		err = startPostgresTLS(conn)
+	default:
+		err = fmt.Errorf("the engine %s isn't supported", engine)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// nothing is trusted yet, this is the certificate the apps are going to trust, so the
+	// chain can't be verified and whoever answers on the address is trusted on first use.
+	// This is why the fetch is opt-in with fetch_ca_certificate
+	client := tls.Client(conn, &tls.Config{InsecureSkipVerify: true})
+	if err := client.HandshakeContext(ctx); err != nil {
+		return "", fmt.Errorf("the TLS handshake failed: %s", err)
+	}
+
+	chain := client.ConnectionState().PeerCertificates
+	if len(chain) == 0 {
+		return "", fmt.Errorf("the server presented no certificate")
+	}
+	top := chain[len(chain)-1]
+	if !top.IsCA && !isSelfSigned(top) {
+		return "", fmt.Errorf("the server only presented its certificate, issued by %s, and not the CA", top.Issuer)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: top.Raw})), nil
+}
+
+func isSelfSigned(cert *x509.Certificate) bool {
+	return bytes.Equal(cert.RawIssuer, cert.RawSubject) && cert.CheckSignatureFrom(cert) == nil
+}
+
+// startPostgresTLS sends the SSLRequest message, the server answers S when it has TLS
+func startPostgresTLS(conn net.Conn) error {
+	request := make([]byte, 8)
+	binary.BigEndian.PutUint32(request[0:4], 8)
+	binary.BigEndian.PutUint32(request[4:8], 80877103)
+	if _, err := conn.Write(request); err != nil {
+		return err
+	}
+
+	answer := make([]byte, 1)
+	if _, err := io.ReadFull(conn, answer); err != nil {
+		return err
+	}
+	if answer[0] != 'S' {
+		return fmt.Errorf("the server doesn't accept TLS connections")
+	}
+	return nil
+}
+
+const (
+	mysqlClientProtocol41       = 0x00000200
+	mysqlClientSSL              = 0x00000800
+	mysqlClientSecureConnection = 0x00008000
+)
+
+// startMySQLTLS reads the greeting of the server and answers with an SSLRequest packet,
+// the handshake continues in TLS
+func startMySQLTLS(conn net.Conn) error {
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return err
+	}
+	greeting := make([]byte, int(header[0])|int(header[1])<<8|int(header[2])<<16)
+	if _, err := io.ReadFull(conn, greeting); err != nil {
+		return err
+	}
+	if len(greeting) == 0 || greeting[0] != 10 {
+		return fmt.Errorf("unexpected greeting from the server")
+	}
+
+	// protocol version, server version, connection ID, auth data and filler come
+	// before the lower capability flags
+	end := bytes.IndexByte(greeting[1:], 0)
+	offset := 1 + end + 1 + 4 + 8 + 1
+	if end < 0 || len(greeting) < offset+2 {
+		return fmt.Errorf("unexpected greeting from the server")
+	}
+	if binary.LittleEndian.Uint16(greeting[offset:offset+2])&mysqlClientSSL == 0 {
+		return fmt.Errorf("the server doesn't accept TLS connections")
+	}
+
+	request := make([]byte, 4+32)
+	request[0] = 32
+	request[3] = header[3] + 1
+	binary.LittleEndian.PutUint32(request[4:8], mysqlClientProtocol41|mysqlClientSSL|mysqlClientSecureConnection)
+	binary.LittleEndian.PutUint32(request[8:12], 1<<24)
+	// utf8mb4_general_ci
+	request[12] = 45
+	_, err := conn.Write(request)
+	return err
+}
@@ -0,0 +1,322 @@
+package database
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/civo/civogo"
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/lib/pq/pqerror"
+)
+
+const (
+	engineMySQL      = "mysql"
+	enginePostgreSQL = "postgresql"
+
+	// postgresSchema is the schema of a PostgreSQL database the grants apply to, the
+	// tables live there unless the apps create other schemas
+	postgresSchema = "public"
+)
+
+// sslModes are the values of the sslmode argument, with the PostgreSQL meaning, and the
+// tls parameter of the MySQL driver they map to
+var sslModes = map[string]string{
+	"disable":     "false",
+	"prefer":      "preferred",
+	"require":     "skip-verify",
+	"verify-full": "true",
+}
+
+// databasePrivileges are the privileges a user can be granted on a database, they are
+// translated to the engine in grantStatements
+var databasePrivileges = []string{"ALL", "SELECT", "INSERT", "UPDATE", "DELETE", "CREATE"}
+
+// sqlIdentifier is what we accept as user and database names, the names are quoted
+// anyway, this keeps them usable in the apps connection strings
+var sqlIdentifier = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// reservedDatabaseNames can't be managed, they belong to the engine or to Civo
+var reservedDatabaseNames = []string{"mysql", "information_schema", "performance_schema", "sys", "postgres", "template0", "template1"}
+
+// sqlConnection is an open connection to a database, as the admin user
+type sqlConnection struct {
+	*sql.DB
+	engine string
+}
+
+// connectDatabase opens a connection to the database of the managed instance, with the
+// admin user returned by the API. An empty name connects to the default database
+func connectDatabase(ctx context.Context, apiClient *civogo.Client, databaseID, host, sslMode, name string) (*sqlConnection, error) {
+	db, err := apiClient.GetDatabase(databaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if host == "" {
+		host = db.PublicIPv4
+	}
+	if host == "" {
+		return nil, fmt.Errorf("the database %s has no public endpoint, set host to an address Terraform can reach", db.Name)
+	}
+
+	engine := strings.ToLower(db.Software)
+	if engine != engineMySQL && engine != enginePostgreSQL {
+		return nil, fmt.Errorf("the engine %s of the database %s doesn't support users and databases", db.Software, db.Name)
+	}
+	if engine == enginePostgreSQL && name == "" {
+		name = "postgres"
+	}
+	connection := databaseConnection{
+		engine:   engine,
+		host:     host,
+		port:     db.Port,
+		username: db.Username,
+		password: db.Password,
+		database: name,
+		sslMode:  sslMode,
+	}
+
+	driver := "mysql"
+	if engine == enginePostgreSQL {
+		driver = "postgres"
+	}
+	conn, err := sql.Open(driver, connection.goDSN())
+	if err != nil {
+		return nil, err
+	}
+	conn.SetMaxOpenConns(1)
+
+	pingCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err := conn.PingContext(pingCtx); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to connect to the database %s at %s: %w", db.Name, connection.address(), err)
+	}
+
+	return &sqlConnection{DB: conn, engine: engine}, nil
+}
+
+// execStatements runs the statements in order, and stops at the first error
+func (c *sqlConnection) execStatements(ctx context.Context, statements []string) error {
+	for _, statement := range statements {
+		if _, err := c.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exists runs a count query and reports whether it found anything
+func (c *sqlConnection) exists(ctx context.Context, query string, args ...interface{}) (bool, error) {
+	var count int
+	if err := c.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// quoteMySQLIdentifier quotes a database name for MySQL
+func quoteMySQLIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// quoteMySQLString quotes a string literal for MySQL
+func quoteMySQLString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// quotePostgresString quotes a string literal for PostgreSQL
+func quotePostgresString(s string) string {
+	// pq prefixes the literals with backslashes with " E"
+	return strings.TrimSpace(pq.QuoteLiteral(s))
+}
+
+// mysqlAccount is the account of a user, who can connect from anywhere the firewall allows
+func mysqlAccount(username string) string {
+	return quoteMySQLString(username) + "@'%'"
+}
+
+// createUserStatement returns the statement creating the user with the password
+func createUserStatement(engine, username, password string) string {
+	if engine == engineMySQL {
+		return fmt.Sprintf("CREATE USER %s IDENTIFIED BY %s", mysqlAccount(username), quoteMySQLString(password))
+	}
+	return fmt.Sprintf("CREATE ROLE %s WITH LOGIN PASSWORD %s", pq.QuoteIdentifier(username), quotePostgresString(password))
+}
+
+// alterPasswordStatement returns the statement changing the password of the user
+func alterPasswordStatement(engine, username, password string) string {
+	if engine == engineMySQL {
+		return fmt.Sprintf("ALTER USER %s IDENTIFIED BY %s", mysqlAccount(username), quoteMySQLString(password))
+	}
+	return fmt.Sprintf("ALTER ROLE %s WITH PASSWORD %s", pq.QuoteIdentifier(username), quotePostgresString(password))
+}
+
+// dropUserStatement returns the statement removing the user
+func dropUserStatement(engine, username string) string {
+	if engine == engineMySQL {
+		return fmt.Sprintf("DROP USER IF EXISTS %s", mysqlAccount(username))
+	}
+	return fmt.Sprintf("DROP ROLE IF EXISTS %s", pq.QuoteIdentifier(username))
+}
+
+// userExistsQuery returns the query counting the users with the name
+func userExistsQuery(engine string) string {
+	if engine == engineMySQL {
+		return "SELECT COUNT(*) FROM mysql.user WHERE user = ? AND host = '%'"
+	}
+	return "SELECT COUNT(*) FROM pg_roles WHERE rolname = $1"
+}
+
+// createSchemaStatement returns the statement creating the database
+func createSchemaStatement(engine, name string) string {
+	if engine == engineMySQL {
+		return fmt.Sprintf("CREATE DATABASE %s", quoteMySQLIdentifier(name))
+	}
+	return fmt.Sprintf("CREATE DATABASE %s", pq.QuoteIdentifier(name))
+}
+
+// dropSchemaStatement returns the statement removing the database
+func dropSchemaStatement(engine, name string) string {
+	if engine == engineMySQL {
+		return fmt.Sprintf("DROP DATABASE IF EXISTS %s", quoteMySQLIdentifier(name))
+	}
+	return fmt.Sprintf("DROP DATABASE IF EXISTS %s", pq.QuoteIdentifier(name))
+}
+
+// schemaExistsQuery returns the query counting the databases with the name
+func schemaExistsQuery(engine string) string {
+	if engine == engineMySQL {
+		return "SELECT COUNT(*) FROM information_schema.schemata WHERE schema_name = ?"
+	}
+	return "SELECT COUNT(*) FROM pg_database WHERE datname = $1"
+}
+
+// grantStatements returns the statements granting the privileges on the database to the user.
+// In MySQL they are granted on every table of the database. PostgreSQL grants them on the
+// tables of the public schema, the existing ones and the ones created later by the admin
+// user, and has to run them connected to the database
+func grantStatements(engine, username, database string, privileges []string) []string {
+	all := false
+	tablePrivileges := []string{}
+	create := false
+	for _, privilege := range databasePrivileges {
+		if !containsPrivilege(privileges, privilege) {
+			continue
+		}
+		switch privilege {
+		case "ALL":
+			all = true
+		case "CREATE":
+			create = true
+		default:
+			tablePrivileges = append(tablePrivileges, privilege)
+		}
+	}
+
+	if engine == engineMySQL {
+		granted := strings.Join(tablePrivileges, ", ")
+		if create {
+			granted = strings.Join(append(tablePrivileges, "CREATE", "ALTER", "INDEX", "DROP", "REFERENCES"), ", ")
+		}
+		if all {
+			granted = "ALL PRIVILEGES"
+		}
+		return []string{fmt.Sprintf("GRANT %s ON %s.* TO %s", granted, quoteMySQLIdentifier(database), mysqlAccount(username))}
+	}
+
+	user := pq.QuoteIdentifier(username)
+	schema := pq.QuoteIdentifier(postgresSchema)
+	statements := []string{fmt.Sprintf("GRANT CONNECT ON DATABASE %s TO %s", pq.QuoteIdentifier(database), user)}
+	if all {
+		return append(statements,
+			fmt.Sprintf("GRANT ALL PRIVILEGES ON DATABASE %s TO %s", pq.QuoteIdentifier(database), user),
+			fmt.Sprintf("GRANT ALL ON SCHEMA %s TO %s", schema, user),
+			fmt.Sprintf("GRANT ALL ON ALL TABLES IN SCHEMA %s TO %s", schema, user),
+			fmt.Sprintf("GRANT ALL ON ALL SEQUENCES IN SCHEMA %s TO %s", schema, user),
+			fmt.Sprintf("ALTER DEFAULT PRIVILEGES IN SCHEMA %s GRANT ALL ON TABLES TO %s", schema, user),
+			fmt.Sprintf("ALTER DEFAULT PRIVILEGES IN SCHEMA %s GRANT ALL ON SEQUENCES TO %s", schema, user),
+		)
+	}
+
+	statements = append(statements, fmt.Sprintf("GRANT USAGE ON SCHEMA %s TO %s", schema, user))
+	if create {
+		statements = append(statements, fmt.Sprintf("GRANT CREATE ON SCHEMA %s TO %s", schema, user))
+	}
+	if len(tablePrivileges) > 0 {
+		granted := strings.Join(tablePrivileges, ", ")
+		statements = append(statements,
+			fmt.Sprintf("GRANT %s ON ALL TABLES IN SCHEMA %s TO %s", granted, schema, user),
+			fmt.Sprintf("GRANT USAGE, SELECT ON ALL SEQUENCES IN SCHEMA %s TO %s", schema, user),
+			fmt.Sprintf("ALTER DEFAULT PRIVILEGES IN SCHEMA %s GRANT %s ON TABLES TO %s", schema, granted, user),
+			fmt.Sprintf("ALTER DEFAULT PRIVILEGES IN SCHEMA %s GRANT USAGE, SELECT ON SEQUENCES TO %s", schema, user),
+		)
+	}
+	return statements
+}
+
+// revokeStatements returns the statements removing every privilege of the user on the database
+func revokeStatements(engine, username, database string) []string {
+	if engine == engineMySQL {
+		return []string{fmt.Sprintf("REVOKE ALL ON %s.* FROM %s", quoteMySQLIdentifier(database), mysqlAccount(username))}
+	}
+
+	user := pq.QuoteIdentifier(username)
+	schema := pq.QuoteIdentifier(postgresSchema)
+	return []string{
+		fmt.Sprintf("REVOKE ALL PRIVILEGES ON DATABASE %s FROM %s", pq.QuoteIdentifier(database), user),
+		fmt.Sprintf("REVOKE ALL ON SCHEMA %s FROM %s", schema, user),
+		fmt.Sprintf("REVOKE ALL ON ALL TABLES IN SCHEMA %s FROM %s", schema, user),
+		fmt.Sprintf("REVOKE ALL ON ALL SEQUENCES IN SCHEMA %s FROM %s", schema, user),
+		fmt.Sprintf("ALTER DEFAULT PRIVILEGES IN SCHEMA %s REVOKE ALL ON TABLES FROM %s", schema, user),
+		fmt.Sprintf("ALTER DEFAULT PRIVILEGES IN SCHEMA %s REVOKE ALL ON SEQUENCES FROM %s", schema, user),
+	}
+}
+
+// isMissingGrant reports whether the privileges couldn't be revoked because the user or the
+// database is already gone, which is what we wanted anyway
+func isMissingGrant(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		// ER_BAD_DB_ERROR, ER_NONEXISTING_GRANT and ER_NONEXISTING_TABLE_GRANT
+		return mysqlErr.Number == 1049 || mysqlErr.Number == 1141 || mysqlErr.Number == 1147
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == pqerror.InvalidCatalogName || pqErr.Code == pqerror.UndefinedObject
+	}
+	return false
+}
+
+func containsPrivilege(privileges []string, privilege string) bool {
+	for _, p := range privileges {
+		if strings.EqualFold(p, privilege) {
+			return true
+		}
+	}
+	return false
+}
+
+// generatePassword returns a random password of letters and digits, safe to use in any
+// connection string
+func generatePassword(length int) (string, error) {
+	const chars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	password := make([]byte, length)
+	for i := range password {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(chars))))
+		if err != nil {
+			return "", err
+		}
+		password[i] = chars[n.Int64()]
+	}
+	return string(password), nil
+}
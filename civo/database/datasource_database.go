@@ -99,6 +99,18 @@ func DataSourceDatabase() *schema.Resource {
 				Computed:    true,
 				Description: "The status of the database",
 			},
+			"connection_uri": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The mysql:// or postgresql:// URI of the database with the credentials of the admin user, on its DNS endpoint",
+			},
+			"private_connection_uri": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The mysql:// or postgresql:// URI of the database with the credentials of the admin user, on its private IP",
+			},
 		},
 		ReadContext: dataSourceDatabaseRead,
 	}
@@ -150,5 +162,9 @@ func dataSourceDatabaseRead(_ context.Context, d *schema.ResourceData, m interfa
 	d.Set("port", foundDatabase.Port)
 	d.Set("status", foundDatabase.Status)
 
+	connectionURI, privateConnectionURI := databaseConnectionURIs(foundDatabase)
+	d.Set("connection_uri", connectionURI)
+	d.Set("private_connection_uri", privateConnectionURI)
+
 	return nil
 }
@@ -0,0 +1,123 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/civo/civogo"
+	"github.com/civo/terraform-provider-civo/internal/datalist"
+	"github.com/civo/terraform-provider-civo/internal/utils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// DataSourceDatabaseBackup Data source to get and filter the backups of a database,
+// use to pick the backup restored by restore_from in resourceDatabase
+func DataSourceDatabaseBackup() *schema.Resource {
+	dataListConfig := &datalist.ResourceConfig{
+		Description:  "Get information on the backups of a database for use in other resources (e.g. restoring a database) with the ability to filter and sort the results. If no filters are specified, all backups of the database will be returned.",
+		RecordSchema: databaseBackupSchema(),
+		ExtraQuerySchema: map[string]*schema.Schema{
+			"database_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: utils.ValidateUUID,
+				Description:  "The ID of the database",
+			},
+			"region": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "If used, the database will be looked up in the provided region",
+			},
+		},
+		ResultAttributeName: "backups",
+		FlattenRecord:       flattenDatabaseBackup,
+		GetRecords:          getDatabaseBackups,
+	}
+
+	return datalist.NewResource(dataListConfig)
+}
+
+func getDatabaseBackups(m interface{}, extra map[string]interface{}) ([]interface{}, error) {
+	apiClient := m.(*civogo.Client)
+
+	// overwrite the region if is define in the datasource
+	region, ok := extra["region"].(string)
+	if !ok {
+		return nil, fmt.Errorf("unable to find `region` key from query data")
+	}
+
+	if region != "" {
+		apiClient = utils.RegionalClient(apiClient, region)
+	}
+
+	databaseID := extra["database_id"].(string)
+	backups, err := apiClient.ListDatabaseBackup(databaseID)
+	if err != nil {
+		return nil, fmt.Errorf("[ERR] error retrieving the backups of the database %s: %s", databaseID, err)
+	}
+
+	var records []interface{}
+	for _, backup := range backups.Items {
+		records = append(records, backup)
+	}
+
+	return records, nil
+}
+
+func flattenDatabaseBackup(backup, _ interface{}, _ map[string]interface{}) (map[string]interface{}, error) {
+	b := backup.(civogo.DatabaseBackup)
+
+	flattenedBackup := map[string]interface{}{}
+	flattenedBackup["id"] = b.ID
+	flattenedBackup["name"] = b.Name
+	flattenedBackup["database_id"] = b.DatabaseID
+	flattenedBackup["database_name"] = b.DatabaseName
+	flattenedBackup["software"] = b.Software
+	flattenedBackup["status"] = b.Status
+	flattenedBackup["schedule"] = b.Schedule
+	flattenedBackup["is_scheduled"] = b.IsScheduled
+	flattenedBackup["created_at"] = b.CreatedAt.UTC().Format(time.RFC3339)
+
+	return flattenedBackup, nil
+}
+
+func databaseBackupSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"id": {
+			Type:        schema.TypeString,
+			Description: "The ID of the backup",
+		},
+		"name": {
+			Type:        schema.TypeString,
+			Description: "The name of the backup",
+		},
+		"database_id": {
+			Type:        schema.TypeString,
+			Description: "The ID of the database",
+		},
+		"database_name": {
+			Type:        schema.TypeString,
+			Description: "The name of the database",
+		},
+		"software": {
+			Type:        schema.TypeString,
+			Description: "The engine of the database",
+		},
+		"status": {
+			Type:        schema.TypeString,
+			Description: "The status of the backup",
+		},
+		"schedule": {
+			Type:        schema.TypeString,
+			Description: "The cron expression of scheduled backups",
+		},
+		"is_scheduled": {
+			Type:        schema.TypeBool,
+			Description: "Whether the backup was taken by the schedule",
+		},
+		"created_at": {
+			Type:        schema.TypeString,
+			Description: "The time the backup was created, in RFC 3339 format so it sorts in time order",
+		},
+	}
+}
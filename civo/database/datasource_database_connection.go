@@ -0,0 +1,171 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/civo/civogo"
+	"github.com/civo/terraform-provider-civo/internal/utils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// DataSourceDatabaseConnection Data source to render the connection strings of a database
+// for the clients of its engine, and to read the CA of its certificate
+func DataSourceDatabaseConnection() *schema.Resource {
+	return &schema.Resource{
+		Description: "Renders the connection strings of a Civo database in the formats the clients expect (URI, JDBC, libpq and Go), for the admin user or another user, and optionally reads the CA certificate of the database.",
+		Schema: map[string]*schema.Schema{
+			"database_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: utils.ValidateUUID,
+				Description:  "The ID of the database",
+			},
+			"database": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The logical database to connect to, e.g. from `civo_database_schema`. The clients pick their default when it's not set",
+			},
+			"username": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"password"},
+				Description:  "The user to connect as, e.g. from `civo_database_user`. The admin user of the database when it's not set",
+			},
+			"password": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				RequiredWith: []string{"username"},
+				Description:  "The password of `username`",
+			},
+			"private": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether to connect to the private IP of the database, for apps in its network, instead of its DNS endpoint",
+			},
+			"sslmode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "require",
+				ValidateFunc: validation.StringInSlice([]string{"disable", "prefer", "require", "verify-full"}, false),
+				Description:  "Whether the clients use TLS, one of `disable`, `prefer`, `require` or `verify-full`. It is translated to the option of each client",
+			},
+			"fetch_ca_certificate": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether to read the CA certificate of the database from the TLS handshake with the server into `ca_certificate`. The certificate isn't verified, it is trusted on first use",
+			},
+			"region": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The region of the database",
+			},
+			// Computed resource
+			"engine": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The engine of the database, `mysql` or `postgresql`",
+			},
+			"host": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The host the connection strings point at",
+			},
+			"port": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The port of the database",
+			},
+			"uri": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The mysql:// or postgresql:// URI",
+			},
+			"jdbc_url": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The URL for the JDBC driver of the engine, with the credentials",
+			},
+			"libpq_dsn": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The keyword/value connection string of libpq, e.g. `host=... port=5432 user=...`. Only for PostgreSQL",
+			},
+			"go_dsn": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The data source name for `database/sql`, in the format of go-sql-driver/mysql for MySQL and a URI lib/pq and pgx accept for PostgreSQL",
+			},
+			"ca_certificate": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The PEM encoded CA certificate of the database, as presented by the server, when `fetch_ca_certificate` is set. Empty with a warning when the server can't be reached or doesn't present its CA",
+			},
+		},
+		ReadContext: dataSourceDatabaseConnectionRead,
+	}
+}
+
+func dataSourceDatabaseConnectionRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*civogo.Client)
+
+	// overwrite the region if is define in the datasource
+	if region, ok := d.GetOk("region"); ok {
+		apiClient = utils.RegionalClient(apiClient, region.(string))
+	}
+
+	databaseID := d.Get("database_id").(string)
+
+	log.Printf("[INFO] retrieving the database %s", databaseID)
+	db, err := apiClient.GetDatabase(databaseID)
+	if err != nil {
+		return diag.Errorf("[ERR] failed to retrieve the database %s: %s", databaseID, err)
+	}
+
+	connection := databaseConnection{
+		engine:   strings.ToLower(db.Software),
+		host:     fmt.Sprintf("%s.db.civo.com", db.ID),
+		port:     db.Port,
+		username: db.Username,
+		password: db.Password,
+		database: d.Get("database").(string),
+		sslMode:  d.Get("sslmode").(string),
+	}
+	if d.Get("private").(bool) {
+		if db.PrivateIPv4 == "" {
+			return diag.Errorf("[ERR] the database %s has no private IP", db.Name)
+		}
+		connection.host = db.PrivateIPv4
+	}
+	if username, ok := d.GetOk("username"); ok {
+		connection.username = username.(string)
+		connection.password = d.Get("password").(string)
+	}
+
+	d.SetId(db.ID)
+	d.Set("region", apiClient.Region)
+	d.Set("engine", connection.engine)
+	d.Set("host", connection.host)
+	d.Set("port", connection.port)
+	d.Set("uri", connection.uri())
+	d.Set("jdbc_url", connection.jdbcURL())
+	d.Set("libpq_dsn", connection.libpqDSN())
+	d.Set("go_dsn", connection.goDSN())
+
+	caCertificate, diags := readCACertificate(ctx, d.Get("fetch_ca_certificate").(bool), db.Name, connection.engine, connection.address())
+	d.Set("ca_certificate", caCertificate)
+
+	return diags
+}
@@ -0,0 +1,292 @@
+package database_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/civo/terraform-provider-civo/civo/acceptance"
+	"github.com/civo/terraform-provider-civo/civo/database"
+	"github.com/go-sql-driver/mysql"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+// TestAccDataSourceCivoDatabaseConnection_basic tests the connection strings of a database for the admin user
+func TestAccDataSourceCivoDatabaseConnection_basic(t *testing.T) {
+	datasourceName := "data.civo_database_connection.foobar"
+	name := acctest.RandomWithPrefix("database")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { acceptance.TestAccPreCheck(t) },
+		Providers: acceptance.TestAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: DataSourceCivoDatabaseConnectionConfig(name),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(datasourceName, "engine", "postgresql"),
+					resource.TestCheckResourceAttr(datasourceName, "port", "5432"),
+					resource.TestCheckResourceAttrPair(datasourceName, "host", "civo_database.foobar", "dns_endpoint"),
+					resource.TestMatchResourceAttr(datasourceName, "uri", regexp.MustCompile(`^postgresql://`)),
+					resource.TestMatchResourceAttr(datasourceName, "jdbc_url", regexp.MustCompile(`^jdbc:postgresql://`)),
+					resource.TestCheckResourceAttrSet(datasourceName, "libpq_dsn"),
+					resource.TestCheckResourceAttrSet(datasourceName, "go_dsn"),
+				),
+			},
+		},
+	})
+}
+
+// DataSourceCivoDatabaseConnectionConfig is used to configure the data source
+func DataSourceCivoDatabaseConnectionConfig(name string) string {
+	return fmt.Sprintf(`
+resource "civo_database" "foobar" {
+	name = "%s"
+	size = "g3.db.small"
+	engine = "PostgreSQL"
+	version = "16"
+	nodes = 1
+}
+
+data "civo_database_connection" "foobar" {
+	database_id = civo_database.foobar.id
+}`, name)
+}
+
+func TestRenderConnection(t *testing.T) {
+	password := "p@ss w'rd"
+
+	postgres := database.ExportRenderConnection("postgresql", "db.example.com", 5432, "app", password, "orders", "require")
+	uri, err := url.Parse(postgres["uri"])
+	if err != nil {
+		t.Fatalf("invalid uri %q: %s", postgres["uri"], err)
+	}
+	if got, _ := uri.User.Password(); uri.Scheme != "postgresql" || uri.Host != "db.example.com:5432" || uri.Path != "/orders" || got != password || uri.Query().Get("sslmode") != "require" {
+		t.Errorf("unexpected uri %q", postgres["uri"])
+	}
+	if expected := `host=db.example.com port=5432 dbname=orders user=app password='p@ss w\'rd' sslmode=require`; postgres["libpq_dsn"] != expected {
+		t.Errorf("expected libpq_dsn %q, got %q", expected, postgres["libpq_dsn"])
+	}
+	if postgres["go_dsn"] != postgres["uri"] {
+		t.Errorf("expected go_dsn to be the uri, got %q", postgres["go_dsn"])
+	}
+	jdbc, err := url.Parse(strings.TrimPrefix(postgres["jdbc_url"], "jdbc:"))
+	if err != nil {
+		t.Fatalf("invalid jdbc_url %q: %s", postgres["jdbc_url"], err)
+	}
+	if !strings.HasPrefix(postgres["jdbc_url"], "jdbc:postgresql://db.example.com:5432/orders?") || jdbc.Query().Get("password") != password || jdbc.Query().Get("sslmode") != "require" {
+		t.Errorf("unexpected jdbc_url %q", postgres["jdbc_url"])
+	}
+
+	mysqlConnection := database.ExportRenderConnection("mysql", "10.0.0.5", 3306, "app", password, "orders", "verify-full")
+	uri, err = url.Parse(mysqlConnection["uri"])
+	if err != nil {
+		t.Fatalf("invalid uri %q: %s", mysqlConnection["uri"], err)
+	}
+	if got, _ := uri.User.Password(); uri.Scheme != "mysql" || got != password || uri.Query().Get("ssl-mode") != "VERIFY_IDENTITY" {
+		t.Errorf("unexpected uri %q", mysqlConnection["uri"])
+	}
+	if mysqlConnection["libpq_dsn"] != "" {
+		t.Errorf("expected no libpq_dsn for MySQL, got %q", mysqlConnection["libpq_dsn"])
+	}
+	config, err := mysql.ParseDSN(mysqlConnection["go_dsn"])
+	if err != nil {
+		t.Fatalf("invalid go_dsn %q: %s", mysqlConnection["go_dsn"], err)
+	}
+	if config.Addr != "10.0.0.5:3306" || config.Passwd != password || config.DBName != "orders" || config.TLSConfig != "true" {
+		t.Errorf("unexpected go_dsn %q", mysqlConnection["go_dsn"])
+	}
+	if !strings.HasPrefix(mysqlConnection["jdbc_url"], "jdbc:mysql://10.0.0.5:3306/orders?") || !strings.Contains(mysqlConnection["jdbc_url"], "sslMode=VERIFY_IDENTITY") {
+		t.Errorf("unexpected jdbc_url %q", mysqlConnection["jdbc_url"])
+	}
+
+	if empty := database.ExportRenderConnection("mysql", "", 3306, "app", password, "", ""); empty["uri"] != "" || empty["go_dsn"] != "" {
+		t.Errorf("expected no connection strings without a host, got %v", empty)
+	}
+}
+
+func TestFetchCACertificate(t *testing.T) {
+	ca, caKey := newTestCertificate(t, nil, nil, true)
+	leaf, leafKey := newTestCertificate(t, ca, caKey, false)
+	caPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw}))
+
+	chain := tls.Certificate{Certificate: [][]byte{leaf.Raw, ca.Raw}, PrivateKey: leafKey}
+	leafOnly := tls.Certificate{Certificate: [][]byte{leaf.Raw}, PrivateKey: leafKey}
+
+	cases := []struct {
+		name        string
+		engine      string
+		certificate tls.Certificate
+		tls         bool
+		wantErr     bool
+	}{
+		{name: "PostgreSQL", engine: "postgresql", certificate: chain, tls: true},
+		{name: "MySQL", engine: "mysql", certificate: chain, tls: true},
+		{name: "CA not presented", engine: "postgresql", certificate: leafOnly, tls: true, wantErr: true},
+		{name: "PostgreSQL without TLS", engine: "postgresql", wantErr: true},
+		{name: "MySQL without TLS", engine: "mysql", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			address := serveTLSDatabase(t, tc.engine, tc.certificate, tc.tls)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			got, err := database.ExportFetchCACertificate(ctx, tc.engine, address)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected error: %t, got: %v", tc.wantErr, err)
+			}
+			if !tc.wantErr && got != caPEM {
+				t.Errorf("expected the CA certificate, got:\n%s", got)
+			}
+		})
+	}
+}
+
+func TestReadCACertificate(t *testing.T) {
+	ca, caKey := newTestCertificate(t, nil, nil, true)
+	leaf, leafKey := newTestCertificate(t, ca, caKey, false)
+	caPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw}))
+	chain := tls.Certificate{Certificate: [][]byte{leaf.Raw, ca.Raw}, PrivateKey: leafKey}
+
+	// nothing listens on the address once the listener is closed
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	unreachable := listener.Addr().String()
+	listener.Close()
+
+	cases := []struct {
+		name        string
+		fetch       bool
+		address     string
+		want        string
+		wantWarning bool
+	}{
+		{name: "not fetched", fetch: false, address: serveTLSDatabase(t, "postgresql", chain, true)},
+		{name: "fetched", fetch: true, address: serveTLSDatabase(t, "postgresql", chain, true), want: caPEM},
+		{name: "unreachable", fetch: true, address: unreachable, wantWarning: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, diags := database.ExportReadCACertificate(context.Background(), tc.fetch, "orders", "postgresql", tc.address)
+			if diags.HasError() {
+				t.Fatalf("expected no error, got: %v", diags)
+			}
+			if tc.wantWarning != (len(diags) == 1) {
+				t.Fatalf("expected a warning: %t, got: %v", tc.wantWarning, diags)
+			}
+			if got != tc.want {
+				t.Errorf("expected the CA certificate %q, got: %q", tc.want, got)
+			}
+		})
+	}
+}
+
+// serveTLSDatabase answers one connection the way the engine starts TLS, and refuses TLS
+// when withTLS is false
+func serveTLSDatabase(t *testing.T, engine string, certificate tls.Certificate, withTLS bool) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		if engine == "mysql" {
+			capabilities := uint16(0x0200 | 0x8000)
+			if withTLS {
+				capabilities |= 0x0800
+			}
+			payload := append([]byte{10}, "8.0.36\x00"...)
+			payload = append(payload, 1, 0, 0, 0)
+			payload = append(payload, "abcdefgh"...)
+			payload = append(payload, 0, byte(capabilities), byte(capabilities>>8), 45, 2, 0, 0, 0, 21)
+			payload = append(payload, make([]byte, 10)...)
+			payload = append(payload, "ijklmnopqrst\x00mysql_native_password\x00"...)
+			conn.Write(append([]byte{byte(len(payload)), byte(len(payload) >> 8), byte(len(payload) >> 16), 0}, payload...))
+			if !withTLS {
+				return
+			}
+			if _, err := io.ReadFull(conn, make([]byte, 36)); err != nil {
+				return
+			}
+		} else {
+			if _, err := io.ReadFull(conn, make([]byte, 8)); err != nil {
+				return
+			}
+			if !withTLS {
+				conn.Write([]byte{'N'})
+				return
+			}
+			conn.Write([]byte{'S'})
+		}
+
+		server := tls.Server(conn, &tls.Config{Certificates: []tls.Certificate{certificate}})
+		server.Handshake()
+	}()
+
+	return listener.Addr().String()
+}
+
+// newTestCertificate returns a CA when parent is nil, and a certificate it issued otherwise
+func newTestCertificate(t *testing.T, parent *x509.Certificate, parentKey *ecdsa.PrivateKey, isCA bool) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate the key: %s", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: "db.example.com"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+	if isCA {
+		template.Subject = pkix.Name{CommonName: "Test Database CA"}
+		template.IsCA = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+
+	raw, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatalf("failed to create the certificate: %s", err)
+	}
+	certificate, err := x509.ParseCertificate(raw)
+	if err != nil {
+		t.Fatalf("failed to parse the certificate: %s", err)
+	}
+	return certificate, key
+}
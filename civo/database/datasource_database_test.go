@@ -27,6 +27,7 @@ func TestAccDataSourceCivoDatabase_basic(t *testing.T) {
 					resource.TestCheckResourceAttrSet(datasourceName, "engine"),
 					resource.TestCheckResourceAttrSet(datasourceName, "version"),
 					resource.TestCheckResourceAttr(datasourceName, "status", "Ready"),
+					resource.TestCheckResourceAttrPair(datasourceName, "connection_uri", "civo_database.foobar", "connection_uri"),
 				),
 			},
 		},
@@ -0,0 +1,70 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/civo/civogo"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// ExportFindRestoreBackup exports findRestoreBackup for testing
+func ExportFindRestoreBackup(backups []civogo.DatabaseBackup, backup string, timestamp time.Time) (*civogo.DatabaseBackup, error) {
+	return findRestoreBackup(backups, backup, timestamp)
+}
+
+// ExportFindSourceBackup exports findSourceBackup for testing
+func ExportFindSourceBackup(apiClient *civogo.Client, sourceID, backup, timestamp, software string) (*civogo.DatabaseBackup, error) {
+	return findSourceBackup(apiClient, map[string]interface{}{"source_database_id": sourceID, "backup": backup, "timestamp": timestamp}, software)
+}
+
+// ExportRestoreDatabaseBackup exports restoreDatabaseBackup for testing
+func ExportRestoreDatabaseBackup(ctx context.Context, apiClient *civogo.Client, id, name string, backup *civogo.DatabaseBackup, timeout time.Duration) error {
+	return restoreDatabaseBackup(ctx, apiClient, id, name, backup, timeout)
+}
+
+// ExportCheckDatabaseUpdate exports checkDatabaseUpdate for testing
+func ExportCheckDatabaseUpdate(db *civogo.Database, size, version string) error {
+	return checkDatabaseUpdate(db, &databaseUpdateRequest{Size: size, SoftwareVersion: version})
+}
+
+// ExportPlanDatabaseChange exports planDatabaseChange for testing
+func ExportPlanDatabaseChange(engine, fromVersion, toVersion, fromSize, toSize string, versions map[string][]civogo.SupportedSoftwareVersion, sizes []civogo.InstanceSize) ([]string, []string, error) {
+	return planDatabaseChange(engine, fromVersion, toVersion, fromSize, toSize, versions, sizes)
+}
+
+// ExportCompareDatabaseVersions exports compareDatabaseVersions for testing
+func ExportCompareDatabaseVersions(a, b string) int {
+	return compareDatabaseVersions(a, b)
+}
+
+// ExportGrantStatements exports grantStatements for testing
+func ExportGrantStatements(engine, username, database string, privileges []string) []string {
+	return grantStatements(engine, username, database, privileges)
+}
+
+// ExportCreateUserStatement exports createUserStatement for testing
+func ExportCreateUserStatement(engine, username, password string) string {
+	return createUserStatement(engine, username, password)
+}
+
+// ExportRenderConnection exports the connection strings of databaseConnection for testing
+func ExportRenderConnection(engine, host string, port int, username, password, database, sslMode string) map[string]string {
+	c := databaseConnection{engine: engine, host: host, port: port, username: username, password: password, database: database, sslMode: sslMode}
+	return map[string]string{
+		"uri":       c.uri(),
+		"jdbc_url":  c.jdbcURL(),
+		"libpq_dsn": c.libpqDSN(),
+		"go_dsn":    c.goDSN(),
+	}
+}
+
+// ExportFetchCACertificate exports fetchCACertificate for testing
+func ExportFetchCACertificate(ctx context.Context, engine, address string) (string, error) {
+	return fetchCACertificate(ctx, engine, address)
+}
+
+// ExportReadCACertificate exports readCACertificate for testing
+func ExportReadCACertificate(ctx context.Context, fetch bool, name, engine, address string) (string, diag.Diagnostics) {
+	return readCACertificate(ctx, fetch, name, engine, address)
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/civo/civogo"
@@ -30,7 +33,7 @@ func ResourceDatabase() *schema.Resource {
 				Type:         schema.TypeString,
 				Required:     true,
 				ValidateFunc: validation.NoZeroValues,
-				Description:  "Size of the database",
+				Description:  "Size of the database, a larger size is changed in place",
 			},
 			"engine": {
 				Type:         schema.TypeString,
@@ -41,7 +44,7 @@ func ResourceDatabase() *schema.Resource {
 			"version": {
 				Type:         schema.TypeString,
 				Required:     true,
-				Description:  "The version of the database",
+				Description:  "The version of the database, an upgrade is done in place",
 				ValidateFunc: validation.NoZeroValues,
 			},
 			"network_id": {
@@ -105,6 +108,68 @@ func ResourceDatabase() *schema.Resource {
 				Computed:    true,
 				Description: "The private IPv4 address for the database",
 			},
+			"connection_uri": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The mysql:// or postgresql:// URI of the database with the credentials of the admin user, on its DNS endpoint",
+			},
+			"private_connection_uri": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The mysql:// or postgresql:// URI of the database with the credentials of the admin user, on its private IP",
+			},
+			"fetch_ca_certificate": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether to read the CA certificate of the database from the TLS handshake with the server into `ca_certificate`. The certificate isn't verified, it is trusted on first use",
+			},
+			"ca_certificate": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The PEM encoded CA certificate of the database, as presented by the server, when `fetch_ca_certificate` is set. Empty with a warning when the server can't be reached or doesn't present its CA",
+			},
+			"update_plan": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "How the changes of `size` and `version` are made, in place or by replacing the database, shown in the plan when they change and kept until the next change",
+			},
+			"restore_from": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				MaxItems:    1,
+				Description: "Build the database from a backup of another database, either a given backup or the latest backup taken at or before a timestamp. Changing it replaces the database",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"source_database_id": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ForceNew:     true,
+							ValidateFunc: utils.ValidateUUID,
+							Description:  "The ID of the database the backup was taken of",
+						},
+						"backup": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ForceNew:     true,
+							ExactlyOneOf: []string{"restore_from.0.backup", "restore_from.0.timestamp"},
+							Description:  "The name or ID of the backup to restore",
+						},
+						"timestamp": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ForceNew:     true,
+							ValidateFunc: validation.IsRFC3339Time,
+							ExactlyOneOf: []string{"restore_from.0.backup", "restore_from.0.timestamp"},
+							Description:  "An RFC 3339 timestamp, e.g. `2024-05-01T02:00:00Z`. The latest completed backup taken at or before it is restored, this isn't a point-in-time recovery",
+						},
+					},
+				},
+			},
 		},
 		CreateContext: resourceDatabaseCreate,
 		ReadContext:   resourceDatabaseRead,
@@ -118,7 +183,33 @@ func ResourceDatabase() *schema.Resource {
 			Update: schema.DefaultTimeout(30 * time.Minute),
 			Delete: schema.DefaultTimeout(30 * time.Minute),
 		},
+		CustomizeDiff: customizeDiffDatabase,
+	}
+}
+
+// databaseUpdateRequest is the body used to update a database. civogo's UpdateDatabaseRequest
+// has no size and version, so they are added to the same PUT /v2/databases/{id} request with the
+// names used by civogo's CreateDatabaseRequest and Database
+type databaseUpdateRequest struct {
+	Name            string `json:"name"`
+	Nodes           *int   `json:"nodes"`
+	FirewallID      string `json:"firewall_id"`
+	Size            string `json:"size,omitempty"`
+	SoftwareVersion string `json:"software_version,omitempty"`
+	Region          string `json:"region"`
+}
+
+// checkDatabaseUpdate returns an error when the size or the version sent in the update request
+// isn't the one of the database. Both are extra fields of the request, which the API can ignore,
+// and a database staying Ready after the request is taken as updated
+func checkDatabaseUpdate(db *civogo.Database, config *databaseUpdateRequest) error {
+	if config.Size != "" && db.Size != config.Size {
+		return fmt.Errorf("the size of the Database %s is still %s, the API didn't change it to %s", db.ID, db.Size, config.Size)
 	}
+	if config.SoftwareVersion != "" && db.SoftwareVersion != config.SoftwareVersion {
+		return fmt.Errorf("the version of the Database %s is still %s, the API didn't upgrade it to %s", db.ID, db.SoftwareVersion, config.SoftwareVersion)
+	}
+	return nil
 }
 
 // function to create a database
@@ -171,6 +262,16 @@ func resourceDatabaseCreate(ctx context.Context, d *schema.ResourceData, m inter
 		config.FirewallID = firewallID
 	}
 
+	// find the backup first, so nothing is created when it doesn't exist
+	var restoreBackup *civogo.DatabaseBackup
+	if v, ok := d.GetOk("restore_from"); ok && len(v.([]interface{})) > 0 && v.([]interface{})[0] != nil {
+		backup, err := findSourceBackup(apiClient, v.([]interface{})[0].(map[string]interface{}), config.Software)
+		if err != nil {
+			return diag.Errorf("[ERR] %s", err)
+		}
+		restoreBackup = backup
+	}
+
 	log.Printf("[INFO] creating the Database %s", d.Get("name").(string))
 	database, err := apiClient.NewDatabase(config)
 	if err != nil {
@@ -199,9 +300,99 @@ func resourceDatabaseCreate(ctx context.Context, d *schema.ResourceData, m inter
 		return diag.Errorf("error waiting for Database (%s) to be created: %s", d.Id(), err)
 	}
 
+	if restoreBackup != nil {
+		if err := restoreDatabaseBackup(ctx, apiClient, d.Id(), config.Name, restoreBackup, d.Timeout(schema.TimeoutCreate)); err != nil {
+			return diag.Errorf("[ERR] %s", err)
+		}
+	}
+
 	return resourceDatabaseRead(ctx, d, m)
 }
 
+// findRestoreBackup function to pick the backup to restore, by name or ID, or the latest
+// completed backup taken at or before the timestamp
+func findRestoreBackup(backups []civogo.DatabaseBackup, backup string, timestamp time.Time) (*civogo.DatabaseBackup, error) {
+	if backup != "" {
+		for i, b := range backups {
+			if b.ID == backup || b.Name == backup {
+				return &backups[i], nil
+			}
+		}
+		return nil, fmt.Errorf("the backup %s could not be found", backup)
+	}
+
+	var latest *civogo.DatabaseBackup
+	for i, b := range backups {
+		if !strings.EqualFold(b.Status, "completed") || b.CreatedAt.After(timestamp) {
+			continue
+		}
+		if latest == nil || b.CreatedAt.After(latest.CreatedAt) {
+			latest = &backups[i]
+		}
+	}
+	if latest == nil {
+		return nil, fmt.Errorf("no completed backup was taken at or before %s", timestamp.Format(time.RFC3339))
+	}
+	return latest, nil
+}
+
+// findSourceBackup function to find the backup of the source database of restore_from, by name
+// or ID or as the latest completed backup taken at or before the timestamp
+func findSourceBackup(apiClient *civogo.Client, restoreFrom map[string]interface{}, software string) (*civogo.DatabaseBackup, error) {
+	sourceID := restoreFrom["source_database_id"].(string)
+	backups, err := apiClient.ListDatabaseBackup(sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the backups of the database %s: %s", sourceID, err)
+	}
+
+	var timestamp time.Time
+	if v := restoreFrom["timestamp"].(string); v != "" {
+		timestamp, err = time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid restore_from timestamp: %s", err)
+		}
+	}
+
+	backup, err := findRestoreBackup(backups.Items, restoreFrom["backup"].(string), timestamp)
+	if err != nil {
+		return nil, err
+	}
+	if backup.Software != "" && !strings.EqualFold(backup.Software, software) {
+		return nil, fmt.Errorf("the backup %s is a %s backup and can't be restored into a %s database", backup.Name, backup.Software, software)
+	}
+	return backup, nil
+}
+
+// restoreDatabaseBackup function to restore a backup into the new database and wait until the
+// database is ready again
+func restoreDatabaseBackup(ctx context.Context, apiClient *civogo.Client, id, name string, backup *civogo.DatabaseBackup, timeout time.Duration) error {
+	log.Printf("[INFO] restoring the backup %s into the Database %s", backup.Name, id)
+	_, err := apiClient.RestoreDatabase(id, &civogo.RestoreDatabaseRequest{
+		Name:   fmt.Sprintf("%s-%s", name, backup.Name),
+		Backup: backup.Name,
+		Region: apiClient.Region,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to restore the backup %s into the Database %s: %s", backup.Name, id, err)
+	}
+
+	if err := waitForDatabaseReady(ctx, apiClient, id, []string{"Pending", "Restoring"}, timeout); err != nil {
+		return fmt.Errorf("error waiting for Database (%s) to be restored: %s", id, err)
+	}
+	return nil
+}
+
+// waitForDatabaseReady function to wait until the database is Ready again after a change
+func waitForDatabaseReady(ctx context.Context, apiClient *civogo.Client, id string, pending []string, timeout time.Duration) error {
+	return utils.WaitForTransition(ctx, fmt.Sprintf("the Database %s", id), "Ready", pending, []string{"Ready"}, timeout, func() (interface{}, string, error) {
+		resp, err := apiClient.GetDatabase(id)
+		if err != nil {
+			return 0, "", err
+		}
+		return resp, resp.Status, nil
+	})
+}
+
 // Function to Update the database
 func resourceDatabaseUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	apiClient := m.(*civogo.Client)
@@ -216,29 +407,53 @@ func resourceDatabaseUpdate(ctx context.Context, d *schema.ResourceData, m inter
 		return diag.Errorf("[ERR] failed to find Database: %s", err)
 	}
 
-	config := &civogo.UpdateDatabaseRequest{
-		Region: apiClient.Region,
-	}
+	if d.HasChanges("name", "nodes", "firewall_id", "size", "version") {
+		config := &databaseUpdateRequest{
+			Region: apiClient.Region,
+		}
 
-	if d.HasChange("nodes") {
-		nodes := d.Get("nodes").(int)
-		config.Nodes = &nodes
-	}
+		if d.HasChange("nodes") {
+			nodes := d.Get("nodes").(int)
+			config.Nodes = &nodes
+		}
 
-	if d.HasChange("name") {
-		name := d.Get("name").(string)
-		config.Name = name
-	}
+		if d.HasChange("name") {
+			name := d.Get("name").(string)
+			config.Name = name
+		}
 
-	if d.HasChange("firewall_id") {
-		firewallID := d.Get("firewall_id").(string)
-		config.FirewallID = firewallID
-	}
+		if d.HasChange("firewall_id") {
+			firewallID := d.Get("firewall_id").(string)
+			config.FirewallID = firewallID
+		}
 
-	log.Printf("[INFO] updating the Database %s", d.Id())
-	_, err = apiClient.UpdateDatabase(d.Id(), config)
-	if err != nil {
-		return diag.Errorf("[ERR] failed to update Database: %s", err)
+		// the plan has checked both are supported in place
+		if d.HasChange("size") {
+			config.Size = d.Get("size").(string)
+		}
+
+		if d.HasChange("version") {
+			config.SoftwareVersion = d.Get("version").(string)
+		}
+
+		log.Printf("[INFO] updating the Database %s", d.Id())
+		_, err = apiClient.SendPutRequest(fmt.Sprintf("/v2/databases/%s", d.Id()), config)
+		if err != nil {
+			return diag.Errorf("[ERR] failed to update Database: %s", err)
+		}
+
+		err = waitForDatabaseReady(ctx, apiClient, d.Id(), []string{"Pending", "Upgrading", "Resizing", "Updating"}, d.Timeout(schema.TimeoutUpdate))
+		if err != nil {
+			return diag.Errorf("error waiting for Database (%s) to be updated: %s", d.Id(), err)
+		}
+
+		resp, err := apiClient.GetDatabase(d.Id())
+		if err != nil {
+			return diag.Errorf("[ERR] failed to retrive the Database: %s", err)
+		}
+		if err := checkDatabaseUpdate(resp, config); err != nil {
+			return diag.Errorf("[ERR] failed to update Database: %s", err)
+		}
 	}
 
 	return resourceDatabaseRead(ctx, d, m)
@@ -279,7 +494,15 @@ func resourceDatabaseRead(ctx context.Context, d *schema.ResourceData, m interfa
 	d.Set("status", resp.Status)
 	d.Set("private_ipv4", resp.PrivateIPv4)
 
-	return nil
+	connectionURI, privateConnectionURI := databaseConnectionURIs(resp)
+	d.Set("connection_uri", connectionURI)
+	d.Set("private_connection_uri", privateConnectionURI)
+
+	connection := databaseConnection{engine: strings.ToLower(resp.Software), host: fmt.Sprintf("%s.db.civo.com", resp.ID), port: resp.Port}
+	caCertificate, diags := readCACertificate(ctx, d.Get("fetch_ca_certificate").(bool), resp.Name, connection.engine, connection.address())
+	d.Set("ca_certificate", caCertificate)
+
+	return diags
 }
 
 // Function to delete the database
@@ -299,3 +522,150 @@ func resourceDatabaseDelete(ctx context.Context, d *schema.ResourceData, m inter
 
 	return nil
 }
+
+// customizeDiffDatabase checks the changes of size and version against the available sizes and
+// versions, the changes that can't be made in place replace the database and update_plan shows
+// how each change is made
+func customizeDiffDatabase(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+
+	if oldEngine, newEngine := d.GetChange("engine"); !strings.EqualFold(oldEngine.(string), newEngine.(string)) {
+		return d.ForceNew("engine")
+	}
+
+	if !d.HasChanges("size", "version") || !d.NewValueKnown("size") || !d.NewValueKnown("version") {
+		return nil
+	}
+
+	apiClient, ok := meta.(*civogo.Client)
+	if !ok {
+		return nil
+	}
+	if region, ok := d.GetOk("region"); ok {
+		apiClient = utils.RegionalClient(apiClient, region.(string))
+	}
+
+	versions, err := apiClient.ListDBVersions()
+	if err != nil {
+		return fmt.Errorf("failed to get the available database versions: %w", err)
+	}
+	sizes, err := apiClient.ListInstanceSizes()
+	if err != nil {
+		return fmt.Errorf("failed to get the available sizes: %w", err)
+	}
+
+	oldVersion, newVersion := d.GetChange("version")
+	oldSize, newSize := d.GetChange("size")
+	replace, plan, err := planDatabaseChange(d.Get("engine").(string), oldVersion.(string), newVersion.(string), oldSize.(string), newSize.(string), versions, sizes)
+	if err != nil {
+		return err
+	}
+	if err := d.SetNew("update_plan", plan); err != nil {
+		return err
+	}
+	for _, key := range replace {
+		if err := d.ForceNew(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// planDatabaseChange function to check a change of version and size of a database. Upgrades of
+// the version and sizes with at least the same disk are made in place, downgrades and smaller
+// disks replace the database. The returned plan explains each change
+func planDatabaseChange(engine, fromVersion, toVersion, fromSize, toSize string, versions map[string][]civogo.SupportedSoftwareVersion, sizes []civogo.InstanceSize) ([]string, []string, error) {
+	var replace, plan []string
+
+	if fromVersion != toVersion {
+		var available []string
+		engines := []string{}
+		for name, engineVersions := range versions {
+			engines = append(engines, name)
+			if strings.EqualFold(name, engine) {
+				for _, v := range engineVersions {
+					available = append(available, v.SoftwareVersion)
+				}
+			}
+		}
+		if available == nil {
+			sort.Strings(engines)
+			return nil, nil, fmt.Errorf("the engine %s has no available versions, available engines: %s", engine, strings.Join(engines, ", "))
+		}
+
+		found := false
+		for _, v := range available {
+			found = found || v == toVersion
+		}
+		if !found {
+			return nil, nil, fmt.Errorf("the version %s of %s is not available, available versions: %s", toVersion, engine, strings.Join(available, ", "))
+		}
+
+		if compareDatabaseVersions(toVersion, fromVersion) < 0 {
+			replace = append(replace, "version")
+			plan = append(plan, fmt.Sprintf("downgrading the version from %s to %s can't be done in place, the database will be replaced", fromVersion, toVersion))
+		} else {
+			plan = append(plan, fmt.Sprintf("the version is upgraded in place from %s to %s", fromVersion, toVersion))
+		}
+	}
+
+	if fromSize != toSize {
+		var current, target *civogo.InstanceSize
+		names := []string{}
+		for i, size := range sizes {
+			if !strings.EqualFold(size.Type, "database") {
+				continue
+			}
+			if size.Selectable {
+				names = append(names, size.Name)
+			}
+			if size.Name == fromSize {
+				current = &sizes[i]
+			}
+			if size.Name == toSize && size.Selectable {
+				target = &sizes[i]
+			}
+		}
+		if target == nil {
+			return nil, nil, fmt.Errorf("the size %s is not available for databases, available sizes: %s", toSize, strings.Join(names, ", "))
+		}
+
+		if current != nil && target.DiskGigabytes < current.DiskGigabytes {
+			replace = append(replace, "size")
+			plan = append(plan, fmt.Sprintf("the %d GB disk of the size %s is smaller than the %d GB disk of %s, the database will be replaced", target.DiskGigabytes, toSize, current.DiskGigabytes, fromSize))
+		} else {
+			plan = append(plan, fmt.Sprintf("the size is changed in place from %s to %s", fromSize, toSize))
+		}
+	}
+
+	return replace, plan, nil
+}
+
+// compareDatabaseVersions function to compare two versions like 8.0 or 16 part by part, it returns
+// -1, 0 or 1 as a is older, the same or newer than b
+func compareDatabaseVersions(a, b string) int {
+	partsA, partsB := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(partsA) || i < len(partsB); i++ {
+		var partA, partB string
+		if i < len(partsA) {
+			partA = partsA[i]
+		}
+		if i < len(partsB) {
+			partB = partsB[i]
+		}
+
+		numberA, errA := strconv.Atoi(partA)
+		numberB, errB := strconv.Atoi(partB)
+		switch {
+		case partA == partB:
+			continue
+		case errA == nil && errB == nil && numberA < numberB, (errA != nil || errB != nil) && partA < partB:
+			return -1
+		case errA == nil && errB == nil && numberA > numberB, (errA != nil || errB != nil) && partA > partB:
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,328 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/civo/civogo"
+	"github.com/civo/terraform-provider-civo/internal/utils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// databaseBackupRequest is the body used to create and update backups, civogo doesn't send
+// the number of scheduled backups to keep
+type databaseBackupRequest struct {
+	Name     string `json:"name"`
+	Schedule string `json:"schedule,omitempty"`
+	Count    int    `json:"count,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Region   string `json:"region"`
+}
+
+// databaseBackupResponse is a backup as returned by the API, civogo doesn't decode the number of
+// scheduled backups to keep
+type databaseBackupResponse struct {
+	civogo.DatabaseBackup
+	Count int `json:"count"`
+}
+
+// ResourceDatabaseBackup The database backup resource represents a manual backup of a database,
+// or the schedule of its automatic backups
+func ResourceDatabaseBackup() *schema.Resource {
+	return &schema.Resource{
+		Description: "Provides a Civo database backup, either a manual backup taken once or the schedule of the automatic backups of the database.",
+		Schema: map[string]*schema.Schema{
+			"database_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: utils.ValidateUUID,
+				Description:  "The ID of the database to back up",
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: utils.ValidateName,
+				Description:  "The name of the backup, it can only be changed for scheduled backups",
+			},
+			"schedule": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateBackupSchedule,
+				Description:  "A cron expression for scheduled backups, e.g. `0 2 * * *` for every day at 2am. A manual backup is taken once when it is not set",
+			},
+			"retention": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				RequiredWith: []string{"schedule"},
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The number of scheduled backups to keep, the oldest ones are removed by the schedule",
+			},
+			"region": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				Description:      "The region of the database, if not declare we use the region in declared in the provider",
+				DiffSuppressFunc: utils.IgnoreCaseDiff,
+			},
+			// Computed resource
+			"is_scheduled": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether this is the schedule of the automatic backups",
+			},
+			"database_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the database",
+			},
+			"software": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The engine of the database",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The status of the backup",
+			},
+			"created_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The time the backup was created",
+			},
+		},
+		CreateContext: resourceDatabaseBackupCreate,
+		ReadContext:   resourceDatabaseBackupRead,
+		UpdateContext: resourceDatabaseBackupUpdate,
+		DeleteContext: resourceDatabaseBackupDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceDatabaseBackupImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+		},
+		CustomizeDiff: customizeDiffDatabaseBackup,
+	}
+}
+
+// function to create the backup
+func resourceDatabaseBackupCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*civogo.Client)
+
+	// overwrite the region if is defined in the datasource
+	if region, ok := d.GetOk("region"); ok {
+		apiClient = utils.RegionalClient(apiClient, region.(string))
+	}
+
+	databaseID := d.Get("database_id").(string)
+	config := &databaseBackupRequest{
+		Name:     d.Get("name").(string),
+		Schedule: d.Get("schedule").(string),
+		Count:    d.Get("retention").(int),
+		Region:   apiClient.Region,
+	}
+	if config.Schedule == "" {
+		config.Type = "manual"
+	}
+
+	log.Printf("[INFO] creating the backup %s of the database %s", config.Name, databaseID)
+	body, err := apiClient.SendPostRequest(fmt.Sprintf("/v2/databases/%s/backups", databaseID), config)
+	if err != nil {
+		return diag.Errorf("[ERR] failed to create the backup of the database %s: %s", databaseID, err)
+	}
+	backup := &civogo.DatabaseBackup{}
+	if err := json.NewDecoder(bytes.NewReader(body)).Decode(backup); err != nil {
+		return diag.Errorf("[ERR] failed to decode the backup of the database %s: %s", databaseID, err)
+	}
+
+	d.SetId(backup.ID)
+
+	// a schedule has nothing to wait for, the backups are taken later
+	if config.Schedule == "" {
+		createStateConf := &retry.StateChangeConf{
+			Pending: []string{"", "pending", "running", "in_progress"},
+			Target:  []string{"completed"},
+			Refresh: func() (interface{}, string, error) {
+				resp, err := apiClient.GetDatabaseBackup(databaseID, d.Id())
+				if err != nil {
+					return 0, "", err
+				}
+				status := strings.ToLower(resp.Status)
+				if status == "failed" || status == "error" {
+					return resp, status, fmt.Errorf("the backup failed")
+				}
+				return resp, status, nil
+			},
+			Timeout:        d.Timeout(schema.TimeoutCreate),
+			Delay:          3 * time.Second,
+			MinTimeout:     3 * time.Second,
+			NotFoundChecks: 10,
+		}
+		if _, err := createStateConf.WaitForStateContext(ctx); err != nil {
+			return diag.Errorf("error waiting for database backup (%s) to be created: %s", d.Id(), err)
+		}
+	}
+
+	return resourceDatabaseBackupRead(ctx, d, m)
+}
+
+// function to read the backup
+func resourceDatabaseBackupRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*civogo.Client)
+
+	// overwrite the region if is defined in the datasource
+	if region, ok := d.GetOk("region"); ok {
+		apiClient = utils.RegionalClient(apiClient, region.(string))
+	}
+
+	databaseID := d.Get("database_id").(string)
+
+	log.Printf("[INFO] retrieving the backup %s of the database %s", d.Id(), databaseID)
+	body, err := apiClient.SendGetRequest(fmt.Sprintf("/v2/databases/%s/backups/%s", databaseID, d.Id()))
+	if err != nil {
+		if databaseBackupNotFound(apiClient, databaseID, d.Id()) {
+			log.Printf("[INFO] database backup %s not found", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("[ERR] failed retrieving the database backup: %s", err)
+	}
+	resp := &databaseBackupResponse{}
+	if err := json.NewDecoder(bytes.NewReader(body)).Decode(resp); err != nil {
+		return diag.Errorf("[ERR] failed to decode the database backup: %s", err)
+	}
+
+	d.Set("name", resp.Name)
+	d.Set("database_id", resp.DatabaseID)
+	d.Set("database_name", resp.DatabaseName)
+	d.Set("software", resp.Software)
+	d.Set("status", resp.Status)
+	d.Set("is_scheduled", resp.IsScheduled)
+	d.Set("schedule", resp.Schedule)
+	// the count is only returned for the schedule, the configured retention is kept otherwise
+	if resp.IsScheduled && resp.Count > 0 {
+		d.Set("retention", resp.Count)
+	}
+	d.Set("region", apiClient.Region)
+	d.Set("created_at", resp.CreatedAt.UTC().Format(time.RFC3339))
+
+	return nil
+}
+
+// function to update the schedule of the backups
+func resourceDatabaseBackupUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*civogo.Client)
+
+	// overwrite the region if is defined in the datasource
+	if region, ok := d.GetOk("region"); ok {
+		apiClient = utils.RegionalClient(apiClient, region.(string))
+	}
+
+	databaseID := d.Get("database_id").(string)
+	if d.HasChanges("name", "schedule", "retention") {
+		config := &databaseBackupRequest{
+			Name:     d.Get("name").(string),
+			Schedule: d.Get("schedule").(string),
+			Count:    d.Get("retention").(int),
+			Region:   apiClient.Region,
+		}
+
+		log.Printf("[INFO] updating the backup schedule of the database %s", databaseID)
+		if _, err := apiClient.SendPutRequest(fmt.Sprintf("/v2/databases/%s/backups", databaseID), config); err != nil {
+			return diag.Errorf("[ERR] failed to update the backup schedule of the database %s: %s", databaseID, err)
+		}
+	}
+
+	return resourceDatabaseBackupRead(ctx, d, m)
+}
+
+// function to delete the backup
+func resourceDatabaseBackupDelete(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*civogo.Client)
+
+	// overwrite the region if is defined in the datasource
+	if region, ok := d.GetOk("region"); ok {
+		apiClient = utils.RegionalClient(apiClient, region.(string))
+	}
+
+	databaseID := d.Get("database_id").(string)
+
+	log.Printf("[INFO] deleting the backup %s of the database %s", d.Id(), databaseID)
+	if _, err := apiClient.DeleteDatabaseBackup(databaseID, d.Id()); err != nil {
+		if databaseBackupNotFound(apiClient, databaseID, d.Id()) {
+			return nil
+		}
+		return diag.Errorf("[ERR] an error occurred while trying to delete the database backup %s: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+// custom import to find the database of the backup, the ID is database_id:backup_id
+func resourceDatabaseBackupImport(_ context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+	databaseID, backupID, err := utils.ResourceCommonParseID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(backupID)
+	d.Set("database_id", databaseID)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// customizeDiffDatabaseBackup replaces manual backups when they are renamed, and the backup
+// when it changes between manual and scheduled
+func customizeDiffDatabaseBackup(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+
+	oldSchedule, newSchedule := d.GetChange("schedule")
+	if (oldSchedule.(string) == "") != (newSchedule.(string) == "") {
+		return d.ForceNew("schedule")
+	}
+	if newSchedule.(string) == "" && d.HasChange("name") {
+		return d.ForceNew("name")
+	}
+	return nil
+}
+
+// validateBackupSchedule checks the schedule is a cron expression with five fields
+func validateBackupSchedule(v interface{}, k string) (ws []string, es []error) {
+	value, ok := v.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected %s to be a string", k)}
+	}
+	if fields := strings.Fields(value); len(fields) != 5 {
+		es = append(es, fmt.Errorf("%s must be a cron expression with five fields (minute, hour, day of month, month and day of week), got: %q", k, value))
+	}
+	return ws, es
+}
+
+// databaseNotFound reports whether the database doesn't exist anymore. civogo has no error of its
+// own for a missing database, so it is looked up in the list of databases
+func databaseNotFound(apiClient *civogo.Client, databaseID string) bool {
+	_, err := apiClient.FindDatabase(databaseID)
+	return errors.Is(err, civogo.ZeroMatchesError)
+}
+
+// databaseBackupNotFound reports whether the backup, or its database, doesn't exist anymore
+func databaseBackupNotFound(apiClient *civogo.Client, databaseID, backupID string) bool {
+	if databaseNotFound(apiClient, databaseID) {
+		return true
+	}
+	_, err := apiClient.FindDatabaseBackup(databaseID, backupID)
+	return errors.Is(err, civogo.ZeroMatchesError)
+}
@@ -0,0 +1,284 @@
+package database_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/civo/civogo"
+	"github.com/civo/terraform-provider-civo/civo/acceptance"
+	"github.com/civo/terraform-provider-civo/civo/database"
+	"github.com/civo/terraform-provider-civo/internal/mockapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// TestAccCivoDatabaseBackup_basic tests a manual backup is taken and restored into a new database
+func TestAccCivoDatabaseBackup_basic(t *testing.T) {
+	resName := "civo_database_backup.foobar"
+	var name = acctest.RandomWithPrefix("tf-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { acceptance.TestAccPreCheck(t) },
+		Providers:    acceptance.TestAccProviders,
+		CheckDestroy: CivoDatabaseBackupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: CivoDatabaseBackupConfigBasic(name, false),
+				Check: resource.ComposeTestCheckFunc(
+					CivoDatabaseBackupResourceExists(resName),
+					resource.TestCheckResourceAttr(resName, "name", name),
+					resource.TestCheckResourceAttr(resName, "is_scheduled", "false"),
+					resource.TestCheckResourceAttrPair(resName, "database_id", "civo_database.foobar", "id"),
+					resource.TestCheckResourceAttr("data.civo_database_backup.foobar", "backups.#", "1"),
+				),
+			},
+			{
+				Config: CivoDatabaseBackupConfigBasic(name, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("civo_database.restored", "restore_from.0.backup", name),
+					resource.TestCheckResourceAttrPair("civo_database.restored", "restore_from.0.source_database_id", "civo_database.foobar", "id"),
+					resource.TestCheckResourceAttr("civo_database.restored", "status", "Ready"),
+				),
+			},
+			{
+				ResourceName:      resName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					rs := s.RootModule().Resources[resName]
+					return fmt.Sprintf("%s:%s", rs.Primary.Attributes["database_id"], rs.Primary.ID), nil
+				},
+			},
+		},
+	})
+}
+
+// TestAccCivoDatabaseBackup_scheduled tests the schedule of the backups can be changed in place
+func TestAccCivoDatabaseBackup_scheduled(t *testing.T) {
+	resName := "civo_database_backup.foobar"
+	var name = acctest.RandomWithPrefix("tf-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { acceptance.TestAccPreCheck(t) },
+		Providers:    acceptance.TestAccProviders,
+		CheckDestroy: CivoDatabaseBackupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: CivoDatabaseBackupConfigScheduled(name, "0 2 * * *"),
+				Check: resource.ComposeTestCheckFunc(
+					CivoDatabaseBackupResourceExists(resName),
+					resource.TestCheckResourceAttr(resName, "schedule", "0 2 * * *"),
+					resource.TestCheckResourceAttr(resName, "retention", "7"),
+					resource.TestCheckResourceAttr(resName, "is_scheduled", "true"),
+				),
+			},
+			{
+				Config: CivoDatabaseBackupConfigScheduled(name, "30 3 * * *"),
+				Check: resource.ComposeTestCheckFunc(
+					CivoDatabaseBackupResourceExists(resName),
+					resource.TestCheckResourceAttr(resName, "schedule", "30 3 * * *"),
+				),
+			},
+		},
+	})
+}
+
+// CivoDatabaseBackupResourceExists queries the API for the backup
+func CivoDatabaseBackupResourceExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("not found: %s", n)
+		}
+
+		client := acceptance.TestAccProvider.Meta().(*civogo.Client)
+		if _, err := client.GetDatabaseBackup(rs.Primary.Attributes["database_id"], rs.Primary.ID); err != nil {
+			return fmt.Errorf("database backup not found: (%s) %s", rs.Primary.ID, err)
+		}
+		return nil
+	}
+}
+
+// CivoDatabaseBackupDestroy checks the backups created during the test are gone
+func CivoDatabaseBackupDestroy(s *terraform.State) error {
+	client := acceptance.TestAccProvider.Meta().(*civogo.Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "civo_database_backup" {
+			continue
+		}
+
+		_, err := client.GetDatabaseBackup(rs.Primary.Attributes["database_id"], rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("database backup still exists")
+		}
+	}
+
+	return nil
+}
+
+// CivoDatabaseBackupConfigBasic backs up a database, and restores the backup into a new
+// database with restore
+func CivoDatabaseBackupConfigBasic(name string, restore bool) string {
+	restored := ""
+	if restore {
+		restored = fmt.Sprintf(`
+resource "civo_database" "restored" {
+	name = "%[1]s-restored"
+	size = "g3.db.small"
+	engine = "PostgreSQL"
+	version = "16"
+	nodes = 1
+
+	restore_from {
+		source_database_id = civo_database.foobar.id
+		backup = civo_database_backup.foobar.name
+	}
+}`, name)
+	}
+
+	return fmt.Sprintf(`
+resource "civo_database" "foobar" {
+	name = "%[1]s"
+	size = "g3.db.small"
+	engine = "PostgreSQL"
+	version = "16"
+	nodes = 1
+}
+
+resource "civo_database_backup" "foobar" {
+	database_id = civo_database.foobar.id
+	name = "%[1]s"
+}
+
+data "civo_database_backup" "foobar" {
+	database_id = civo_database.foobar.id
+	depends_on = [civo_database_backup.foobar]
+}
+%[2]s`, name, restored)
+}
+
+// CivoDatabaseBackupConfigScheduled schedules the backups of a database
+func CivoDatabaseBackupConfigScheduled(name, schedule string) string {
+	return fmt.Sprintf(`
+resource "civo_database" "foobar" {
+	name = "%[1]s"
+	size = "g3.db.small"
+	engine = "PostgreSQL"
+	version = "16"
+	nodes = 1
+}
+
+resource "civo_database_backup" "foobar" {
+	database_id = civo_database.foobar.id
+	name = "%[1]s"
+	schedule = "%[2]s"
+	retention = 7
+}`, name, schedule)
+}
+
+func TestRestoreDatabaseBackup(t *testing.T) {
+	s := mockapi.NewServer()
+	defer s.Close()
+	client, err := s.Client(mockapi.DefaultRegion)
+	if err != nil {
+		t.Fatalf("failed to build the client: %s", err)
+	}
+
+	source, err := client.NewDatabase(&civogo.CreateDatabaseRequest{Name: "app", Size: "g3.db.small", Software: "PostgreSQL", SoftwareVersion: "16", Nodes: 1})
+	if err != nil {
+		t.Fatalf("NewDatabase: %s", err)
+	}
+	if _, err := client.CreateDatabaseBackup(source.ID, &civogo.DatabaseBackupCreateRequest{Name: "nightly", Type: "manual"}); err != nil {
+		t.Fatalf("CreateDatabaseBackup: %s", err)
+	}
+
+	// the backup is found before the new database is created
+	if _, err := database.ExportFindSourceBackup(client, source.ID, "weekly", "", "PostgreSQL"); err == nil {
+		t.Fatal("expected an unknown backup not to be found")
+	}
+	if _, err := database.ExportFindSourceBackup(client, source.ID, "nightly", "", "MySQL"); err == nil || !strings.Contains(err.Error(), "can't be restored into a MySQL database") {
+		t.Fatalf("expected a PostgreSQL backup not to be restored into a MySQL database, got: %v", err)
+	}
+	backup, err := database.ExportFindSourceBackup(client, source.ID, "", time.Now().Add(time.Minute).Format(time.RFC3339), "postgresql")
+	if err != nil {
+		t.Fatalf("failed to find the backup: %s", err)
+	}
+	if backup.Name != "nightly" {
+		t.Fatalf("expected the nightly backup, got: %s", backup.Name)
+	}
+
+	db, err := client.NewDatabase(&civogo.CreateDatabaseRequest{Name: "app-restored", Size: "g3.db.small", Software: "PostgreSQL", SoftwareVersion: "16", Nodes: 1})
+	if err != nil {
+		t.Fatalf("NewDatabase: %s", err)
+	}
+
+	// the restore is seen in progress for one read, it is waited for until the database is Ready again
+	s.SetPendingPolls(1)
+	path := "/v2/databases/" + db.ID
+	before := s.RequestCount(http.MethodGet, path)
+	if err := database.ExportRestoreDatabaseBackup(context.Background(), client, db.ID, "app-restored", backup, time.Minute); err != nil {
+		t.Fatalf("failed to restore the backup: %s", err)
+	}
+	if count := s.RequestCount(http.MethodPost, path+"/restore"); count != 1 {
+		t.Fatalf("expected the backup to be restored once into the new database, got: %d", count)
+	}
+	if count := s.RequestCount(http.MethodPost, "/v2/databases/"+source.ID+"/restore"); count != 0 {
+		t.Fatalf("expected the source database to be left as it is, got %d restores", count)
+	}
+	if reads := s.RequestCount(http.MethodGet, path) - before; reads < 2 {
+		t.Fatalf("expected the restore to be waited for, got %d reads", reads)
+	}
+}
+
+func TestDatabaseBackupRead(t *testing.T) {
+	s := mockapi.NewServer()
+	defer s.Close()
+	client, err := s.Client(mockapi.DefaultRegion)
+	if err != nil {
+		t.Fatalf("failed to build the client: %s", err)
+	}
+
+	db, err := client.NewDatabase(&civogo.CreateDatabaseRequest{Name: "app", Size: "g3.db.small", Software: "PostgreSQL", SoftwareVersion: "16", Nodes: 1})
+	if err != nil {
+		t.Fatalf("NewDatabase: %s", err)
+	}
+
+	r := database.ResourceDatabaseBackup()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"database_id": db.ID,
+		"name":        "nightly",
+		"schedule":    "0 2 * * *",
+		"retention":   7,
+	})
+	if diags := r.CreateContext(context.Background(), d, client); diags.HasError() {
+		t.Fatalf("failed to create the backup: %v", diags)
+	}
+
+	// an imported schedule gets its retention from the API
+	imported := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"database_id": db.ID})
+	imported.SetId(d.Id())
+	if diags := r.ReadContext(context.Background(), imported, client); diags.HasError() {
+		t.Fatalf("failed to read the backup: %v", diags)
+	}
+	if retention := imported.Get("retention").(int); retention != 7 {
+		t.Fatalf("expected the retention to be 7, got: %d", retention)
+	}
+
+	// the backup is gone with its database
+	if _, err := client.DeleteDatabase(db.ID); err != nil {
+		t.Fatalf("DeleteDatabase: %s", err)
+	}
+	if diags := r.ReadContext(context.Background(), imported, client); diags.HasError() {
+		t.Fatalf("failed to read the backup: %v", diags)
+	}
+	if imported.Id() != "" {
+		t.Fatal("expected the backup to be removed from the state")
+	}
+}
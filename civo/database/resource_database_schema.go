@@ -0,0 +1,201 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/civo/civogo"
+	"github.com/civo/terraform-provider-civo/internal/utils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// ResourceDatabaseSchema The database schema resource represents a logical database inside
+// a managed database, created by connecting to its endpoint
+func ResourceDatabaseSchema() *schema.Resource {
+	return &schema.Resource{
+		Description: "Provides a logical database inside a Civo managed database, e.g. one per app. The provider connects to the endpoint of the database with its admin user to manage it.",
+		Schema: map[string]*schema.Schema{
+			"database_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: utils.ValidateUUID,
+				Description:  "The ID of the managed database",
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateFunc: validation.All(
+					validation.StringLenBetween(1, 63),
+					validation.StringMatch(sqlIdentifier, "must start with a letter or an underscore and only contain letters, digits and underscores"),
+					validation.StringNotInSlice(reservedDatabaseNames, true),
+				),
+				Description: "The name of the database",
+			},
+			"host": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The address to connect to instead of the public endpoint of the database, e.g. its `private_ipv4` when Terraform runs in the same network",
+			},
+			"sslmode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "prefer",
+				ValidateFunc: validation.StringInSlice([]string{"disable", "prefer", "require", "verify-full"}, false),
+				Description:  "Whether the connection uses TLS, one of `disable`, `prefer`, `require` or `verify-full`",
+			},
+			"region": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				Description:      "The region of the database, if not declare we use the region in declared in the provider",
+				DiffSuppressFunc: utils.IgnoreCaseDiff,
+			},
+			// Computed resource
+			"engine": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The engine of the managed database",
+			},
+		},
+		CreateContext: resourceDatabaseSchemaCreate,
+		ReadContext:   resourceDatabaseSchemaRead,
+		UpdateContext: resourceDatabaseSchemaUpdate,
+		DeleteContext: resourceDatabaseSchemaDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceDatabaseSchemaImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+	}
+}
+
+// function to create the database
+func resourceDatabaseSchemaCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*civogo.Client)
+
+	// overwrite the region if is defined in the datasource
+	if region, ok := d.GetOk("region"); ok {
+		apiClient = utils.RegionalClient(apiClient, region.(string))
+	}
+
+	databaseID := d.Get("database_id").(string)
+	name := d.Get("name").(string)
+
+	ctx, cancel := context.WithTimeout(ctx, d.Timeout(schema.TimeoutCreate))
+	defer cancel()
+
+	conn, err := connectDatabase(ctx, apiClient, databaseID, d.Get("host").(string), d.Get("sslmode").(string), "")
+	if err != nil {
+		return diag.Errorf("[ERR] failed to connect to the database %s: %s", databaseID, err)
+	}
+	defer conn.Close()
+
+	log.Printf("[INFO] creating the database %s in the database %s", name, databaseID)
+	if _, err := conn.ExecContext(ctx, createSchemaStatement(conn.engine, name)); err != nil {
+		return diag.Errorf("[ERR] failed to create the database %s: %s", name, err)
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s", databaseID, name))
+
+	return resourceDatabaseSchemaRead(ctx, d, m)
+}
+
+// function to read the database
+func resourceDatabaseSchemaRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*civogo.Client)
+
+	// overwrite the region if is defined in the datasource
+	if region, ok := d.GetOk("region"); ok {
+		apiClient = utils.RegionalClient(apiClient, region.(string))
+	}
+
+	databaseID := d.Get("database_id").(string)
+	name := d.Get("name").(string)
+
+	log.Printf("[INFO] retrieving the database %s of the database %s", name, databaseID)
+	conn, err := connectDatabase(ctx, apiClient, databaseID, d.Get("host").(string), d.Get("sslmode").(string), "")
+	if err != nil {
+		if databaseNotFound(apiClient, databaseID) {
+			log.Printf("[INFO] database %s not found", databaseID)
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("[ERR] failed to connect to the database %s: %s", databaseID, err)
+	}
+	defer conn.Close()
+
+	exists, err := conn.exists(ctx, schemaExistsQuery(conn.engine), name)
+	if err != nil {
+		return diag.Errorf("[ERR] failed retrieving the database %s: %s", name, err)
+	}
+	if !exists {
+		log.Printf("[INFO] database %s not found", name)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("engine", conn.engine)
+	d.Set("region", apiClient.Region)
+
+	return nil
+}
+
+// function to update the database, only the connection settings can change
+func resourceDatabaseSchemaUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return resourceDatabaseSchemaRead(ctx, d, m)
+}
+
+// function to delete the database
+func resourceDatabaseSchemaDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*civogo.Client)
+
+	// overwrite the region if is defined in the datasource
+	if region, ok := d.GetOk("region"); ok {
+		apiClient = utils.RegionalClient(apiClient, region.(string))
+	}
+
+	databaseID := d.Get("database_id").(string)
+	name := d.Get("name").(string)
+
+	ctx, cancel := context.WithTimeout(ctx, d.Timeout(schema.TimeoutDelete))
+	defer cancel()
+
+	conn, err := connectDatabase(ctx, apiClient, databaseID, d.Get("host").(string), d.Get("sslmode").(string), "")
+	if err != nil {
+		if databaseNotFound(apiClient, databaseID) {
+			return nil
+		}
+		return diag.Errorf("[ERR] failed to connect to the database %s: %s", databaseID, err)
+	}
+	defer conn.Close()
+
+	log.Printf("[INFO] deleting the database %s of the database %s", name, databaseID)
+	if _, err := conn.ExecContext(ctx, dropSchemaStatement(conn.engine, name)); err != nil {
+		return diag.Errorf("[ERR] an error occurred while trying to delete the database %s: %s", name, err)
+	}
+
+	return nil
+}
+
+// custom import to split the ID, which is database_id:name
+func resourceDatabaseSchemaImport(_ context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+	databaseID, name, err := utils.ResourceCommonParseID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	d.Set("database_id", databaseID)
+	d.Set("name", name)
+	d.Set("sslmode", "prefer")
+
+	return []*schema.ResourceData{d}, nil
+}
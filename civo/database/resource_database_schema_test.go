@@ -0,0 +1,57 @@
+package database_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/civo/terraform-provider-civo/civo/acceptance"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+// TestAccCivoDatabaseSchema_basic tests a logical database is created in a MySQL database
+func TestAccCivoDatabaseSchema_basic(t *testing.T) {
+	if acceptance.MockAPI != nil {
+		t.Skip("the fake API has no database server to connect to, TestDatabaseUserLocal covers it")
+	}
+	resName := "civo_database_schema.app"
+	var name = acctest.RandomWithPrefix("tf-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { acceptance.TestAccPreCheck(t) },
+		Providers:    acceptance.TestAccProviders,
+		CheckDestroy: CivoDatabaseDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: CivoDatabaseSchemaConfigBasic(name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "name", "app"),
+					resource.TestCheckResourceAttr(resName, "engine", "mysql"),
+					resource.TestCheckResourceAttrPair(resName, "database_id", "civo_database.foobar", "id"),
+				),
+			},
+			{
+				ResourceName:      resName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// CivoDatabaseSchemaConfigBasic is used to configure a MySQL database with a logical database
+func CivoDatabaseSchemaConfigBasic(name string) string {
+	return fmt.Sprintf(`
+resource "civo_database" "foobar" {
+	name = "%s"
+	size = "g3.db.small"
+	engine = "MySQL"
+	version = "8.0"
+	nodes = 1
+}
+
+resource "civo_database_schema" "app" {
+	database_id = civo_database.foobar.id
+	name = "app"
+}`, name)
+}
@@ -1,11 +1,17 @@
 package database_test
 
 import (
+	"context"
 	"fmt"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/civo/civogo"
 	"github.com/civo/terraform-provider-civo/civo/acceptance"
+	"github.com/civo/terraform-provider-civo/civo/database"
+	"github.com/civo/terraform-provider-civo/internal/mockapi"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
@@ -39,6 +45,7 @@ func TestAccCivoDatabase_basic(t *testing.T) {
 					resource.TestCheckResourceAttrSet(resName, "nodes"),
 					resource.TestCheckResourceAttrSet(resName, "engine"),
 					resource.TestCheckResourceAttrSet(resName, "version"),
+					resource.TestCheckResourceAttrSet(resName, "connection_uri"),
 					resource.TestCheckResourceAttr(resName, "status", "Ready"),
 				),
 			},
@@ -82,6 +89,282 @@ func TestAccCivoDatabase_update(t *testing.T) {
 	})
 }
 
+// TestAccCivoDatabase_upgrade tests the version and the size are changed without replacing the database
+func TestAccCivoDatabase_upgrade(t *testing.T) {
+	var database, upgraded civogo.Database
+
+	resName := "civo_database.foobar"
+	var databaseName = acctest.RandomWithPrefix("tf-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { acceptance.TestAccPreCheck(t) },
+		Providers:    acceptance.TestAccProviders,
+		CheckDestroy: CivoDatabaseDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: CivoDatabaseConfigVersion(databaseName, "g3.db.small", "14"),
+				Check: resource.ComposeTestCheckFunc(
+					CivoDatabaseResourceExists(resName, &database),
+					resource.TestCheckResourceAttr(resName, "version", "14"),
+				),
+			},
+			{
+				Config: CivoDatabaseConfigVersion(databaseName, "g3.db.medium", "16"),
+				Check: resource.ComposeTestCheckFunc(
+					CivoDatabaseResourceExists(resName, &upgraded),
+					resource.TestCheckResourceAttr(resName, "version", "16"),
+					resource.TestCheckResourceAttr(resName, "size", "g3.db.medium"),
+					resource.TestCheckResourceAttr(resName, "status", "Ready"),
+					func(_ *terraform.State) error {
+						if upgraded.ID != database.ID {
+							return fmt.Errorf("expected the database to be upgraded in place, it was replaced")
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestPlanDatabaseChange(t *testing.T) {
+	versions := map[string][]civogo.SupportedSoftwareVersion{
+		"mysql":      {{SoftwareVersion: "8.0", Default: true}},
+		"postgresql": {{SoftwareVersion: "14"}, {SoftwareVersion: "15"}, {SoftwareVersion: "16", Default: true}},
+	}
+	sizes := []civogo.InstanceSize{
+		{Type: "Database", Name: "g3.db.small", DiskGigabytes: 40, Selectable: true},
+		{Type: "Database", Name: "g3.db.medium", DiskGigabytes: 80, Selectable: true},
+		{Type: "Instance", Name: "g3.large", DiskGigabytes: 100, Selectable: true},
+	}
+
+	cases := []struct {
+		name                   string
+		engine                 string
+		fromVersion, toVersion string
+		fromSize, toSize       string
+		replace                []string
+		wantErr                bool
+	}{
+		{name: "Version upgrade", engine: "PostgreSQL", fromVersion: "14", toVersion: "16", fromSize: "g3.db.small", toSize: "g3.db.small"},
+		{name: "Version downgrade", engine: "PostgreSQL", fromVersion: "16", toVersion: "15", fromSize: "g3.db.small", toSize: "g3.db.small", replace: []string{"version"}},
+		{name: "Unknown version", engine: "PostgreSQL", fromVersion: "14", toVersion: "17", fromSize: "g3.db.small", toSize: "g3.db.small", wantErr: true},
+		{name: "Unknown engine", engine: "redis", fromVersion: "6", toVersion: "7", fromSize: "g3.db.small", toSize: "g3.db.small", wantErr: true},
+		{name: "Larger size", engine: "MySQL", fromVersion: "8.0", toVersion: "8.0", fromSize: "g3.db.small", toSize: "g3.db.medium"},
+		{name: "Smaller disk", engine: "MySQL", fromVersion: "8.0", toVersion: "8.0", fromSize: "g3.db.medium", toSize: "g3.db.small", replace: []string{"size"}},
+		{name: "Size of another type", engine: "MySQL", fromVersion: "8.0", toVersion: "8.0", fromSize: "g3.db.small", toSize: "g3.large", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			replace, plan, err := database.ExportPlanDatabaseChange(tc.engine, tc.fromVersion, tc.toVersion, tc.fromSize, tc.toSize, versions, sizes)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected error: %t, got: %v", tc.wantErr, err)
+			}
+			if tc.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(replace, tc.replace) {
+				t.Fatalf("expected replace: %v, got: %v", tc.replace, replace)
+			}
+			if len(plan) != 1 {
+				t.Fatalf("expected the change to be explained, got: %v", plan)
+			}
+		})
+	}
+}
+
+func TestCustomizeDiffDatabase(t *testing.T) {
+	server := mockapi.NewServer()
+	defer server.Close()
+	client, err := server.Client(mockapi.DefaultRegion)
+	if err != nil {
+		t.Fatalf("failed to build the client: %s", err)
+	}
+
+	state := &terraform.InstanceState{
+		ID: "database",
+		Attributes: map[string]string{
+			"id":      "database",
+			"name":    "database",
+			"region":  mockapi.DefaultRegion,
+			"size":    "g3.db.medium",
+			"engine":  "PostgreSQL",
+			"version": "14",
+			"nodes":   "1",
+		},
+	}
+	config := func(size, version string) *terraform.ResourceConfig {
+		return terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":    "database",
+			"region":  mockapi.DefaultRegion,
+			"size":    size,
+			"engine":  "PostgreSQL",
+			"version": version,
+			"nodes":   1,
+		})
+	}
+
+	diff, err := database.ResourceDatabase().Diff(context.Background(), state, config("g3.db.large", "16"), client)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff.RequiresNew() {
+		t.Error("expected the upgrade to be made in place")
+	}
+	for k, expected := range map[string]string{
+		"update_plan.#": "2",
+		"update_plan.0": "the version is upgraded in place from 14 to 16",
+		"update_plan.1": "the size is changed in place from g3.db.medium to g3.db.large",
+	} {
+		if attr, ok := diff.Attributes[k]; !ok || attr.New != expected {
+			t.Errorf("expected %s to be %q in the plan, got: %#v", k, expected, attr)
+		}
+	}
+
+	diff, err = database.ResourceDatabase().Diff(context.Background(), state, config("g3.db.small", "14"), client)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !diff.RequiresNew() {
+		t.Error("expected a smaller disk to replace the database")
+	}
+	if attr, ok := diff.Attributes["update_plan.0"]; !ok || !strings.Contains(attr.New, "the database will be replaced") {
+		t.Errorf("expected the replacement in the plan, got: %#v", attr)
+	}
+}
+
+// TestDiffDatabaseRestoreFrom plans restore_from, which builds a new database and replaces the
+// database when it changes instead of overwriting its data
+func TestDiffDatabaseRestoreFrom(t *testing.T) {
+	const sourceID = "0b8b2100-0e9f-4e8f-ad78-9eb578c2a0af"
+	config := func(timestamp string) *terraform.ResourceConfig {
+		return terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":    "restored",
+			"size":    "g3.db.small",
+			"engine":  "PostgreSQL",
+			"version": "16",
+			"nodes":   1,
+			"restore_from": []interface{}{
+				map[string]interface{}{"source_database_id": sourceID, "timestamp": timestamp},
+			},
+		})
+	}
+
+	if _, err := database.ResourceDatabase().Diff(context.Background(), nil, config("2024-05-01T02:00:00Z"), nil); err != nil {
+		t.Fatalf("expected restore_from to be planned for a new database, got: %s", err)
+	}
+
+	state := &terraform.InstanceState{
+		ID: "database",
+		Attributes: map[string]string{
+			"id":                                "database",
+			"name":                              "restored",
+			"size":                              "g3.db.small",
+			"engine":                            "PostgreSQL",
+			"version":                           "16",
+			"nodes":                             "1",
+			"restore_from.#":                    "1",
+			"restore_from.0.source_database_id": sourceID,
+			"restore_from.0.backup":             "",
+			"restore_from.0.timestamp":          "2024-05-01T02:00:00Z",
+		},
+	}
+	diff, err := database.ResourceDatabase().Diff(context.Background(), state, config("2024-05-02T02:00:00Z"), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !diff.RequiresNew() {
+		t.Error("expected another restore_from to replace the database")
+	}
+}
+
+func TestCheckDatabaseUpdate(t *testing.T) {
+	db := &civogo.Database{ID: "db", Size: "g3.db.medium", SoftwareVersion: "16"}
+
+	cases := []struct {
+		name          string
+		size, version string
+		errContains   string
+	}{
+		{name: "nothing requested"},
+		{name: "size and version applied", size: "g3.db.medium", version: "16"},
+		{name: "size ignored", size: "g3.db.large", errContains: "size of the Database db is still g3.db.medium"},
+		{name: "version ignored", version: "17", errContains: "version of the Database db is still 16"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := database.ExportCheckDatabaseUpdate(db, tc.size, tc.version)
+			if tc.errContains == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.errContains) {
+				t.Fatalf("expected an error containing %q, got %v", tc.errContains, err)
+			}
+		})
+	}
+}
+
+func TestCompareDatabaseVersions(t *testing.T) {
+	cases := []struct {
+		a, b     string
+		expected int
+	}{
+		{a: "16", b: "14", expected: 1},
+		{a: "8.0", b: "8.4", expected: -1},
+		{a: "10", b: "9.6", expected: 1},
+		{a: "13.4", b: "13.4", expected: 0},
+	}
+
+	for _, tc := range cases {
+		if got := database.ExportCompareDatabaseVersions(tc.a, tc.b); got != tc.expected {
+			t.Errorf("compare(%s, %s) = %d, expected %d", tc.a, tc.b, got, tc.expected)
+		}
+	}
+}
+
+func TestFindRestoreBackup(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 5, d, 2, 0, 0, 0, time.UTC) }
+	backups := []civogo.DatabaseBackup{
+		{ID: "a1", Name: "nightly-1", Status: "COMPLETED", CreatedAt: day(1)},
+		{ID: "a2", Name: "nightly-2", Status: "COMPLETED", CreatedAt: day(2)},
+		{ID: "a3", Name: "nightly-3", Status: "FAILED", CreatedAt: day(3)},
+		{ID: "a4", Name: "nightly-4", Status: "COMPLETED", CreatedAt: day(4)},
+	}
+
+	cases := []struct {
+		name      string
+		backup    string
+		timestamp time.Time
+		expected  string
+		wantErr   bool
+	}{
+		{name: "By name", backup: "nightly-2", expected: "a2"},
+		{name: "By ID", backup: "a3", expected: "a3"},
+		{name: "Unknown backup", backup: "weekly", wantErr: true},
+		{name: "Exact timestamp", timestamp: day(2), expected: "a2"},
+		{name: "Skips failed backups", timestamp: day(3).Add(time.Hour), expected: "a2"},
+		{name: "Latest backup", timestamp: day(9), expected: "a4"},
+		{name: "Before the first backup", timestamp: day(1).Add(-time.Minute), wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			backup, err := database.ExportFindRestoreBackup(backups, tc.backup, tc.timestamp)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected error: %t, got: %v", tc.wantErr, err)
+			}
+			if !tc.wantErr && backup.ID != tc.expected {
+				t.Fatalf("expected the backup %s, got: %s", tc.expected, backup.ID)
+			}
+		})
+	}
+}
+
 // CivoDatabaseConfig is used to configure the database resource
 func CivoDatabaseValues(database *civogo.Database, name string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
@@ -166,3 +449,15 @@ resource "civo_database" "foobar" {
 	nodes = 2
 }`, name)
 }
+
+// CivoDatabaseConfigVersion is used to configure a PostgreSQL database of the size and version
+func CivoDatabaseConfigVersion(name, size, version string) string {
+	return fmt.Sprintf(`
+resource "civo_database" "foobar" {
+	name = "%s"
+	size = "%s"
+	engine = "PostgreSQL"
+	version = "%s"
+	nodes = 1
+}`, name, size, version)
+}
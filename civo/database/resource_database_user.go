@@ -0,0 +1,400 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/civo/civogo"
+	"github.com/civo/terraform-provider-civo/internal/utils"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// ResourceDatabaseUser The database user resource represents a user of a managed database,
+// with the privileges it is granted, created by connecting to its endpoint
+func ResourceDatabaseUser() *schema.Resource {
+	return &schema.Resource{
+		Description: "Provides a user of a Civo managed database and the privileges it is granted on the databases, so every app can get its own credentials. The provider connects to the endpoint of the database with its admin user to manage it.",
+		Schema: map[string]*schema.Schema{
+			"database_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: utils.ValidateUUID,
+				Description:  "The ID of the managed database",
+			},
+			"username": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateFunc: validation.All(
+					validation.StringLenBetween(1, 32),
+					validation.StringMatch(sqlIdentifier, "must start with a letter or an underscore and only contain letters, digits and underscores"),
+				),
+				Description: "The name of the user",
+			},
+			"password": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				Sensitive:     true,
+				ConflictsWith: []string{"password_wo"},
+				ValidateFunc:  validation.StringLenBetween(8, 128),
+				Description:   "The password of the user, a random password is generated when neither `password` nor `password_wo` is set",
+			},
+			"password_wo": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				WriteOnly:     true,
+				ConflictsWith: []string{"password"},
+				ValidateFunc:  validation.StringLenBetween(8, 128),
+				Description:   "The password of the user, never stored in the state. Requires Terraform 1.11 or later",
+			},
+			"password_wo_version": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				RequiredWith: []string{"password_wo"},
+				Description:  "Change it to set the user to the current `password_wo`, as Terraform can't see a change of a write-only value",
+			},
+			"grant": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "The privileges of the user on a database, the user has none on the databases without a grant",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"database": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringMatch(sqlIdentifier, "must be the name of a database"),
+							Description:  "The name of the database, e.g. from `civo_database_schema`",
+						},
+						"privileges": {
+							Type:     schema.TypeSet,
+							Required: true,
+							MinItems: 1,
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validation.StringInSlice(databasePrivileges, false),
+							},
+							Description: "The privileges on the tables of the database, any of `SELECT`, `INSERT`, `UPDATE`, `DELETE`, `CREATE` (create and change tables) or `ALL`",
+						},
+					},
+				},
+			},
+			"host": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The address to connect to instead of the public endpoint of the database, e.g. its `private_ipv4` when Terraform runs in the same network",
+			},
+			"sslmode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "prefer",
+				ValidateFunc: validation.StringInSlice([]string{"disable", "prefer", "require", "verify-full"}, false),
+				Description:  "Whether the connection uses TLS, one of `disable`, `prefer`, `require` or `verify-full`",
+			},
+			"region": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				Description:      "The region of the database, if not declare we use the region in declared in the provider",
+				DiffSuppressFunc: utils.IgnoreCaseDiff,
+			},
+			// Computed resource
+			"engine": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The engine of the managed database",
+			},
+		},
+		CreateContext: resourceDatabaseUserCreate,
+		ReadContext:   resourceDatabaseUserRead,
+		UpdateContext: resourceDatabaseUserUpdate,
+		DeleteContext: resourceDatabaseUserDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceDatabaseUserImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+	}
+}
+
+// function to create the user and grant its privileges
+func resourceDatabaseUserCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*civogo.Client)
+
+	// overwrite the region if is defined in the datasource
+	if region, ok := d.GetOk("region"); ok {
+		apiClient = utils.RegionalClient(apiClient, region.(string))
+	}
+
+	databaseID := d.Get("database_id").(string)
+	username := d.Get("username").(string)
+
+	password := writeOnlyPassword(d)
+	if password == "" {
+		password = d.Get("password").(string)
+	}
+	generated := false
+	if password == "" {
+		var err error
+		if password, err = generatePassword(32); err != nil {
+			return diag.Errorf("[ERR] failed to generate the password of the user %s: %s", username, err)
+		}
+		generated = true
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, d.Timeout(schema.TimeoutCreate))
+	defer cancel()
+
+	conn, err := connectDatabase(ctx, apiClient, databaseID, d.Get("host").(string), d.Get("sslmode").(string), "")
+	if err != nil {
+		return diag.Errorf("[ERR] failed to connect to the database %s: %s", databaseID, err)
+	}
+	defer conn.Close()
+
+	log.Printf("[INFO] creating the user %s in the database %s", username, databaseID)
+	if _, err := conn.ExecContext(ctx, createUserStatement(conn.engine, username, password)); err != nil {
+		return diag.Errorf("[ERR] failed to create the user %s: %s", username, err)
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s", databaseID, username))
+	if generated {
+		d.Set("password", password)
+	}
+
+	for _, grant := range expandDatabaseGrants(d.Get("grant").(*schema.Set)) {
+		if err := grantDatabasePrivileges(ctx, apiClient, d, grant); err != nil {
+			return diag.Errorf("[ERR] failed to grant the privileges on %s to the user %s: %s", grant.database, username, err)
+		}
+	}
+
+	return resourceDatabaseUserRead(ctx, d, m)
+}
+
+// function to read the user, the password and the grants can't be read back
+func resourceDatabaseUserRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*civogo.Client)
+
+	// overwrite the region if is defined in the datasource
+	if region, ok := d.GetOk("region"); ok {
+		apiClient = utils.RegionalClient(apiClient, region.(string))
+	}
+
+	databaseID := d.Get("database_id").(string)
+	username := d.Get("username").(string)
+
+	log.Printf("[INFO] retrieving the user %s of the database %s", username, databaseID)
+	conn, err := connectDatabase(ctx, apiClient, databaseID, d.Get("host").(string), d.Get("sslmode").(string), "")
+	if err != nil {
+		if databaseNotFound(apiClient, databaseID) {
+			log.Printf("[INFO] database %s not found", databaseID)
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("[ERR] failed to connect to the database %s: %s", databaseID, err)
+	}
+	defer conn.Close()
+
+	exists, err := conn.exists(ctx, userExistsQuery(conn.engine), username)
+	if err != nil {
+		return diag.Errorf("[ERR] failed retrieving the user %s: %s", username, err)
+	}
+	if !exists {
+		log.Printf("[INFO] database user %s not found", username)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("engine", conn.engine)
+	d.Set("region", apiClient.Region)
+
+	return nil
+}
+
+// function to update the password and the grants of the user
+func resourceDatabaseUserUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*civogo.Client)
+
+	// overwrite the region if is defined in the datasource
+	if region, ok := d.GetOk("region"); ok {
+		apiClient = utils.RegionalClient(apiClient, region.(string))
+	}
+
+	databaseID := d.Get("database_id").(string)
+	username := d.Get("username").(string)
+
+	ctx, cancel := context.WithTimeout(ctx, d.Timeout(schema.TimeoutUpdate))
+	defer cancel()
+
+	password := ""
+	if d.HasChange("password_wo_version") {
+		password = writeOnlyPassword(d)
+	} else if d.HasChange("password") {
+		password = d.Get("password").(string)
+	}
+	if password != "" {
+		conn, err := connectDatabase(ctx, apiClient, databaseID, d.Get("host").(string), d.Get("sslmode").(string), "")
+		if err != nil {
+			return diag.Errorf("[ERR] failed to connect to the database %s: %s", databaseID, err)
+		}
+		defer conn.Close()
+
+		log.Printf("[INFO] changing the password of the user %s", username)
+		if _, err := conn.ExecContext(ctx, alterPasswordStatement(conn.engine, username, password)); err != nil {
+			return diag.Errorf("[ERR] failed to change the password of the user %s: %s", username, err)
+		}
+	}
+
+	if d.HasChange("grant") {
+		oldGrants, newGrants := d.GetChange("grant")
+		// the privileges on a database are revoked before they are granted again, so a
+		// privilege removed from the grant is removed from the user
+		for _, grant := range expandDatabaseGrants(oldGrants.(*schema.Set)) {
+			if err := revokeDatabasePrivileges(ctx, apiClient, d, grant.database); err != nil {
+				return diag.Errorf("[ERR] failed to revoke the privileges on %s from the user %s: %s", grant.database, username, err)
+			}
+		}
+		for _, grant := range expandDatabaseGrants(newGrants.(*schema.Set)) {
+			if err := grantDatabasePrivileges(ctx, apiClient, d, grant); err != nil {
+				return diag.Errorf("[ERR] failed to grant the privileges on %s to the user %s: %s", grant.database, username, err)
+			}
+		}
+	}
+
+	return resourceDatabaseUserRead(ctx, d, m)
+}
+
+// function to delete the user, its privileges are revoked first as PostgreSQL doesn't drop
+// a role that has some
+func resourceDatabaseUserDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*civogo.Client)
+
+	// overwrite the region if is defined in the datasource
+	if region, ok := d.GetOk("region"); ok {
+		apiClient = utils.RegionalClient(apiClient, region.(string))
+	}
+
+	databaseID := d.Get("database_id").(string)
+	username := d.Get("username").(string)
+
+	ctx, cancel := context.WithTimeout(ctx, d.Timeout(schema.TimeoutDelete))
+	defer cancel()
+
+	for _, grant := range expandDatabaseGrants(d.Get("grant").(*schema.Set)) {
+		if err := revokeDatabasePrivileges(ctx, apiClient, d, grant.database); err != nil {
+			if databaseNotFound(apiClient, databaseID) {
+				return nil
+			}
+			return diag.Errorf("[ERR] failed to revoke the privileges on %s from the user %s: %s", grant.database, username, err)
+		}
+	}
+
+	conn, err := connectDatabase(ctx, apiClient, databaseID, d.Get("host").(string), d.Get("sslmode").(string), "")
+	if err != nil {
+		if databaseNotFound(apiClient, databaseID) {
+			return nil
+		}
+		return diag.Errorf("[ERR] failed to connect to the database %s: %s", databaseID, err)
+	}
+	defer conn.Close()
+
+	log.Printf("[INFO] deleting the user %s of the database %s", username, databaseID)
+	if _, err := conn.ExecContext(ctx, dropUserStatement(conn.engine, username)); err != nil {
+		return diag.Errorf("[ERR] an error occurred while trying to delete the user %s: %s", username, err)
+	}
+
+	return nil
+}
+
+// custom import to split the ID, which is database_id:username
+func resourceDatabaseUserImport(_ context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+	databaseID, username, err := utils.ResourceCommonParseID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	d.Set("database_id", databaseID)
+	d.Set("username", username)
+	d.Set("sslmode", "prefer")
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// databaseGrant is a grant block of the user
+type databaseGrant struct {
+	database   string
+	privileges []string
+}
+
+// expandDatabaseGrants turns the grant blocks into grants, sorted by database
+func expandDatabaseGrants(set *schema.Set) []databaseGrant {
+	grants := []databaseGrant{}
+	for _, raw := range set.List() {
+		grant := raw.(map[string]interface{})
+		privileges := []string{}
+		for _, privilege := range grant["privileges"].(*schema.Set).List() {
+			privileges = append(privileges, privilege.(string))
+		}
+		sort.Strings(privileges)
+		grants = append(grants, databaseGrant{database: grant["database"].(string), privileges: privileges})
+	}
+	sort.Slice(grants, func(i, j int) bool { return grants[i].database < grants[j].database })
+	return grants
+}
+
+// grantDatabasePrivileges grants the privileges of the grant, PostgreSQL needs a connection
+// to the database the tables are in
+func grantDatabasePrivileges(ctx context.Context, apiClient *civogo.Client, d *schema.ResourceData, grant databaseGrant) error {
+	conn, err := connectDatabase(ctx, apiClient, d.Get("database_id").(string), d.Get("host").(string), d.Get("sslmode").(string), grant.database)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	log.Printf("[INFO] granting %v on %s to the user %s", grant.privileges, grant.database, d.Get("username").(string))
+	return conn.execStatements(ctx, grantStatements(conn.engine, d.Get("username").(string), grant.database, grant.privileges))
+}
+
+// revokeDatabasePrivileges revokes every privilege of the user on the database, it is fine
+// if the database or the privileges are already gone
+func revokeDatabasePrivileges(ctx context.Context, apiClient *civogo.Client, d *schema.ResourceData, database string) error {
+	conn, err := connectDatabase(ctx, apiClient, d.Get("database_id").(string), d.Get("host").(string), d.Get("sslmode").(string), database)
+	if err != nil {
+		if isMissingGrant(err) {
+			return nil
+		}
+		return err
+	}
+	defer conn.Close()
+
+	log.Printf("[INFO] revoking the privileges on %s from the user %s", database, d.Get("username").(string))
+	if err := conn.execStatements(ctx, revokeStatements(conn.engine, d.Get("username").(string), database)); err != nil && !isMissingGrant(err) {
+		return err
+	}
+	return nil
+}
+
+// writeOnlyPassword returns the password_wo from the configuration, write-only values are
+// only in the configuration and never in the plan or the state
+func writeOnlyPassword(d *schema.ResourceData) string {
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() || !config.Type().IsObjectType() || !config.Type().HasAttribute("password_wo") {
+		return ""
+	}
+	password := config.GetAttr("password_wo")
+	if password.IsNull() || !password.IsKnown() || !password.Type().Equals(cty.String) {
+		return ""
+	}
+	return password.AsString()
+}
@@ -0,0 +1,295 @@
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/civo/civogo"
+	"github.com/civo/terraform-provider-civo/civo/acceptance"
+	"github.com/civo/terraform-provider-civo/civo/database"
+	"github.com/civo/terraform-provider-civo/internal/mockapi"
+	"github.com/go-sql-driver/mysql"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	_ "github.com/lib/pq"
+)
+
+// TestAccCivoDatabaseUser_basic tests a user is created with a generated password and granted
+// privileges on a database of its own
+func TestAccCivoDatabaseUser_basic(t *testing.T) {
+	if acceptance.MockAPI != nil {
+		t.Skip("the fake API has no database server to connect to, TestDatabaseUserLocal covers it")
+	}
+	resName := "civo_database_user.app"
+	var name = acctest.RandomWithPrefix("tf-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { acceptance.TestAccPreCheck(t) },
+		Providers:    acceptance.TestAccProviders,
+		CheckDestroy: CivoDatabaseDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: CivoDatabaseUserConfigBasic(name, `["SELECT", "INSERT"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resName, "username", "app"),
+					resource.TestCheckResourceAttr(resName, "engine", "postgresql"),
+					resource.TestCheckResourceAttrSet(resName, "password"),
+					resource.TestCheckResourceAttr(resName, "grant.#", "1"),
+					resource.TestCheckResourceAttr("civo_database_schema.app", "name", "app"),
+				),
+			},
+			{
+				Config: CivoDatabaseUserConfigBasic(name, `["ALL"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs(resName, "grant.*", map[string]string{"database": "app", "privileges.#": "1"}),
+					resource.TestCheckTypeSetElemAttr(resName, "grant.*.privileges.*", "ALL"),
+				),
+			},
+			{
+				ResourceName:            resName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"password", "grant"},
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					return s.RootModule().Resources[resName].Primary.ID, nil
+				},
+			},
+		},
+	})
+}
+
+// CivoDatabaseUserConfigBasic is used to configure a database with a user of its own
+func CivoDatabaseUserConfigBasic(name, privileges string) string {
+	return fmt.Sprintf(`
+resource "civo_database" "foobar" {
+	name = "%s"
+	size = "g3.db.small"
+	engine = "PostgreSQL"
+	version = "16"
+	nodes = 1
+}
+
+resource "civo_database_schema" "app" {
+	database_id = civo_database.foobar.id
+	name = "app"
+}
+
+resource "civo_database_user" "app" {
+	database_id = civo_database.foobar.id
+	username = "app"
+
+	grant {
+		database = civo_database_schema.app.name
+		privileges = %s
+	}
+}`, name, privileges)
+}
+
+func TestGrantStatements(t *testing.T) {
+	cases := []struct {
+		name       string
+		engine     string
+		privileges []string
+		expected   []string
+	}{
+		{
+			name:       "MySQL read only",
+			engine:     "mysql",
+			privileges: []string{"SELECT"},
+			expected:   []string{"GRANT SELECT ON `app`.* TO 'web'@'%'"},
+		},
+		{
+			name:       "MySQL create",
+			engine:     "mysql",
+			privileges: []string{"CREATE", "SELECT"},
+			expected:   []string{"GRANT SELECT, CREATE, ALTER, INDEX, DROP, REFERENCES ON `app`.* TO 'web'@'%'"},
+		},
+		{
+			name:       "MySQL all",
+			engine:     "mysql",
+			privileges: []string{"ALL", "SELECT"},
+			expected:   []string{"GRANT ALL PRIVILEGES ON `app`.* TO 'web'@'%'"},
+		},
+		{
+			name:       "PostgreSQL read and write",
+			engine:     "postgresql",
+			privileges: []string{"INSERT", "SELECT"},
+			expected: []string{
+				`GRANT CONNECT ON DATABASE "app" TO "web"`,
+				`GRANT USAGE ON SCHEMA "public" TO "web"`,
+				`GRANT SELECT, INSERT ON ALL TABLES IN SCHEMA "public" TO "web"`,
+				`GRANT USAGE, SELECT ON ALL SEQUENCES IN SCHEMA "public" TO "web"`,
+				`ALTER DEFAULT PRIVILEGES IN SCHEMA "public" GRANT SELECT, INSERT ON TABLES TO "web"`,
+				`ALTER DEFAULT PRIVILEGES IN SCHEMA "public" GRANT USAGE, SELECT ON SEQUENCES TO "web"`,
+			},
+		},
+		{
+			name:       "PostgreSQL create only",
+			engine:     "postgresql",
+			privileges: []string{"CREATE"},
+			expected: []string{
+				`GRANT CONNECT ON DATABASE "app" TO "web"`,
+				`GRANT USAGE ON SCHEMA "public" TO "web"`,
+				`GRANT CREATE ON SCHEMA "public" TO "web"`,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := database.ExportGrantStatements(tc.engine, "web", "app", tc.privileges)
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Fatalf("expected:\n%s\ngot:\n%s", strings.Join(tc.expected, "\n"), strings.Join(got, "\n"))
+			}
+		})
+	}
+}
+
+func TestCreateUserStatement(t *testing.T) {
+	password := `it's\secret`
+
+	if got, expected := database.ExportCreateUserStatement("mysql", "web", password), `CREATE USER 'web'@'%' IDENTIFIED BY 'it''s\\secret'`; got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+	if got, expected := database.ExportCreateUserStatement("postgresql", "web", password), `CREATE ROLE "web" WITH LOGIN PASSWORD E'it''s\\secret'`; got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
+
+// TestDatabaseUserLocal creates a database and a user in a local MySQL or PostgreSQL server,
+// standing in for the endpoint of a managed database, and checks the user only has the
+// privileges it was granted. See TESTING.md to start the servers
+func TestDatabaseUserLocal(t *testing.T) {
+	engines := []struct {
+		software string
+		env      string
+	}{
+		{software: "MySQL", env: "CIVO_TEST_MYSQL_URL"},
+		{software: "PostgreSQL", env: "CIVO_TEST_POSTGRES_URL"},
+	}
+
+	for _, engine := range engines {
+		t.Run(engine.software, func(t *testing.T) {
+			raw := os.Getenv(engine.env)
+			if raw == "" {
+				t.Skipf("%s must be set to the URL of a local %s server to run this test", engine.env, engine.software)
+			}
+			server, err := url.Parse(raw)
+			if err != nil {
+				t.Fatalf("invalid %s: %s", engine.env, err)
+			}
+			port, err := strconv.Atoi(server.Port())
+			if err != nil {
+				t.Fatalf("%s must have a port: %s", engine.env, err)
+			}
+			adminPassword, _ := server.User.Password()
+
+			s := mockapi.NewServer()
+			defer s.Close()
+			client, err := s.Client(mockapi.DefaultRegion)
+			if err != nil {
+				t.Fatalf("failed to build client: %s", err)
+			}
+			db, err := client.NewDatabase(&civogo.CreateDatabaseRequest{Name: "local", Size: "g3.db.small", Software: engine.software})
+			if err != nil {
+				t.Fatalf("NewDatabase: %s", err)
+			}
+			if err := s.SetDatabaseConnection(db.ID, server.Hostname(), port, server.User.Username(), adminPassword); err != nil {
+				t.Fatalf("SetDatabaseConnection: %s", err)
+			}
+
+			ctx := context.Background()
+			schemaResource := database.ResourceDatabaseSchema()
+			schemaData := schema.TestResourceDataRaw(t, schemaResource.Schema, map[string]interface{}{
+				"database_id": db.ID,
+				"name":        "tf_local",
+				"sslmode":     "disable",
+			})
+			if diags := schemaResource.CreateContext(ctx, schemaData, client); diags.HasError() {
+				t.Fatalf("failed to create the database: %v", diags)
+			}
+			defer schemaResource.DeleteContext(ctx, schemaData, client)
+
+			// a table created by the admin, the user is only granted to read and add rows
+			admin := openLocalDatabase(t, engine.software, server.Hostname(), port, server.User.Username(), adminPassword, "tf_local")
+			if _, err := admin.Exec("CREATE TABLE items (id INT)"); err != nil {
+				t.Fatalf("failed to create the table: %s", err)
+			}
+			// PostgreSQL doesn't drop a database with open connections
+			admin.Close()
+
+			userResource := database.ResourceDatabaseUser()
+			userData := schema.TestResourceDataRaw(t, userResource.Schema, map[string]interface{}{
+				"database_id": db.ID,
+				"username":    "tf_local_app",
+				"sslmode":     "disable",
+				"grant": []interface{}{
+					map[string]interface{}{"database": "tf_local", "privileges": []interface{}{"SELECT", "INSERT"}},
+				},
+			})
+			if diags := userResource.CreateContext(ctx, userData, client); diags.HasError() {
+				t.Fatalf("failed to create the user: %v", diags)
+			}
+			password := userData.Get("password").(string)
+			if len(password) != 32 {
+				t.Fatalf("expected a generated password, got %d characters", len(password))
+			}
+
+			app := openLocalDatabase(t, engine.software, server.Hostname(), port, "tf_local_app", password, "tf_local")
+			if _, err := app.Exec("INSERT INTO items (id) VALUES (1)"); err != nil {
+				t.Errorf("expected the user to add rows: %s", err)
+			}
+			var count int
+			if err := app.QueryRow("SELECT COUNT(*) FROM items").Scan(&count); err != nil || count != 1 {
+				t.Errorf("expected the user to read the row, got %d: %v", count, err)
+			}
+			if _, err := app.Exec("DELETE FROM items"); err == nil {
+				t.Error("expected the user not to be allowed to delete rows")
+			}
+			app.Close()
+
+			if diags := userResource.DeleteContext(ctx, userData, client); diags.HasError() {
+				t.Fatalf("failed to delete the user: %v", diags)
+			}
+			if diags := userResource.ReadContext(ctx, userData, client); diags.HasError() || userData.Id() != "" {
+				t.Errorf("expected the user to be gone: %v", diags)
+			}
+		})
+	}
+}
+
+// openLocalDatabase connects to a database of the local server
+func openLocalDatabase(t *testing.T, software, host string, port int, username, password, name string) *sql.DB {
+	t.Helper()
+
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+	driver, dsn := "postgres", (&url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(username, password),
+		Host:     address,
+		Path:     "/" + name,
+		RawQuery: "sslmode=disable",
+	}).String()
+	if software == "MySQL" {
+		config := mysql.NewConfig()
+		config.User, config.Passwd, config.Net, config.Addr, config.DBName = username, password, "tcp", address, name
+		driver, dsn = "mysql", config.FormatDSN()
+	}
+
+	conn, err := sql.Open(driver, dsn)
+	if err != nil {
+		t.Fatalf("failed to open the database %s: %s", name, err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
@@ -0,0 +1,67 @@
+package firewall
+
+import (
+	"context"
+
+	"github.com/civo/civogo"
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// ExportFirewallRuleIDs exports firewallRuleIDs for testing
+func ExportFirewallRuleIDs(ruleSets ...interface{}) map[string]bool {
+	return firewallRuleIDs(ruleSets...)
+}
+
+// ExportOwnedFirewallRules exports ownedFirewallRules for testing
+func ExportOwnedFirewallRules(rules []civogo.FirewallRule, ids map[string]bool) []civogo.FirewallRule {
+	return ownedFirewallRules(rules, ids)
+}
+
+// ExportFirewallReferenceCIDRs exports firewallReferenceCIDRs for testing, the references are
+// given as firewalls, instances, kubernetes clusters and networks
+func ExportFirewallReferenceCIDRs(refs [4][]string, instances []civogo.Instance, clusters []civogo.KubernetesCluster, networks []civogo.Network) ([]string, error) {
+	return firewallReferenceCIDRs(firewallRuleReferences{
+		firewalls:          refs[0],
+		instances:          refs[1],
+		kubernetesClusters: refs[2],
+		networks:           refs[3],
+	}, instances, clusters, networks)
+}
+
+// ExportCopyFirewallRuleReferences exports copyFirewallRuleReferences for testing
+func ExportCopyFirewallRuleReferences(rules []interface{}, known []interface{}) {
+	copyFirewallRuleReferences(rules, known)
+}
+
+// ExportParseFirewallPortRange exports parseFirewallPortRange for testing
+func ExportParseFirewallPortRange(protocol, ports string) (int, int, error) {
+	p, err := parseFirewallPortRange(protocol, ports)
+	return p.start, p.end, err
+}
+
+// ExportValidateFirewallRules parses the ingress rules and checks them for duplicates and covered rules
+func ExportValidateFirewallRules(rules []map[string]interface{}) ([]string, error) {
+	var specs []*firewallRuleSpec
+	for _, rule := range rules {
+		spec, err := parseFirewallRule("ingress", rule, true)
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, spec)
+	}
+	return validateFirewallRules(specs), nil
+}
+
+// ExportValidateFirewallRawConfig runs validateFirewallRawConfig on the firewall configuration
+// given as JSON, the missing arguments are null
+func ExportValidateFirewallRawConfig(config string) (diag.Diagnostics, error) {
+	raw, err := ctyjson.Unmarshal([]byte(config), ResourceFirewall().CoreConfigSchema().ImpliedType())
+	if err != nil {
+		return nil, err
+	}
+	resp := &schema.ValidateResourceConfigFuncResponse{}
+	validateFirewallRawConfig(context.Background(), schema.ValidateResourceConfigFuncRequest{RawConfig: raw}, resp)
+	return resp.Diagnostics, nil
+}
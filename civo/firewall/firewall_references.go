@@ -0,0 +1,316 @@
+package firewall
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/civo/civogo"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// firewallRuleReferenceKeys are the arguments of a rule that name other objects,
+// they are expanded to the CIDRs of those objects
+var firewallRuleReferenceKeys = []string{"firewall_ids", "instance_ids", "kubernetes_cluster_ids", "network_ids"}
+
+// firewallRuleReferenceSchema returns the schema of the rule arguments that name other objects
+func firewallRuleReferenceSchema(forceNew bool) map[string]*schema.Schema {
+	descriptions := map[string]string{
+		"firewall_ids":           "The IDs of firewalls, expanded to the IP addresses of the instances and Kubernetes nodes that use them",
+		"instance_ids":           "The IDs of instances, expanded to their private and public IP addresses",
+		"kubernetes_cluster_ids": "The IDs of Kubernetes clusters, expanded to the public IP addresses of their nodes",
+		"network_ids":            "The IDs of networks, expanded to their CIDR",
+	}
+
+	s := map[string]*schema.Schema{}
+	for _, key := range firewallRuleReferenceKeys {
+		s[key] = &schema.Schema{
+			Type:        schema.TypeSet,
+			Optional:    true,
+			ForceNew:    forceNew,
+			Description: descriptions[key] + ". The CIDRs are added to `cidr` and kept up to date on every plan",
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		}
+	}
+	return s
+}
+
+// firewallRuleReferences are the objects named by a rule
+type firewallRuleReferences struct {
+	firewalls          []string
+	instances          []string
+	kubernetesClusters []string
+	networks           []string
+}
+
+func (r firewallRuleReferences) empty() bool {
+	return len(r.firewalls)+len(r.instances)+len(r.kubernetesClusters)+len(r.networks) == 0
+}
+
+// expandFirewallRuleReferences reads the objects named by a rule, from a rule map or from the
+// values of a ResourceData/ResourceDiff through get
+func expandFirewallRuleReferences(get func(string) interface{}) firewallRuleReferences {
+	list := func(key string) []string {
+		set, ok := get(key).(*schema.Set)
+		if !ok {
+			return nil
+		}
+		return expandFirewallRuleCIDR(set.List())
+	}
+	return firewallRuleReferences{
+		firewalls:          list("firewall_ids"),
+		instances:          list("instance_ids"),
+		kubernetesClusters: list("kubernetes_cluster_ids"),
+		networks:           list("network_ids"),
+	}
+}
+
+// firewallReferenceResolver expands rule references to CIDRs, the objects are only
+// listed once for all the rules of a firewall
+type firewallReferenceResolver struct {
+	client    *civogo.Client
+	instances []civogo.Instance
+	clusters  []civogo.KubernetesCluster
+	networks  []civogo.Network
+	loaded    map[string]bool
+}
+
+func newFirewallReferenceResolver(client *civogo.Client) *firewallReferenceResolver {
+	return &firewallReferenceResolver{client: client, loaded: map[string]bool{}}
+}
+
+// load lists the objects needed to expand the references
+func (r *firewallReferenceResolver) load(refs firewallRuleReferences) error {
+	if (len(refs.firewalls) > 0 || len(refs.instances) > 0) && !r.loaded["instances"] {
+		instances, err := r.client.ListAllInstances()
+		if err != nil {
+			return fmt.Errorf("failed to list the instances: %s", err)
+		}
+		r.instances = instances
+		r.loaded["instances"] = true
+	}
+
+	if (len(refs.firewalls) > 0 || len(refs.kubernetesClusters) > 0) && !r.loaded["clusters"] {
+		clusters, err := r.client.ListKubernetesClusters()
+		if err != nil {
+			return fmt.Errorf("failed to list the kubernetes clusters: %s", err)
+		}
+		r.clusters = clusters.Items
+		r.loaded["clusters"] = true
+	}
+
+	if len(refs.networks) > 0 && !r.loaded["networks"] {
+		networks, err := r.client.ListVPCNetworks()
+		if err != nil {
+			return fmt.Errorf("failed to list the networks: %s", err)
+		}
+		r.networks = networks
+		r.loaded["networks"] = true
+	}
+
+	return nil
+}
+
+// cidrs returns the CIDRs of the objects named by the references
+func (r *firewallReferenceResolver) cidrs(refs firewallRuleReferences) ([]string, error) {
+	if refs.empty() {
+		return nil, nil
+	}
+	if err := r.load(refs); err != nil {
+		return nil, err
+	}
+	return firewallReferenceCIDRs(refs, r.instances, r.clusters, r.networks)
+}
+
+// resolveRules returns a copy of the rules with the CIDRs of their references added to `cidr`
+func (r *firewallReferenceResolver) resolveRules(rules []interface{}) ([]interface{}, error) {
+	resolved := make([]interface{}, 0, len(rules))
+	for _, raw := range rules {
+		rule := map[string]interface{}{}
+		for k, v := range raw.(map[string]interface{}) {
+			rule[k] = v
+		}
+
+		refs := expandFirewallRuleReferences(func(key string) interface{} { return rule[key] })
+		cidrs, err := r.cidrs(refs)
+		if err != nil {
+			return nil, err
+		}
+		if len(cidrs) > 0 {
+			rule["cidr"] = mergeFirewallRuleCIDR(rule["cidr"], cidrs)
+		}
+
+		resolved = append(resolved, rule)
+	}
+	return resolved, nil
+}
+
+// firewallReferenceCIDRs expands the references using the listed objects, the result is sorted
+func firewallReferenceCIDRs(refs firewallRuleReferences, instances []civogo.Instance, clusters []civogo.KubernetesCluster, networks []civogo.Network) ([]string, error) {
+	cidrs := map[string]bool{}
+	addIP := func(ip string) {
+		if ip != "" {
+			cidrs[ip+"/32"] = true
+		}
+	}
+	addCluster := func(cluster civogo.KubernetesCluster) {
+		for _, node := range cluster.Instances {
+			addIP(node.PublicIP)
+		}
+	}
+
+	for _, id := range refs.firewalls {
+		for _, instance := range instances {
+			if instance.FirewallID == id {
+				addIP(instance.PrivateIP)
+				addIP(instance.PublicIP)
+			}
+		}
+		for _, cluster := range clusters {
+			if cluster.FirewallID == id {
+				addCluster(cluster)
+			}
+		}
+	}
+
+	for _, id := range refs.instances {
+		found := false
+		for _, instance := range instances {
+			if instance.ID == id {
+				found = true
+				addIP(instance.PrivateIP)
+				addIP(instance.PublicIP)
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("the instance %s referenced by the rule could not be found", id)
+		}
+	}
+
+	for _, id := range refs.kubernetesClusters {
+		found := false
+		for _, cluster := range clusters {
+			if cluster.ID == id {
+				found = true
+				addCluster(cluster)
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("the kubernetes cluster %s referenced by the rule could not be found", id)
+		}
+	}
+
+	for _, id := range refs.networks {
+		found := false
+		for _, network := range networks {
+			if network.ID == id {
+				found = true
+				if network.CIDR != "" {
+					cidrs[network.CIDR] = true
+				}
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("the network %s referenced by the rule could not be found", id)
+		}
+	}
+
+	result := make([]string, 0, len(cidrs))
+	for cidr := range cidrs {
+		result = append(result, cidr)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// mergeFirewallRuleCIDR adds the CIDRs to the cidr set of a rule
+func mergeFirewallRuleCIDR(current interface{}, cidrs []string) *schema.Set {
+	merged := schema.NewSet(schema.HashString, []interface{}{})
+	if set, ok := current.(*schema.Set); ok {
+		for _, v := range set.List() {
+			merged.Add(v)
+		}
+	}
+	for _, cidr := range cidrs {
+		merged.Add(cidr)
+	}
+	return merged
+}
+
+// rawConfigKnown reports whether the configured value of the key is known, it is also
+// false when there is no configuration to look at
+func rawConfigKnown(diff *schema.ResourceDiff, key string) bool {
+	raw := diff.GetRawConfig()
+	if raw.IsNull() || !raw.IsKnown() {
+		return false
+	}
+	return raw.GetAttr(key).IsWhollyKnown()
+}
+
+// configuredCIDR returns the cidr set in the configuration, without the CIDRs expanded
+// from the references in the state
+func configuredCIDR(diff *schema.ResourceDiff) []string {
+	var cidrs []string
+	raw := diff.GetRawConfig()
+	if raw.IsNull() || !raw.IsKnown() {
+		return cidrs
+	}
+	value := raw.GetAttr("cidr")
+	if value.IsNull() || !value.IsWhollyKnown() {
+		return cidrs
+	}
+	for it := value.ElementIterator(); it.Next(); {
+		_, v := it.Element()
+		cidrs = append(cidrs, v.AsString())
+	}
+	return cidrs
+}
+
+// firewallRuleKey identifies a rule by its content, used to match the rules returned
+// by the API with the configured ones before they have an ID
+func firewallRuleKey(rule map[string]interface{}) string {
+	var cidrs []string
+	if set, ok := rule["cidr"].(*schema.Set); ok {
+		cidrs = expandFirewallRuleCIDR(set.List())
+	}
+	sort.Strings(cidrs)
+	return strings.Join([]string{
+		fmt.Sprint(rule["protocol"]),
+		fmt.Sprint(rule["port_range"]),
+		fmt.Sprint(rule["action"]),
+		fmt.Sprint(rule["label"]),
+		strings.Join(cidrs, ","),
+	}, "|")
+}
+
+// copyFirewallRuleReferences copies the references of the known rules to the rules read
+// from the API, as the API only returns the expanded CIDRs. Rules are matched by ID, and
+// by content for the ones that don't have an ID yet
+func copyFirewallRuleReferences(rules []interface{}, known []interface{}) {
+	byID := map[string]map[string]interface{}{}
+	byKey := map[string]map[string]interface{}{}
+	for _, raw := range known {
+		rule := raw.(map[string]interface{})
+		if id, _ := rule["id"].(string); id != "" {
+			byID[id] = rule
+		} else {
+			byKey[firewallRuleKey(rule)] = rule
+		}
+	}
+
+	for _, raw := range rules {
+		rule := raw.(map[string]interface{})
+		source, ok := byID[rule["id"].(string)]
+		if !ok {
+			source, ok = byKey[firewallRuleKey(rule)]
+		}
+		if !ok {
+			continue
+		}
+		for _, key := range firewallRuleReferenceKeys {
+			if v, ok := source[key]; ok {
+				rule[key] = v
+			}
+		}
+	}
+}
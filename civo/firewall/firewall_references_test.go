@@ -0,0 +1,113 @@
+package firewall_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/civo/civogo"
+	"github.com/civo/terraform-provider-civo/civo/firewall"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestFirewallReferenceCIDRs(t *testing.T) {
+	instances := []civogo.Instance{
+		{ID: "instance-1", FirewallID: "firewall-1", PrivateIP: "192.168.1.2", PublicIP: "74.220.1.2"},
+		{ID: "instance-2", FirewallID: "firewall-2", PrivateIP: "192.168.1.3"},
+	}
+	clusters := []civogo.KubernetesCluster{
+		{ID: "cluster-1", FirewallID: "firewall-1", Instances: []civogo.KubernetesInstance{{PublicIP: "74.220.1.10"}, {PublicIP: "74.220.1.11"}}},
+	}
+	networks := []civogo.Network{{ID: "network-1", CIDR: "192.168.1.0/24"}}
+
+	tests := []struct {
+		name     string
+		refs     [4][]string
+		expected []string
+		wantErr  string
+	}{
+		{
+			name:     "firewall expands to its instances and cluster nodes",
+			refs:     [4][]string{{"firewall-1"}},
+			expected: []string{"192.168.1.2/32", "74.220.1.10/32", "74.220.1.11/32", "74.220.1.2/32"},
+		},
+		{
+			name:     "instance without public IP",
+			refs:     [4][]string{nil, {"instance-2"}},
+			expected: []string{"192.168.1.3/32"},
+		},
+		{
+			name:     "cluster and network",
+			refs:     [4][]string{nil, nil, {"cluster-1"}, {"network-1"}},
+			expected: []string{"192.168.1.0/24", "74.220.1.10/32", "74.220.1.11/32"},
+		},
+		{
+			name:     "firewall without instances",
+			refs:     [4][]string{{"firewall-3"}},
+			expected: []string{},
+		},
+		{
+			name:    "missing instance",
+			refs:    [4][]string{nil, {"instance-3"}},
+			wantErr: "the instance instance-3 referenced by the rule could not be found",
+		},
+		{
+			name:    "missing network",
+			refs:    [4][]string{nil, nil, nil, {"network-2"}},
+			wantErr: "the network network-2 referenced by the rule could not be found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cidrs, err := firewall.ExportFirewallReferenceCIDRs(tt.refs, instances, clusters, networks)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected an error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(cidrs, tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, cidrs)
+			}
+		})
+	}
+}
+
+func TestCopyFirewallRuleReferences(t *testing.T) {
+	cidr := func(values ...string) *schema.Set {
+		set := schema.NewSet(schema.HashString, []interface{}{})
+		for _, v := range values {
+			set.Add(v)
+		}
+		return set
+	}
+	instances := cidr("instance-1")
+
+	rules := []interface{}{
+		map[string]interface{}{"id": "rule-1", "protocol": "tcp", "port_range": "22", "action": "allow", "label": "ssh", "cidr": cidr("192.168.1.2/32")},
+		map[string]interface{}{"id": "rule-2", "protocol": "tcp", "port_range": "443", "action": "allow", "label": "https", "cidr": cidr("10.0.0.1/32")},
+		map[string]interface{}{"id": "rule-3", "protocol": "tcp", "port_range": "80", "action": "allow", "label": "http", "cidr": cidr("0.0.0.0/0")},
+	}
+	known := []interface{}{
+		// matched by ID
+		map[string]interface{}{"id": "rule-1", "protocol": "tcp", "port_range": "22", "action": "allow", "label": "ssh", "cidr": cidr("192.168.1.9/32"), "instance_ids": instances},
+		// matched by content, the rule didn't have an ID yet
+		map[string]interface{}{"id": "", "protocol": "tcp", "port_range": "443", "action": "allow", "label": "https", "cidr": cidr("10.0.0.1/32"), "network_ids": cidr("network-1")},
+	}
+
+	firewall.ExportCopyFirewallRuleReferences(rules, known)
+
+	if got := rules[0].(map[string]interface{})["instance_ids"]; got != instances {
+		t.Fatalf("expected the instance_ids of rule-1 to be copied, got %v", got)
+	}
+	if got, ok := rules[1].(map[string]interface{})["network_ids"].(*schema.Set); !ok || !got.Contains("network-1") {
+		t.Fatalf("expected the network_ids of rule-2 to be copied, got %v", got)
+	}
+	if _, ok := rules[2].(map[string]interface{})["instance_ids"]; ok {
+		t.Fatalf("expected rule-3 to have no references")
+	}
+}
@@ -0,0 +1,207 @@
+package firewall
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// firewallPortRange is an inclusive range of ports
+type firewallPortRange struct {
+	start int
+	end   int
+}
+
+func (p firewallPortRange) contains(o firewallPortRange) bool {
+	return p.start <= o.start && o.end <= p.end
+}
+
+// firewallRuleSpec is a rule with its ports and CIDRs parsed
+type firewallRuleSpec struct {
+	direction string
+	label     string
+	protocol  string
+	action    string
+	ports     firewallPortRange
+	cidrs     []*net.IPNet
+}
+
+func (r *firewallRuleSpec) String() string {
+	if r.label != "" {
+		return fmt.Sprintf("%s rule %q", r.direction, r.label)
+	}
+	return fmt.Sprintf("%s rule %s %d-%d", r.direction, r.protocol, r.ports.start, r.ports.end)
+}
+
+// covers reports whether every packet matched by o is also matched by r
+func (r *firewallRuleSpec) covers(o *firewallRuleSpec) bool {
+	if r.direction != o.direction || r.protocol != o.protocol || !r.ports.contains(o.ports) {
+		return false
+	}
+	if len(r.cidrs) == 0 || len(o.cidrs) == 0 {
+		return false
+	}
+	for _, inner := range o.cidrs {
+		covered := false
+		for _, outer := range r.cidrs {
+			if cidrContains(outer, inner) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return false
+		}
+	}
+	return true
+}
+
+// parseFirewallPortRange parses a single port, a range separated by a dash or `all`,
+// icmp rules don't use ports and cover all of them
+func parseFirewallPortRange(protocol, ports string) (firewallPortRange, error) {
+	all := firewallPortRange{start: 1, end: 65535}
+	if protocol == "icmp" {
+		return all, nil
+	}
+
+	ports = strings.TrimSpace(ports)
+	switch {
+	case ports == "":
+		return firewallPortRange{}, fmt.Errorf("port_range is required if protocol is tcp or udp")
+	case strings.EqualFold(ports, "all"):
+		return all, nil
+	}
+
+	parts := strings.SplitN(ports, "-", 2)
+	start, err := parseFirewallPort(parts[0])
+	if err != nil {
+		return firewallPortRange{}, fmt.Errorf("invalid port_range %q: %s", ports, err)
+	}
+	end := start
+	if len(parts) == 2 {
+		if end, err = parseFirewallPort(parts[1]); err != nil {
+			return firewallPortRange{}, fmt.Errorf("invalid port_range %q: %s", ports, err)
+		}
+	}
+	if start > end {
+		return firewallPortRange{}, fmt.Errorf("invalid port_range %q: the first port must not be greater than the last one", ports)
+	}
+
+	return firewallPortRange{start: start, end: end}, nil
+}
+
+func parseFirewallPort(port string) (int, error) {
+	p, err := strconv.Atoi(strings.TrimSpace(port))
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", port)
+	}
+	if p < 1 || p > 65535 {
+		return 0, fmt.Errorf("the port %d must be between 1 and 65535", p)
+	}
+	return p, nil
+}
+
+// parseFirewallCIDR parses an IPv4 or IPv6 CIDR, a single address is taken as a /32 or /128
+func parseFirewallCIDR(cidr string) (*net.IPNet, error) {
+	if !strings.Contains(cidr, "/") {
+		ip := net.ParseIP(cidr)
+		if ip == nil {
+			return nil, fmt.Errorf("%q is not a valid IPv4 or IPv6 CIDR", cidr)
+		}
+		bits := 128
+		if ip.To4() != nil {
+			ip, bits = ip.To4(), 32
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+	}
+
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, fmt.Errorf("%q is not a valid IPv4 or IPv6 CIDR", cidr)
+	}
+	return network, nil
+}
+
+// cidrContains reports whether the inner network is inside the outer one
+func cidrContains(outer, inner *net.IPNet) bool {
+	outerOnes, outerBits := outer.Mask.Size()
+	innerOnes, innerBits := inner.Mask.Size()
+	return outerBits == innerBits && outerOnes <= innerOnes && outer.Contains(inner.IP)
+}
+
+// parseFirewallRule parses a rule of the schema, the CIDRs are only parsed when checkCIDR is set
+// as they may not be known yet
+func parseFirewallRule(direction string, rule map[string]interface{}, checkCIDR bool) (*firewallRuleSpec, error) {
+	value := func(key string) string {
+		v, _ := rule[key].(string)
+		return v
+	}
+
+	spec := &firewallRuleSpec{
+		direction: direction,
+		label:     value("label"),
+		protocol:  strings.ToLower(value("protocol")),
+		action:    value("action"),
+	}
+
+	switch spec.protocol {
+	case "tcp", "udp", "icmp":
+	default:
+		return nil, fmt.Errorf("%s: unknown protocol %q, it must be tcp, udp or icmp", spec, spec.protocol)
+	}
+
+	ports, err := parseFirewallPortRange(spec.protocol, value("port_range"))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", spec, err)
+	}
+	spec.ports = ports
+
+	if !checkCIDR {
+		return spec, nil
+	}
+
+	if set, ok := rule["cidr"].(*schema.Set); ok {
+		for _, raw := range set.List() {
+			network, err := parseFirewallCIDR(raw.(string))
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s", spec, err)
+			}
+			spec.cidrs = append(spec.cidrs, network)
+		}
+	}
+
+	return spec, nil
+}
+
+// validateFirewallRules looks for rules that are duplicated or covered by other rules and returns
+// them as warnings, the API accepts them
+func validateFirewallRules(rules []*firewallRuleSpec) []string {
+	var warnings []string
+	for i, rule := range rules {
+		for j, other := range rules {
+			if i == j || !other.covers(rule) {
+				continue
+			}
+
+			if rule.covers(other) {
+				// each rule covers the other one, only report the pair once
+				if i < j && rule.action == other.action {
+					warnings = append(warnings, fmt.Sprintf("the %s and the %s are duplicates, remove one of them", rule, other))
+				} else if i < j {
+					warnings = append(warnings, fmt.Sprintf("the %s and the %s match the same traffic with different actions", rule, other))
+				}
+				continue
+			}
+
+			if rule.action == other.action {
+				warnings = append(warnings, fmt.Sprintf("the %s is already covered by the %s", rule, other))
+			} else {
+				warnings = append(warnings, fmt.Sprintf("the %s is fully covered by the %s, which has the opposite action", rule, other))
+			}
+		}
+	}
+	return warnings
+}
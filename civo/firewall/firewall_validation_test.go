@@ -0,0 +1,175 @@
+package firewall_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/civo/terraform-provider-civo/civo/firewall"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestParseFirewallPortRange(t *testing.T) {
+	tests := []struct {
+		protocol string
+		ports    string
+		start    int
+		end      int
+		wantErr  string
+	}{
+		{protocol: "tcp", ports: "80", start: 80, end: 80},
+		{protocol: "tcp", ports: "80-443", start: 80, end: 443},
+		{protocol: "udp", ports: "all", start: 1, end: 65535},
+		{protocol: "icmp", ports: "", start: 1, end: 65535},
+		{protocol: "tcp", ports: "", wantErr: "port_range is required"},
+		{protocol: "tcp", ports: "443-80", wantErr: "must not be greater"},
+		{protocol: "tcp", ports: "0", wantErr: "must be between 1 and 65535"},
+		{protocol: "tcp", ports: "80-70000", wantErr: "must be between 1 and 65535"},
+		{protocol: "tcp", ports: "http", wantErr: "is not a number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.protocol+" "+tt.ports, func(t *testing.T) {
+			start, end, err := firewall.ExportParseFirewallPortRange(tt.protocol, tt.ports)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected an error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if start != tt.start || end != tt.end {
+				t.Fatalf("expected %d-%d, got %d-%d", tt.start, tt.end, start, end)
+			}
+		})
+	}
+}
+
+func TestValidateFirewallRules(t *testing.T) {
+	rule := func(label, ports, action string, cidrs ...string) map[string]interface{} {
+		set := schema.NewSet(schema.HashString, []interface{}{})
+		for _, c := range cidrs {
+			set.Add(c)
+		}
+		return map[string]interface{}{"label": label, "protocol": "tcp", "port_range": ports, "action": action, "cidr": set}
+	}
+
+	tests := []struct {
+		name     string
+		rules    []map[string]interface{}
+		warnings []string
+		wantErr  string
+	}{
+		{
+			name:  "distinct rules",
+			rules: []map[string]interface{}{rule("ssh", "22", "allow", "10.0.0.0/8"), rule("https", "443", "allow", "0.0.0.0/0")},
+		},
+		{
+			name:  "ipv6 and single address",
+			rules: []map[string]interface{}{rule("ssh", "22", "allow", "2001:db8::/32", "192.168.1.1")},
+		},
+		{
+			name:    "invalid cidr",
+			rules:   []map[string]interface{}{rule("ssh", "22", "allow", "10.0.0.0/33")},
+			wantErr: `"10.0.0.0/33" is not a valid IPv4 or IPv6 CIDR`,
+		},
+		{
+			name:     "duplicates with a different label",
+			rules:    []map[string]interface{}{rule("ssh", "22", "allow", "10.0.0.1/32"), rule("ssh admin", "22", "allow", "10.0.0.1")},
+			warnings: []string{`the ingress rule "ssh" and the ingress rule "ssh admin" are duplicates, remove one of them`},
+		},
+		{
+			name:     "covered by a wider allow rule",
+			rules:    []map[string]interface{}{rule("all", "1-65535", "allow", "0.0.0.0/0"), rule("ssh", "22", "allow", "10.0.0.0/8")},
+			warnings: []string{`the ingress rule "ssh" is already covered by the ingress rule "all"`},
+		},
+		{
+			name:     "covered by a deny rule",
+			rules:    []map[string]interface{}{rule("block", "all", "deny", "10.0.0.0/8"), rule("ssh", "22", "allow", "10.1.0.0/16")},
+			warnings: []string{`the ingress rule "ssh" is fully covered by the ingress rule "block", which has the opposite action`},
+		},
+		{
+			name:     "same traffic with different actions",
+			rules:    []map[string]interface{}{rule("allow", "22", "allow", "10.0.0.0/8"), rule("deny", "22", "deny", "10.0.0.0/8")},
+			warnings: []string{`the ingress rule "allow" and the ingress rule "deny" match the same traffic with different actions`},
+		},
+		{
+			name:  "partial overlap",
+			rules: []map[string]interface{}{rule("web", "80-443", "allow", "10.0.0.0/8"), rule("alt", "400-8080", "allow", "10.0.0.0/8")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings, err := firewall.ExportValidateFirewallRules(tt.rules)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected an error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if strings.Join(warnings, "\n") != strings.Join(tt.warnings, "\n") {
+				t.Fatalf("expected the warnings %q, got %q", tt.warnings, warnings)
+			}
+		})
+	}
+}
+
+func TestValidateFirewallRawConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		warnings []string
+	}{
+		{
+			name:   "no rules",
+			config: `{"name": "example"}`,
+		},
+		{
+			name:   "rules without the default rules",
+			config: `{"name": "example", "create_default_rules": false, "ingress_rule": [{"label": "ssh", "port_range": "22", "cidr": ["10.0.0.0/8"], "action": "allow"}]}`,
+		},
+		{
+			// the plan fails in customizeDiffFirewall, a warning would contradict the error
+			name:   "rules with the default rules",
+			config: `{"name": "example", "ingress_rule": [{"label": "ssh", "port_range": "22", "cidr": ["10.0.0.0/8"], "action": "allow"}]}`,
+		},
+		{
+			name: "duplicated rules",
+			config: `{"name": "example", "create_default_rules": false, "ingress_rule": [
+				{"label": "ssh", "port_range": "22", "cidr": ["10.0.0.1/32"], "action": "allow"},
+				{"label": "ssh admin", "protocol": "tcp", "port_range": "22", "cidr": ["10.0.0.1"], "action": "allow"}
+			]}`,
+			warnings: []string{"are duplicates, remove one of them"},
+		},
+		{
+			name: "invalid rules are left to the plan",
+			config: `{"name": "example", "create_default_rules": false, "egress_rule": [
+				{"label": "all", "port_range": "all", "cidr": ["0.0.0.0/0"], "action": "allow"},
+				{"label": "bad", "port_range": "22", "cidr": ["10.0.0.0/33"], "action": "allow"}
+			]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags, err := firewall.ExportValidateFirewallRawConfig(tt.config)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if len(diags) != len(tt.warnings) {
+				t.Fatalf("expected %d warnings, got %v", len(tt.warnings), diags)
+			}
+			for i, d := range diags {
+				if d.Severity != diag.Warning || !strings.Contains(d.Detail, tt.warnings[i]) {
+					t.Errorf("expected a warning containing %q, got %v", tt.warnings[i], d)
+				}
+			}
+		})
+	}
+}
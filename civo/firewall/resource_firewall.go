@@ -8,6 +8,7 @@ import (
 
 	"github.com/civo/civogo"
 	"github.com/civo/terraform-provider-civo/internal/utils"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
@@ -62,56 +63,171 @@ func ResourceFirewall() *schema.Resource {
 				Elem:        firewallRuleSchema(),
 				Description: "The egress rules, this is a list of rules that will be applied to the firewall",
 			},
+			"ignore_unmanaged_rules": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "When set to true, only the rules created by this resource are read and removed, so rules added outside of it (e.g. with `civo_firewall_rule`) are left alone",
+			},
 		},
 		CreateContext: resourceFirewallCreate,
 		ReadContext:   resourceFirewallRead,
 		UpdateContext: resourceFirewallUpdate,
 		DeleteContext: resourceFirewallDelete,
-		CustomizeDiff: func(ctx context.Context, diff *schema.ResourceDiff, v interface{}) error {
+		CustomizeDiff: customizeDiffFirewall,
+		ValidateRawResourceConfigFuncs: []schema.ValidateRawResourceConfigFunc{
+			validateFirewallRawConfig,
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
+	}
+}
 
-			if diff.HasChange("create_default_rules") {
-				createDefaultRules := diff.Get("create_default_rules").(bool)
-				ingressRules := diff.Get("ingress_rule")
-				egressRules := diff.Get("egress_rule")
+// customizeDiffFirewall validates the rules before they reach the API and expands their references
+func customizeDiffFirewall(_ context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	ingressRules := diff.Get("ingress_rule").(*schema.Set)
+	egressRules := diff.Get("egress_rule").(*schema.Set)
 
-				if createDefaultRules && (ingressRules.(*schema.Set).Len() > 0 || egressRules.(*schema.Set).Len() > 0) {
-					return fmt.Errorf("create_default_rules can't be true when ingress_rule or egress_rule is specified")
-				}
+	if diff.HasChange("create_default_rules") {
+		createDefaultRules := diff.Get("create_default_rules").(bool)
+
+		if createDefaultRules && (ingressRules.Len() > 0 || egressRules.Len() > 0) {
+			return fmt.Errorf("create_default_rules can't be true when ingress_rule or egress_rule is specified")
+		}
+	}
+
+	// only the configured rules are checked, the rules read from the API are left as they are,
+	// the duplicated and covered rules are reported by validateFirewallRawConfig
+	for _, direction := range []string{"ingress", "egress"} {
+		key := direction + "_rule"
+		if !firewallRulesConfigured(diff, key) {
+			continue
+		}
+
+		// the CIDRs are checked once they are known
+		known := rawConfigKnown(diff, key)
+		for _, v := range diff.Get(key).(*schema.Set).List() {
+			if _, err := parseFirewallRule(direction, v.(map[string]interface{}), known); err != nil {
+				return err
 			}
+		}
+	}
 
-			ingressRules := diff.Get("ingress_rule")
-			for _, v := range ingressRules.(*schema.Set).List() {
-				ingress := v.(map[string]interface{})
-				protocol := ingress["protocol"]
+	return customizeDiffFirewallRuleReferences(diff, meta)
+}
 
-				port := ingress["port_range"]
-				if protocol != "icmp" && port == "" {
-					return fmt.Errorf("ports of ingress rules is required if protocol is tcp or udp")
-				}
+// validateFirewallRawConfig warns about configured rules that have no effect because they are
+// duplicated or covered by other rules. The rules that are not known yet are skipped, they are
+// checked again when planning. Rules together with create_default_rules fail the plan in
+// customizeDiffFirewall, so they aren't warned about here
+func validateFirewallRawConfig(_ context.Context, req schema.ValidateResourceConfigFuncRequest, resp *schema.ValidateResourceConfigFuncResponse) {
+	raw := req.RawConfig
+	if raw.IsNull() || !raw.IsKnown() {
+		return
+	}
+
+	var specs []*firewallRuleSpec
+	for _, direction := range []string{"ingress", "egress"} {
+		rules := raw.GetAttr(direction + "_rule")
+		if rules.IsNull() || !rules.IsKnown() {
+			continue
+		}
+
+		for it := rules.ElementIterator(); it.Next(); {
+			_, rule := it.Element()
+			if !rule.IsWhollyKnown() {
+				continue
+			}
+			// the invalid rules are reported when planning
+			if spec, err := parseFirewallRule(direction, firewallRawRule(rule), true); err == nil {
+				specs = append(specs, spec)
 			}
+		}
+	}
 
-			egressRules := diff.Get("egress_rule")
-			for _, v := range egressRules.(*schema.Set).List() {
-				egress := v.(map[string]interface{})
-				protocol := egress["protocol"]
+	for _, warning := range validateFirewallRules(specs) {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Overlapping firewall rules",
+			Detail:   fmt.Sprintf("In the firewall rules, %s.", warning),
+		})
+	}
+}
 
-				port := egress["port_range"]
-				if protocol != "icmp" && port == "" {
-					return fmt.Errorf("ports of egress rules is required if protocol is tcp or udp")
-				}
+// firewallRawRule converts a rule of the configuration to the map used by the schema, the
+// protocol defaults to tcp
+func firewallRawRule(rule cty.Value) map[string]interface{} {
+	m := map[string]interface{}{"protocol": "tcp"}
+	for _, key := range []string{"label", "protocol", "port_range", "action"} {
+		if v := rule.GetAttr(key); !v.IsNull() {
+			m[key] = v.AsString()
+		}
+	}
+
+	var cidrs []interface{}
+	if v := rule.GetAttr("cidr"); !v.IsNull() {
+		for _, cidr := range v.AsValueSlice() {
+			cidrs = append(cidrs, cidr.AsString())
+		}
+	}
+	m["cidr"] = schema.NewSet(schema.HashString, cidrs)
+
+	return m
+}
+
+// firewallRulesConfigured reports whether the configuration has explicit rules for the key
+func firewallRulesConfigured(diff *schema.ResourceDiff, key string) bool {
+	raw := diff.GetRawConfig()
+	if raw.IsNull() || !raw.IsKnown() {
+		return false
+	}
+	value := raw.GetAttr(key)
+	return !value.IsNull() && value.IsKnown() && value.LengthInt() > 0
+}
+
+// customizeDiffFirewallRuleReferences expands the references of the rules to their current CIDRs,
+// so the rules are replaced when the referenced objects change
+func customizeDiffFirewallRuleReferences(diff *schema.ResourceDiff, meta interface{}) error {
+	apiClient := meta.(*civogo.Client)
+	if region, ok := diff.GetOk("region"); ok {
+		apiClient = utils.RegionalClient(apiClient, region.(string))
+	}
+	resolver := newFirewallReferenceResolver(apiClient)
+
+	for _, key := range []string{"ingress_rule", "egress_rule"} {
+		rules := diff.Get(key).(*schema.Set).List()
+
+		hasReferences := false
+		for _, rule := range rules {
+			rule := rule.(map[string]interface{})
+			refs := expandFirewallRuleReferences(func(k string) interface{} { return rule[k] })
+			if refs.empty() && rule["cidr"].(*schema.Set).Len() == 0 {
+				return fmt.Errorf("the %s %q needs at least one cidr or reference", key, rule["label"])
 			}
+			hasReferences = hasReferences || !refs.empty()
+		}
 
-			return nil
-		},
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
-		Timeouts: &schema.ResourceTimeout{
-			Create: schema.DefaultTimeout(30 * time.Minute),
-			Update: schema.DefaultTimeout(30 * time.Minute),
-			Delete: schema.DefaultTimeout(30 * time.Minute),
-		},
+		// the references are expanded again at apply time when they are not known yet
+		if !hasReferences || !rawConfigKnown(diff, key) {
+			continue
+		}
+
+		resolved, err := resolver.resolveRules(rules)
+		if err != nil {
+			return err
+		}
+		if err := diff.SetNew(key, resolved); err != nil {
+			return err
+		}
 	}
+
+	return nil
 }
 
 // function to create a firewall
@@ -143,7 +259,7 @@ func resourceFirewallCreate(ctx context.Context, d *schema.ResourceData, m inter
 
 	log.Printf("[INFO] creating a new firewall %s", d.Get("name").(string))
 
-	firewallConfig, err := firewallRequestBuild(d, apiClient)
+	firewallConfig, rules, err := firewallRequestBuild(d, apiClient)
 	if err != nil {
 		return diag.Errorf("[ERR] an error occurred while trying to build the firewall request, %s", err)
 	}
@@ -177,6 +293,11 @@ func resourceFirewallCreate(ctx context.Context, d *schema.ResourceData, m inter
 
 	d.SetId(firewall.ID)
 
+	// every rule of the new firewall is owned by this resource, including the default ones
+	if err := setFirewallRules(d, firewall.Rules, rules); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return resourceFirewallRead(ctx, d, m)
 }
 
@@ -203,17 +324,19 @@ func resourceFirewallRead(_ context.Context, d *schema.ResourceData, m interface
 	d.Set("network_id", resp.NetworkID)
 	d.Set("region", apiClient.Region)
 	d.Set("create_default_rules", d.Get("create_default_rules").(bool))
+	d.Set("ignore_unmanaged_rules", d.Get("ignore_unmanaged_rules").(bool))
 
-	for _, rule := range resp.Rules {
-		if rule.Direction == "ingress" {
-			if err := d.Set("ingress_rule", flattenFirewallRules(resp.Rules, rule.Direction)); err != nil {
-				return diag.Errorf("[ERR] error setting ingress rules: %s", err)
-			}
-		} else {
-			if err := d.Set("egress_rule", flattenFirewallRules(resp.Rules, rule.Direction)); err != nil {
-				return diag.Errorf("[ERR] error setting egress rules: %s", err)
-			}
-		}
+	rules := resp.Rules
+	if d.Get("ignore_unmanaged_rules").(bool) {
+		rules = ownedFirewallRules(rules, firewallRuleIDs(d.Get("ingress_rule"), d.Get("egress_rule")))
+	}
+
+	known := map[string][]interface{}{
+		"ingress": d.Get("ingress_rule").(*schema.Set).List(),
+		"egress":  d.Get("egress_rule").(*schema.Set).List(),
+	}
+	if err := setFirewallRules(d, rules, known); err != nil {
+		return diag.FromErr(err)
 	}
 
 	return nil
@@ -253,12 +376,29 @@ func resourceFirewallUpdate(ctx context.Context, d *schema.ResourceData, m inter
 			egressRules = value.(*schema.Set).List()
 		}
 
+		// expand the references of the rules to their current CIDRs
+		resolver := newFirewallReferenceResolver(apiClient)
+		var err error
+		if ingressRules, err = resolver.resolveRules(ingressRules); err != nil {
+			return diag.Errorf("[ERR] an error occurred while trying to expand the ingress rules, %s", err)
+		}
+		if egressRules, err = resolver.resolveRules(egressRules); err != nil {
+			return diag.Errorf("[ERR] an error occurred while trying to expand the egress rules, %s", err)
+		}
+
 		// call the api to get the current rules
 		allRules, err := apiClient.ListVPCFirewallRules(d.Id())
 		if err != nil {
 			return diag.Errorf("[ERR] an error occurred while trying to list the firewall rules, %s", err)
 		}
 
+		// the rules added outside of this resource are left alone
+		oldIngressRules, _ := d.GetChange("ingress_rule")
+		oldEgressRules, _ := d.GetChange("egress_rule")
+		if d.Get("ignore_unmanaged_rules").(bool) {
+			allRules = ownedFirewallRules(allRules, firewallRuleIDs(oldIngressRules, oldEgressRules))
+		}
+
 		// remove the rules that are not in terraform
 		for _, rule := range allRules {
 			if rule.Direction == "ingress" {
@@ -290,6 +430,7 @@ func resourceFirewallUpdate(ctx context.Context, d *schema.ResourceData, m inter
 						return diag.Errorf("[WARN] an error occurred while trying to create the ingress rule %s, %s", fwRule, err)
 					}
 					log.Printf("[INFO] creating a new ingress rule %s", resp.ID)
+					ingressRule.(map[string]interface{})["id"] = resp.ID
 				}
 			}
 		}
@@ -304,9 +445,18 @@ func resourceFirewallUpdate(ctx context.Context, d *schema.ResourceData, m inter
 						return diag.Errorf("[WARN] an error occurred while trying to create the egress rule %s, %s", fwRule, err)
 					}
 					log.Printf("[INFO] creating a new egress rule %s", resp.ID)
+					egressRule.(map[string]interface{})["id"] = resp.ID
 				}
 			}
 		}
+
+		// keep the IDs of the new rules, so they are known as owned by this resource
+		if err := d.Set("ingress_rule", ingressRules); err != nil {
+			return diag.Errorf("[ERR] error setting ingress rules: %s", err)
+		}
+		if err := d.Set("egress_rule", egressRules); err != nil {
+			return diag.Errorf("[ERR] error setting egress rules: %s", err)
+		}
 	}
 
 	return resourceFirewallRead(ctx, d, m)
@@ -354,6 +504,50 @@ func resourceFirewallDelete(_ context.Context, d *schema.ResourceData, m interfa
 	return nil
 }
 
+// setFirewallRules sets the ingress and egress rules of the firewall, keeping the references
+// of the known rules
+func setFirewallRules(d *schema.ResourceData, rules []civogo.FirewallRule, known map[string][]interface{}) error {
+	ingressRules := flattenFirewallRules(rules, "ingress")
+	copyFirewallRuleReferences(ingressRules, known["ingress"])
+	if err := d.Set("ingress_rule", ingressRules); err != nil {
+		return fmt.Errorf("[ERR] error setting ingress rules: %s", err)
+	}
+	egressRules := flattenFirewallRules(rules, "egress")
+	copyFirewallRuleReferences(egressRules, known["egress"])
+	if err := d.Set("egress_rule", egressRules); err != nil {
+		return fmt.Errorf("[ERR] error setting egress rules: %s", err)
+	}
+	return nil
+}
+
+// firewallRuleIDs returns the IDs of the rules in the ingress and egress rule sets
+func firewallRuleIDs(ruleSets ...interface{}) map[string]bool {
+	ids := map[string]bool{}
+	for _, ruleSet := range ruleSets {
+		set, ok := ruleSet.(*schema.Set)
+		if !ok {
+			continue
+		}
+		for _, rule := range set.List() {
+			if id, _ := rule.(map[string]interface{})["id"].(string); id != "" {
+				ids[id] = true
+			}
+		}
+	}
+	return ids
+}
+
+// ownedFirewallRules filters the rules to the ones with the given IDs
+func ownedFirewallRules(rules []civogo.FirewallRule, ids map[string]bool) []civogo.FirewallRule {
+	owned := []civogo.FirewallRule{}
+	for _, rule := range rules {
+		if ids[rule.ID] {
+			owned = append(owned, rule)
+		}
+	}
+	return owned
+}
+
 // ingressRulesContains check if the ingress rules contains the rule
 func ingressRulesContains(ingressRules []interface{}, rule civogo.FirewallRule) bool {
 	for _, ingressRule := range ingressRules {
@@ -375,7 +569,7 @@ func egressRulesContains(egressRules []interface{}, rule civogo.FirewallRule) bo
 }
 
 func firewallRuleSchema() *schema.Resource {
-	return &schema.Resource{
+	s := &schema.Resource{
 		Schema: map[string]*schema.Schema{
 			"id": {
 				Type:        schema.TypeString,
@@ -411,8 +605,8 @@ func firewallRuleSchema() *schema.Resource {
 			},
 			"cidr": {
 				Type:        schema.TypeSet,
-				Required:    true,
-				Description: "The CIDR notation of the other end to affect, or a valid network CIDR (e.g. 0.0.0.0/0 to open for everyone or 1.2.3.4/32 to open just for a specific IP address)",
+				Optional:    true,
+				Description: "The CIDR notation of the other end to affect, or a valid network CIDR (e.g. 0.0.0.0/0 to open for everyone or 1.2.3.4/32 to open just for a specific IP address). Required unless the rule references other objects",
 				Elem: &schema.Schema{
 					Type:         schema.TypeString,
 					ValidateFunc: validation.NoZeroValues,
@@ -428,10 +622,17 @@ func firewallRuleSchema() *schema.Resource {
 			},
 		},
 	}
+
+	for key, reference := range firewallRuleReferenceSchema(false) {
+		s.Schema[key] = reference
+	}
+
+	return s
 }
 
-// firewallRequestBuild builds the request body for a firewall
-func firewallRequestBuild(d *schema.ResourceData, client *civogo.Client) (*civogo.FirewallConfig, error) {
+// firewallRequestBuild builds the request body for a firewall, it also returns the rules
+// with their references expanded
+func firewallRequestBuild(d *schema.ResourceData, client *civogo.Client) (*civogo.FirewallConfig, map[string][]interface{}, error) {
 	var networkID string
 
 	if attr, ok := d.GetOk("network_id"); ok {
@@ -439,7 +640,7 @@ func firewallRequestBuild(d *schema.ResourceData, client *civogo.Client) (*civog
 	} else {
 		network, err := client.GetDefaultVPCNetwork()
 		if err != nil {
-			return nil, fmt.Errorf("[ERR] failed to get the default network: %s", err)
+			return nil, nil, fmt.Errorf("[ERR] failed to get the default network: %s", err)
 		}
 		networkID = network.ID
 	}
@@ -453,17 +654,30 @@ func firewallRequestBuild(d *schema.ResourceData, client *civogo.Client) (*civog
 		CreateRules: &createFirewallRules,
 	}
 
+	resolver := newFirewallReferenceResolver(client)
+	rules := map[string][]interface{}{}
+
 	// Get ingress_rule
 	if v, ok := d.GetOk("ingress_rule"); ok {
-		firewallCofig.Rules = append(firewallCofig.Rules, expandFirewallRules(v.(*schema.Set).List(), "ingress")...)
+		ingressRules, err := resolver.resolveRules(v.(*schema.Set).List())
+		if err != nil {
+			return nil, nil, err
+		}
+		firewallCofig.Rules = append(firewallCofig.Rules, expandFirewallRules(ingressRules, "ingress")...)
+		rules["ingress"] = ingressRules
 	}
 
 	// Get egress_rule
 	if v, ok := d.GetOk("egress_rule"); ok {
-		firewallCofig.Rules = append(firewallCofig.Rules, expandFirewallRules(v.(*schema.Set).List(), "egress")...)
+		egressRules, err := resolver.resolveRules(v.(*schema.Set).List())
+		if err != nil {
+			return nil, nil, err
+		}
+		firewallCofig.Rules = append(firewallCofig.Rules, expandFirewallRules(egressRules, "egress")...)
+		rules["egress"] = egressRules
 	}
 
-	return firewallCofig, nil
+	return firewallCofig, rules, nil
 }
 
 // expandFirewallIngressRules expands the ingress rules
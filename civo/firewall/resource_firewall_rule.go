@@ -0,0 +1,298 @@
+package firewall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/civo/civogo"
+	"github.com/civo/terraform-provider-civo/internal/utils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// ResourceFirewallRule Firewall rule resource, with this we can manage one rule of a firewall
+// that is shared with other configurations
+func ResourceFirewallRule() *schema.Resource {
+	r := &schema.Resource{
+		Description: "Provides a single rule of a Civo firewall. This can be used to add rules to a firewall managed somewhere else, set `ignore_unmanaged_rules` on the `civo_firewall` so it doesn't remove them.",
+		Schema: map[string]*schema.Schema{
+			"firewall_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: utils.ValidateUUID,
+				Description:  "The ID of the firewall the rule belongs to",
+			},
+			"direction": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The direction of the rule, can be `ingress` or `egress`",
+				ValidateFunc: validation.StringInSlice([]string{
+					"ingress", "egress",
+				}, false),
+			},
+			"label": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Description:  "A string that will be the displayed name/reference for this rule",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"protocol": {
+				Type:        schema.TypeString,
+				Default:     "tcp",
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The protocol choice from `tcp`, `udp` or `icmp` (the default if unspecified is `tcp`)",
+				ValidateFunc: validation.StringInSlice([]string{
+					"tcp",
+					"udp",
+					"icmp",
+				}, false),
+			},
+			"port_range": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Description:  "The port or port range to open, can be a single port or a range separated by a dash (`-`), e.g. `80` or `80-443`",
+				ValidateFunc: validation.NoZeroValues,
+			},
+			"cidr": {
+				Type:         schema.TypeSet,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				AtLeastOneOf: append([]string{"cidr"}, firewallRuleReferenceKeys...),
+				Description:  "The CIDR notation of the other end to affect, or a valid network CIDR (e.g. 0.0.0.0/0 to open for everyone or 1.2.3.4/32 to open just for a specific IP address). Required unless the rule references other objects",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.NoZeroValues,
+				},
+			},
+			"action": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The action of the rule can be allow or deny. When we set the `action = 'allow'`, this is going to add a rule to allow traffic. Similarly, setting `action = 'deny'` will deny the traffic.",
+				ValidateFunc: validation.StringInSlice([]string{
+					"allow", "deny",
+				}, false),
+			},
+			"region": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				Description:      "The region of the firewall, if is not defined we use the global defined in the provider",
+				DiffSuppressFunc: utils.IgnoreCaseDiff,
+			},
+		},
+		CreateContext: resourceFirewallRuleCreate,
+		ReadContext:   resourceFirewallRuleRead,
+		DeleteContext: resourceFirewallRuleDelete,
+		CustomizeDiff: customizeDiffFirewallRule,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceFirewallRuleImport,
+		},
+	}
+
+	for key, reference := range firewallRuleReferenceSchema(true) {
+		r.Schema[key] = reference
+	}
+
+	return r
+}
+
+// customizeDiffFirewallRule checks the ports and CIDRs and expands the references of the rule to their
+// current CIDRs, so the rule is replaced when the referenced objects change
+func customizeDiffFirewallRule(_ context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	rule := map[string]interface{}{
+		"label":      diff.Get("label"),
+		"protocol":   diff.Get("protocol"),
+		"action":     diff.Get("action"),
+		"port_range": diff.Get("port_range"),
+		"cidr":       mergeFirewallRuleCIDR(nil, configuredCIDR(diff)),
+	}
+	if _, err := parseFirewallRule(diff.Get("direction").(string), rule, rawConfigKnown(diff, "cidr")); err != nil {
+		return err
+	}
+
+	refs := expandFirewallRuleReferences(diff.Get)
+	if refs.empty() {
+		return nil
+	}
+	for _, key := range firewallRuleReferenceKeys {
+		if !rawConfigKnown(diff, key) {
+			// the references are expanded at apply time
+			return diff.SetNewComputed("cidr")
+		}
+	}
+
+	apiClient := meta.(*civogo.Client)
+	if region, ok := diff.GetOk("region"); ok {
+		apiClient = utils.RegionalClient(apiClient, region.(string))
+	}
+
+	cidrs, err := newFirewallReferenceResolver(apiClient).cidrs(refs)
+	if err != nil {
+		return err
+	}
+
+	cidr := mergeFirewallRuleCIDR(nil, append(configuredCIDR(diff), cidrs...))
+	if cidr.Len() == 0 {
+		return fmt.Errorf("the references of the rule don't have any IP address or CIDR")
+	}
+	return diff.SetNew("cidr", cidr)
+}
+
+// function to create a firewall rule
+func resourceFirewallRuleCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*civogo.Client)
+
+	// overwrite the region if it's defined
+	if region, ok := d.GetOk("region"); ok {
+		apiClient = utils.RegionalClient(apiClient, region.(string))
+	}
+
+	// expand the references again, they may not have been known at plan time
+	refs := expandFirewallRuleReferences(d.Get)
+	cidrs, err := newFirewallReferenceResolver(apiClient).cidrs(refs)
+	if err != nil {
+		return diag.Errorf("[ERR] failed to expand the references of the rule: %s", err)
+	}
+
+	config := &civogo.FirewallRuleConfig{
+		FirewallID: d.Get("firewall_id").(string),
+		Region:     apiClient.Region,
+		Protocol:   d.Get("protocol").(string),
+		Cidr:       expandFirewallRuleCIDR(mergeFirewallRuleCIDR(d.Get("cidr"), cidrs).List()),
+		Direction:  d.Get("direction").(string),
+		Action:     d.Get("action").(string),
+		Label:      d.Get("label").(string),
+		Ports:      d.Get("port_range").(string),
+	}
+
+	log.Printf("[INFO] creating a new %s rule in the firewall %s", config.Direction, config.FirewallID)
+	rule, err := apiClient.NewVPCFirewallRule(config)
+	if err != nil {
+		return diag.Errorf("[ERR] failed to create the %s rule in the firewall %s: %s", config.Direction, config.FirewallID, err)
+	}
+
+	d.SetId(rule.ID)
+
+	return resourceFirewallRuleRead(ctx, d, m)
+}
+
+// function to read a firewall rule
+func resourceFirewallRuleRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*civogo.Client)
+
+	// overwrite the region if it's defined
+	if region, ok := d.GetOk("region"); ok {
+		apiClient = utils.RegionalClient(apiClient, region.(string))
+	}
+
+	firewallID := d.Get("firewall_id").(string)
+
+	log.Printf("[INFO] retriving the rule %s of the firewall %s", d.Id(), firewallID)
+	rules, err := apiClient.ListVPCFirewallRules(firewallID)
+	if err != nil {
+		if errors.Is(err, civogo.DatabaseFirewallNotFoundError) {
+			log.Printf("[INFO] firewall %s not found", firewallID)
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("[ERR] error retrieving the rules of the firewall %s: %s", firewallID, err)
+	}
+
+	rule := findFirewallRule(rules, d.Id())
+	if rule == nil {
+		log.Printf("[INFO] firewall rule %s not found", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("region", apiClient.Region)
+	d.Set("direction", rule.Direction)
+	d.Set("label", rule.Label)
+	d.Set("protocol", rule.Protocol)
+	d.Set("port_range", rule.Ports)
+	d.Set("action", rule.Action)
+	d.Set("cidr", flattenFirewallRuleCIDR(rule.Cidr))
+
+	return nil
+}
+
+// function to delete a firewall rule
+func resourceFirewallRuleDelete(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*civogo.Client)
+
+	// overwrite the region if it's defined
+	if region, ok := d.GetOk("region"); ok {
+		apiClient = utils.RegionalClient(apiClient, region.(string))
+	}
+
+	firewallID := d.Get("firewall_id").(string)
+
+	log.Printf("[INFO] deleting the rule %s of the firewall %s", d.Id(), firewallID)
+	_, err := apiClient.DeleteVPCFirewallRule(firewallID, d.Id())
+	if err != nil {
+		if errors.Is(err, civogo.DatabaseFirewallNotFoundError) || errors.Is(err, civogo.DatabaseFirewallRulesFindError) {
+			return nil
+		}
+		return diag.Errorf("[ERR] an error occurred while trying to delete the rule %s of the firewall %s: %s", d.Id(), firewallID, err)
+	}
+
+	return nil
+}
+
+// resourceFirewallRuleImport function to import a rule using firewall_id:rule_id,
+// the firewall is searched in all the regions
+func resourceFirewallRuleImport(_ context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	apiClient := m.(*civogo.Client)
+
+	firewallID, ruleID, err := utils.ResourceCommonParseID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	regions, err := apiClient.ListRegions()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, region := range regions {
+		regionalClient := utils.RegionalClient(apiClient, region.Code)
+
+		log.Printf("[INFO] retriving the rules of the firewall %s from region %s", firewallID, region.Code)
+		rules, err := regionalClient.ListVPCFirewallRules(firewallID)
+		if err != nil {
+			continue
+		}
+
+		if findFirewallRule(rules, ruleID) == nil {
+			return nil, fmt.Errorf("the rule %s could not be found in the firewall %s", ruleID, firewallID)
+		}
+
+		d.SetId(ruleID)
+		d.Set("firewall_id", firewallID)
+		d.Set("region", region.Code)
+		return []*schema.ResourceData{d}, nil
+	}
+
+	return nil, fmt.Errorf("the firewall %s could not be found in any region", firewallID)
+}
+
+// findFirewallRule finds a rule by ID
+func findFirewallRule(rules []civogo.FirewallRule, id string) *civogo.FirewallRule {
+	for i := range rules {
+		if rules[i].ID == id {
+			return &rules[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package firewall_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/civo/civogo"
+	"github.com/civo/terraform-provider-civo/civo/acceptance"
+	"github.com/civo/terraform-provider-civo/civo/firewall"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccCivoFirewallRule_basic(t *testing.T) {
+	var firewall civogo.Firewall
+
+	resName := "civo_firewall.foobar"
+	resRuleName := "civo_firewall_rule.foobar"
+	var firewallName = acctest.RandomWithPrefix("tf-fw")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { acceptance.TestAccPreCheck(t) },
+		Providers:    acceptance.TestAccProviders,
+		CheckDestroy: CivoFirewallDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: CivoFirewallRuleConfigBasic(firewallName),
+				Check: resource.ComposeTestCheckFunc(
+					CivoFirewallResourceExists(resName, &firewall),
+					resource.TestCheckResourceAttr(resName, "ingress_rule.#", "1"),
+					resource.TestCheckResourceAttrSet(resRuleName, "id"),
+					resource.TestCheckResourceAttr(resRuleName, "direction", "ingress"),
+					resource.TestCheckResourceAttr(resRuleName, "port_range", "8080"),
+					resource.TestCheckResourceAttr(resRuleName, "cidr.#", "1"),
+				),
+			},
+			{
+				// a second apply must not remove the standalone rule or add it to the firewall
+				Config:   CivoFirewallRuleConfigBasic(firewallName),
+				PlanOnly: true,
+			},
+			{
+				ResourceName:      resRuleName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: CivoFirewallRuleImportID(resRuleName),
+			},
+		},
+	})
+}
+
+func TestAccCivoFirewallRule_references(t *testing.T) {
+	resRuleName := "civo_firewall_rule.foobar"
+	var firewallName = acctest.RandomWithPrefix("tf-fw")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { acceptance.TestAccPreCheck(t) },
+		Providers:    acceptance.TestAccProviders,
+		CheckDestroy: CivoFirewallDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: CivoFirewallRuleConfigReferences(firewallName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resRuleName, "network_ids.#", "1"),
+					resource.TestCheckResourceAttr(resRuleName, "cidr.#", "1"),
+					resource.TestCheckTypeSetElemAttrPair(resRuleName, "cidr.*", "civo_network.foobar", "cidr_v4"),
+				),
+			},
+			{
+				Config:   CivoFirewallRuleConfigReferences(firewallName),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestFirewallRuleIDs(t *testing.T) {
+	ruleSchema := schema.HashResource(&schema.Resource{Schema: map[string]*schema.Schema{
+		"id": {Type: schema.TypeString, Computed: true},
+	}})
+	ingress := schema.NewSet(ruleSchema, []interface{}{
+		map[string]interface{}{"id": "rule-1"},
+		map[string]interface{}{"id": ""},
+	})
+	egress := schema.NewSet(ruleSchema, []interface{}{
+		map[string]interface{}{"id": "rule-2"},
+	})
+
+	ids := firewall.ExportFirewallRuleIDs(ingress, egress)
+	expected := map[string]bool{"rule-1": true, "rule-2": true}
+	if !reflect.DeepEqual(ids, expected) {
+		t.Fatalf("expected %v, got %v", expected, ids)
+	}
+
+	rules := []civogo.FirewallRule{{ID: "rule-1"}, {ID: "rule-2"}, {ID: "rule-3"}}
+	owned := firewall.ExportOwnedFirewallRules(rules, ids)
+	if len(owned) != 2 || owned[0].ID != "rule-1" || owned[1].ID != "rule-2" {
+		t.Fatalf("expected the rules rule-1 and rule-2, got %v", owned)
+	}
+}
+
+func CivoFirewallRuleImportID(n string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return "", fmt.Errorf("Not found: %s", n)
+		}
+		return fmt.Sprintf("%s:%s", rs.Primary.Attributes["firewall_id"], rs.Primary.ID), nil
+	}
+}
+
+func CivoFirewallRuleConfigBasic(name string) string {
+	return fmt.Sprintf(`
+resource "civo_firewall" "foobar" {
+	name = "%s"
+	create_default_rules = false
+	ignore_unmanaged_rules = true
+	region = "LOCAL"
+
+	ingress_rule {
+		label = "www https"
+		protocol = "tcp"
+		port_range = "443"
+		cidr = ["0.0.0.0/0"]
+		action = "allow"
+	}
+}
+
+resource "civo_firewall_rule" "foobar" {
+	firewall_id = civo_firewall.foobar.id
+	direction = "ingress"
+	label = "app"
+	protocol = "tcp"
+	port_range = "8080"
+	cidr = ["192.168.1.0/24"]
+	action = "allow"
+	region = "LOCAL"
+}`, name)
+}
+
+func CivoFirewallRuleConfigReferences(name string) string {
+	return fmt.Sprintf(`
+resource "civo_network" "foobar" {
+	label = "%[1]s"
+	region = "LOCAL"
+}
+
+resource "civo_firewall" "foobar" {
+	name = "%[1]s"
+	network_id = civo_network.foobar.id
+	ignore_unmanaged_rules = true
+	region = "LOCAL"
+}
+
+resource "civo_firewall_rule" "foobar" {
+	firewall_id = civo_firewall.foobar.id
+	direction = "ingress"
+	label = "network"
+	port_range = "1-65535"
+	network_ids = [civo_network.foobar.id]
+	action = "allow"
+	region = "LOCAL"
+}`, name)
+}
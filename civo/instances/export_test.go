@@ -0,0 +1,28 @@
+package instances
+
+import (
+	"context"
+	"time"
+
+	"github.com/civo/civogo"
+)
+
+// ExportPlanInstanceResize exports planInstanceResize for testing
+func ExportPlanInstanceResize(from, to string, diskGigabytes int, stopping bool, sizes []civogo.InstanceSize) (*civogo.InstanceSize, string, error) {
+	return planInstanceResize(from, to, diskGigabytes, stopping, sizes)
+}
+
+// ExportSetInstancePowerState exports setInstancePowerState for testing
+func ExportSetInstancePowerState(ctx context.Context, apiClient *civogo.Client, id, state string, timeout time.Duration) error {
+	return setInstancePowerState(ctx, apiClient, id, state, timeout)
+}
+
+// ExportResizeInstance exports resizeInstance for testing
+func ExportResizeInstance(ctx context.Context, apiClient *civogo.Client, id, newSize string, stopping bool, timeout time.Duration) error {
+	return resizeInstance(ctx, apiClient, id, newSize, stopping, timeout)
+}
+
+// ExportRebootInstance exports rebootInstance for testing
+func ExportRebootInstance(ctx context.Context, apiClient *civogo.Client, id string, timeout time.Duration) error {
+	return rebootInstance(ctx, apiClient, id, timeout)
+}
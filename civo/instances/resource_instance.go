@@ -19,6 +19,12 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+// the values of desired_state
+const (
+	instanceStateRunning = "running"
+	instanceStateStopped = "stopped"
+)
+
 // ResourceInstance The instance resource represents an object of type instances
 // and with it you can handle the instances created with Terraform
 func ResourceInstance() *schema.Resource {
@@ -51,6 +57,28 @@ func ResourceInstance() *schema.Resource {
 				Default:     "g3.xsmall",
 				Description: "The name of the size, from the current list, e.g. g3.xsmall",
 			},
+			"allow_stopping_for_update": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "If set to true, the instance is stopped before a change of size and started again after it, instead of being rebooted by the resize",
+			},
+			"resize_plan": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "How the instance is resized, e.g. whether it is rebooted or stopped and started again, shown in the plan when the size changes and kept until the next resize",
+			},
+			"desired_state": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{instanceStateRunning, instanceStateStopped}, false),
+				Description:  "The power state of the instance, either 'running' or 'stopped'. When set, the instance is started or stopped to match it (optional; the power state isn't managed if unspecified)",
+			},
+			"reboot_trigger": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "An arbitrary value, the instance is rebooted every time it changes, e.g. a timestamp or the hash of a configuration file",
+			},
 			"public_ip_required": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -67,10 +95,19 @@ func ResourceInstance() *schema.Resource {
 			},
 			"disk_image": {
 				Type:         schema.TypeString,
-				Required:     true,
+				Optional:     true,
 				Description:  "The ID for the disk image to use to build the instance",
 				ForceNew:     true,
 				ValidateFunc: utils.ValidateUUID,
+				ExactlyOneOf: []string{"disk_image", "snapshot_id"},
+			},
+			"snapshot_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "The ID of an instance snapshot to build the instance from, instead of a disk image",
+				ForceNew:     true,
+				ValidateFunc: utils.ValidateUUID,
+				ExactlyOneOf: []string{"disk_image", "snapshot_id"},
 			},
 			"initial_user": {
 				Type:        schema.TypeString,
@@ -107,6 +144,7 @@ func ResourceInstance() *schema.Resource {
 				Description: "An optional list of tags, represented as a key, value pair",
 				Elem:        &schema.Schema{Type: schema.TypeString},
 			},
+			"tags_all": utils.TagsAllSchema(),
 			"script": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -267,6 +305,12 @@ func resourceInstanceCreate(ctx context.Context, d *schema.ResourceData, m inter
 		config.TemplateID = findDiskImage.ID
 	}
 
+	if attr, ok := d.GetOk("snapshot_id"); ok {
+		config.SourceType = "snapshot"
+		config.SourceID = attr.(string)
+		config.SnapshotID = attr.(string)
+	}
+
 	if attr, ok := d.GetOk("initial_user"); ok {
 		config.InitialUser = attr.(string)
 	}
@@ -279,13 +323,7 @@ func resourceInstanceCreate(ctx context.Context, d *schema.ResourceData, m inter
 		config.Script = attr.(string)
 	}
 
-	tfTags := d.Get("tags").(*schema.Set).List()
-	tags := make([]string, len(tfTags))
-	for i, tfTag := range tfTags {
-		tags[i] = tfTag.(string)
-	}
-
-	config.Tags = tags
+	config.Tags = utils.MergeTags(utils.ExpandTags(d.Get("tags")), utils.DefaultTags(apiClient))
 
 	log.Printf("[INFO] creating the instance %s", d.Get("hostname").(string))
 
@@ -344,6 +382,12 @@ func resourceInstanceCreate(ctx context.Context, d *schema.ResourceData, m inter
 		}
 	}
 
+	if d.Get("desired_state").(string) == instanceStateStopped {
+		if err := setInstancePowerState(ctx, apiClient, d.Id(), instanceStateStopped, d.Timeout(schema.TimeoutCreate)); err != nil {
+			return diag.Errorf("[ERR] %s", err)
+		}
+	}
+
 	if attr, ok := d.GetOk("notes"); ok {
 		resp, err := apiClient.GetInstance(d.Id())
 		if err != nil {
@@ -384,11 +428,6 @@ func resourceInstanceRead(_ context.Context, d *schema.ResourceData, m interface
 		return diag.Errorf("[ERR] failed to retriving the instance: %s", err)
 	}
 
-	diskImg, err := apiClient.GetDiskImageByName(resp.SourceID)
-	if err != nil {
-		return diag.Errorf("[ERR] failed to get the disk image: %s", err)
-	}
-
 	if d.Get("write_password").(bool) {
 		d.Set("initial_password", resp.InitialPassword)
 	} else {
@@ -415,15 +454,35 @@ func resourceInstanceRead(_ context.Context, d *schema.ResourceData, m interface
 	d.Set("source_type", resp.SourceType)
 	d.Set("source_id", resp.SourceID)
 	d.Set("sshkey_id", resp.SSHKeyID)
-	d.Set("tags", resp.Tags)
+	// the default tags of the provider are only shown in tags_all
+	d.Set("tags", utils.ResourceTags(resp.Tags, utils.ExpandTags(d.Get("tags")), utils.DefaultTags(apiClient)))
+	d.Set("tags_all", resp.Tags)
 	d.Set("private_ip", resp.PrivateIP)
 	d.Set("public_ip", resp.PublicIP)
 	d.Set("network_id", resp.NetworkID)
 	d.Set("firewall_id", resp.FirewallID)
 	d.Set("status", resp.Status)
+	// the power state is only tracked when it is managed, so a change made outside Terraform shows as a diff
+	if d.Get("desired_state").(string) != "" {
+		if state, ok := instancePowerState(resp.Status); ok {
+			d.Set("desired_state", state)
+		}
+	}
 	d.Set("created_at", resp.CreatedAt.UTC().String())
 	d.Set("notes", resp.Notes)
-	d.Set("disk_image", diskImg.ID)
+
+	// Instances built from a snapshot have no disk image to look up
+	if resp.SnapshotID != "" {
+		d.Set("snapshot_id", resp.SnapshotID)
+	} else if resp.SourceType == "snapshot" {
+		d.Set("snapshot_id", resp.SourceID)
+	} else {
+		diskImg, err := apiClient.GetDiskImageByName(resp.SourceID)
+		if err != nil {
+			return diag.Errorf("[ERR] failed to get the disk image: %s", err)
+		}
+		d.Set("disk_image", diskImg.ID)
+	}
 	d.Set("volume_type", resp.VolumeType)
 
 	if resp.PublicIP != "" {
@@ -452,32 +511,22 @@ func resourceInstanceUpdate(ctx context.Context, d *schema.ResourceData, m inter
 
 	// check if the size change if change we send to resize the instance
 	if d.HasChange("size") {
-		newSize := d.Get("size").(string)
-
-		log.Printf("[INFO] resizing the instance %s", d.Id())
-		_, err := apiClient.UpgradeInstance(d.Id(), newSize)
-		if err != nil {
-			return diag.Errorf("[WARN] An error occurred while resizing the instance %s", d.Id())
+		if err := resizeInstance(ctx, apiClient, d.Id(), d.Get("size").(string), d.Get("allow_stopping_for_update").(bool), d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return diag.Errorf("[ERR] %s", err)
 		}
+	}
 
-		createStateConf := &resource.StateChangeConf{
-			Pending: []string{"BUILDING", "REBOOTING"},
-			Target:  []string{"ACTIVE"},
-			Refresh: func() (interface{}, string, error) {
-				resp, err := apiClient.GetInstance(d.Id())
-				if err != nil {
-					return 0, "", err
-				}
-				return resp, resp.Status, nil
-			},
-			Timeout:        60 * time.Minute,
-			Delay:          3 * time.Second,
-			MinTimeout:     3 * time.Second,
-			NotFoundChecks: 60,
+	if d.HasChange("desired_state") {
+		if state := d.Get("desired_state").(string); state != "" {
+			if err := setInstancePowerState(ctx, apiClient, d.Id(), state, d.Timeout(schema.TimeoutUpdate)); err != nil {
+				return diag.Errorf("[ERR] %s", err)
+			}
 		}
-		_, err = createStateConf.WaitForStateContext(ctx)
-		if err != nil {
-			return diag.Errorf("error waiting for instance (%s) to be created: %s", d.Id(), err)
+	}
+
+	if d.HasChange("reboot_trigger") {
+		if err := rebootInstance(ctx, apiClient, d.Id(), d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return diag.Errorf("[ERR] %s", err)
 		}
 	}
 
@@ -576,13 +625,9 @@ func resourceInstanceUpdate(ctx context.Context, d *schema.ResourceData, m inter
 		return diag.Errorf("[ERR] updating sshkey_id is not supported")
 	}
 
-	// if tags is declare we update the instance with the tags
-	if d.HasChange("tags") {
-		tfTags := d.Get("tags").(*schema.Set).List()
-		tags := make([]string, len(tfTags))
-		for i, tfTag := range tfTags {
-			tags[i] = tfTag.(string)
-		}
+	// if tags is declare we update the instance with the tags, and the default tags of the provider
+	if d.HasChanges("tags", "tags_all") {
+		tags := utils.MergeTags(utils.ExpandTags(d.Get("tags")), utils.DefaultTags(apiClient))
 
 		instance, err := apiClient.GetInstance(d.Id())
 		if err != nil {
@@ -649,9 +694,227 @@ func customizeDiffInstance(ctx context.Context, d *schema.ResourceDiff, meta int
 	if d.Id() != "" && d.HasChange("script") {
 		return fmt.Errorf("the 'script' field is immutable")
 	}
+
+	if apiClient, ok := meta.(*civogo.Client); ok && d.Id() != "" && d.HasChange("size") && d.NewValueKnown("size") {
+		if region, ok := d.GetOk("region"); ok {
+			apiClient = utils.RegionalClient(apiClient, region.(string))
+		}
+
+		sizes, err := apiClient.ListInstanceSizes()
+		if err != nil {
+			return fmt.Errorf("failed to get the available sizes: %w", err)
+		}
+
+		oldSize, newSize := d.GetChange("size")
+		target, plan, err := planInstanceResize(oldSize.(string), newSize.(string), d.Get("disk_gb").(int), d.Get("allow_stopping_for_update").(bool), sizes)
+		if err != nil {
+			return err
+		}
+		// show how the instance is resized and the resources of the new size in the plan
+		if err := d.SetNew("resize_plan", plan); err != nil {
+			return err
+		}
+		if err := d.SetNew("cpu_cores", target.CPUCores); err != nil {
+			return err
+		}
+		if err := d.SetNew("ram_mb", target.RAMMegabytes); err != nil {
+			return err
+		}
+		if err := d.SetNew("disk_gb", target.DiskGigabytes); err != nil {
+			return err
+		}
+	}
+
+	return utils.CustomizeDiffTagsAll(d, meta, utils.ExpandTags(d.Get("tags")))
+}
+
+// planInstanceResize function to check a resize of an instance against the sizes of its region. Only
+// larger sizes are supported and the disk of an instance can't shrink, the returned plan explains
+// whether the instance is rebooted by the resize or stopped and started again
+func planInstanceResize(from, to string, diskGigabytes int, stopping bool, sizes []civogo.InstanceSize) (*civogo.InstanceSize, string, error) {
+	var current, target *civogo.InstanceSize
+	names := []string{}
+	for i, size := range sizes {
+		if !strings.EqualFold(size.Type, "instance") || !size.Selectable {
+			continue
+		}
+		names = append(names, size.Name)
+		if size.Name == to {
+			target = &sizes[i]
+		}
+	}
+	for i, size := range sizes {
+		if size.Name == from {
+			current = &sizes[i]
+		}
+	}
+
+	if target == nil {
+		return nil, "", fmt.Errorf("the size %s is not available for instances in this region, available sizes: %s", to, strings.Join(names, ", "))
+	}
+
+	// the size of the instance may have been retired, the disk is then the only thing to check
+	if current != nil {
+		if diskGigabytes == 0 {
+			diskGigabytes = current.DiskGigabytes
+		}
+		if target.CPUCores < current.CPUCores || target.RAMMegabytes < current.RAMMegabytes {
+			return nil, "", fmt.Errorf("downgrading the instance from %s (%d CPU, %d MB RAM) to %s (%d CPU, %d MB RAM) is not supported, an instance can only be resized to a larger size",
+				from, current.CPUCores, current.RAMMegabytes, to, target.CPUCores, target.RAMMegabytes)
+		}
+	}
+	if target.DiskGigabytes < diskGigabytes {
+		return nil, "", fmt.Errorf("the %d GB disk of the instance doesn't fit in the %d GB disk of the size %s, the disk of an instance can't shrink", diskGigabytes, target.DiskGigabytes, to)
+	}
+
+	plan := fmt.Sprintf("resizing from %s to %s reboots the instance, set allow_stopping_for_update to stop it cleanly instead", from, to)
+	if stopping {
+		plan = fmt.Sprintf("resizing from %s to %s stops the instance and starts it again", from, to)
+	}
+	if target.DiskGigabytes > diskGigabytes {
+		plan = fmt.Sprintf("%s, the disk grows from %d GB to %d GB", plan, diskGigabytes, target.DiskGigabytes)
+	}
+
+	return target, plan, nil
+}
+
+// resizeInstance function to resize the instance to the new size, with stopping the instance is
+// stopped before the resize and started again after it, if it was running
+func resizeInstance(ctx context.Context, apiClient *civogo.Client, id, newSize string, stopping bool, timeout time.Duration) error {
+	instance, err := apiClient.GetInstance(id)
+	if err != nil {
+		return fmt.Errorf("failed to get the instance %s: %s", id, err)
+	}
+
+	status := instance.Status
+	running := status == "ACTIVE"
+	if running && stopping {
+		log.Printf("[INFO] stopping the instance %s to resize it", id)
+		if err := setInstancePowerState(ctx, apiClient, id, instanceStateStopped, timeout); err != nil {
+			return err
+		}
+		status = "SHUTOFF"
+	} else {
+		running = false
+	}
+
+	log.Printf("[INFO] resizing the instance %s", id)
+	if _, err := apiClient.UpgradeInstance(id, newSize); err != nil {
+		return fmt.Errorf("an error occurred while resizing the instance %s: %s", id, err)
+	}
+	if err := waitForInstanceTransition(ctx, apiClient, id, status, []string{"BUILDING", "REBOOTING", "RESIZING"}, []string{"ACTIVE", "SHUTOFF"}, timeout); err != nil {
+		return fmt.Errorf("error waiting for instance (%s) to be resized: %s", id, err)
+	}
+
+	if running {
+		log.Printf("[INFO] starting the instance %s after the resize", id)
+		if err := setInstancePowerState(ctx, apiClient, id, instanceStateRunning, timeout); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// instancePowerState function to map the status of an instance to a desired_state, transitional
+// statuses have no power state
+func instancePowerState(status string) (string, bool) {
+	switch status {
+	case "ACTIVE":
+		return instanceStateRunning, true
+	case "SHUTOFF":
+		return instanceStateStopped, true
+	}
+	return "", false
+}
+
+// setInstancePowerState function to start or stop the instance and wait until it reaches the
+// state, nothing is done when the instance is already in it
+func setInstancePowerState(ctx context.Context, apiClient *civogo.Client, id, state string, timeout time.Duration) error {
+	instance, err := apiClient.GetInstance(id)
+	if err != nil {
+		return fmt.Errorf("failed to get the instance %s: %s", id, err)
+	}
+	if current, ok := instancePowerState(instance.Status); ok && current == state {
+		return nil
+	}
+
+	if state == instanceStateStopped {
+		log.Printf("[INFO] stopping the instance %s", id)
+		if _, err := apiClient.StopInstance(id); err != nil {
+			return fmt.Errorf("an error occurred while stopping the instance %s: %s", id, err)
+		}
+		if err := waitForInstanceStatus(ctx, apiClient, id, []string{"ACTIVE", "STOPPING"}, []string{"SHUTOFF"}, timeout); err != nil {
+			return fmt.Errorf("error waiting for instance (%s) to be stopped: %s", id, err)
+		}
+		return nil
+	}
+
+	log.Printf("[INFO] starting the instance %s", id)
+	if _, err := apiClient.StartInstance(id); err != nil {
+		return fmt.Errorf("an error occurred while starting the instance %s: %s", id, err)
+	}
+	if err := waitForInstanceStatus(ctx, apiClient, id, []string{"SHUTOFF", "STARTING"}, []string{"ACTIVE"}, timeout); err != nil {
+		return fmt.Errorf("error waiting for instance (%s) to be started: %s", id, err)
+	}
+	return nil
+}
+
+// rebootInstance function to reboot the instance for reboot_trigger, a stopped instance
+// is left stopped as there is nothing to reboot
+func rebootInstance(ctx context.Context, apiClient *civogo.Client, id string, timeout time.Duration) error {
+	instance, err := apiClient.GetInstance(id)
+	if err != nil {
+		return fmt.Errorf("failed to get the instance %s: %s", id, err)
+	}
+	if instance.Status == "SHUTOFF" {
+		log.Printf("[INFO] the instance %s is stopped, it isn't rebooted", id)
+		return nil
+	}
+
+	log.Printf("[INFO] rebooting the instance %s", id)
+	if _, err := apiClient.SoftRebootInstance(id); err != nil {
+		return fmt.Errorf("an error occurred while rebooting the instance %s: %s", id, err)
+	}
+	if err := waitForInstanceTransition(ctx, apiClient, id, "ACTIVE", []string{"REBOOTING", "STOPPING", "STARTING"}, []string{"ACTIVE"}, timeout); err != nil {
+		return fmt.Errorf("error waiting for instance (%s) to be rebooted: %s", id, err)
+	}
 	return nil
 }
 
+// waitForInstanceTransition function to wait until the instance reaches one of the target
+// statuses after an action, once it has left the status it had before the action
+func waitForInstanceTransition(ctx context.Context, apiClient *civogo.Client, id, status string, pending, target []string, timeout time.Duration) error {
+	return utils.WaitForTransition(ctx, fmt.Sprintf("the instance %s", id), status, pending, target, timeout, func() (interface{}, string, error) {
+		resp, err := apiClient.GetInstance(id)
+		if err != nil {
+			return 0, "", err
+		}
+		return resp, resp.Status, nil
+	})
+}
+
+// waitForInstanceStatus function to wait until the instance reaches one of the target statuses
+func waitForInstanceStatus(ctx context.Context, apiClient *civogo.Client, id string, pending, target []string, timeout time.Duration) error {
+	stateConf := &resource.StateChangeConf{
+		Pending: pending,
+		Target:  target,
+		Refresh: func() (interface{}, string, error) {
+			resp, err := apiClient.GetInstance(id)
+			if err != nil {
+				return 0, "", err
+			}
+			return resp, resp.Status, nil
+		},
+		Timeout:        timeout,
+		Delay:          3 * time.Second,
+		MinTimeout:     3 * time.Second,
+		NotFoundChecks: 60,
+	}
+	_, err := stateConf.WaitForStateContext(ctx)
+	return err
+}
+
 // checkNetworkFirstInstance checks if this is the first instance in a given network
 func checkNetworkFirstInstance(apiClient *civogo.Client, networkID string) (bool, error) {
 	// List all instances
@@ -0,0 +1,341 @@
+package instances
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/civo/civogo"
+	"github.com/civo/terraform-provider-civo/internal/utils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// ResourceInstanceSnapshot The instance snapshot resource represents a point in time
+// copy of an instance, which can be used as the source of new instances
+func ResourceInstanceSnapshot() *schema.Resource {
+	return &schema.Resource{
+		Description: "Provides a Civo instance snapshot resource. This can be used to create, modify, and delete snapshots of an instance.",
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				Description:      "The region of the instance, if not declared we use the region declared in the provider",
+				DiffSuppressFunc: utils.IgnoreCaseDiff,
+			},
+			"instance_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: utils.ValidateUUID,
+				Description:  "The ID of the instance to snapshot",
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: utils.ValidateNameSize,
+				Description:  "A name for the snapshot",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "A description for the snapshot",
+			},
+			"schedule": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateCronExpression,
+				Description:  "A cron expression (e.g. `0 2 * * *`) to also take snapshots of the instance on a schedule",
+			},
+			"max_snapshots": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				RequiredWith: []string{"schedule"},
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The number of scheduled snapshots to keep, older ones are deleted",
+			},
+			// Computed resource
+			"schedule_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The ID of the snapshot schedule, when `schedule` is set",
+			},
+			"included_volumes": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The IDs of the volumes included in the snapshot",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The status of the snapshot",
+			},
+			"created_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Timestamp when the snapshot was created",
+			},
+		},
+		CreateContext: resourceInstanceSnapshotCreate,
+		ReadContext:   resourceInstanceSnapshotRead,
+		UpdateContext: resourceInstanceSnapshotUpdate,
+		DeleteContext: resourceInstanceSnapshotDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceInstanceSnapshotImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
+	}
+}
+
+// function to create an instance snapshot
+func resourceInstanceSnapshotCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*civogo.Client)
+
+	// overwrite the region if is defined in the datasource
+	if region, ok := d.GetOk("region"); ok {
+		apiClient = utils.RegionalClient(apiClient, region.(string))
+	}
+
+	instanceID := d.Get("instance_id").(string)
+	params := &civogo.CreateInstanceSnapshotParams{
+		Name:        d.Get("name").(string),
+		Description: d.Get("description").(string),
+	}
+
+	log.Printf("[INFO] creating the snapshot %s of the instance %s", params.Name, instanceID)
+	snapshot, err := apiClient.CreateInstanceSnapshot(instanceID, params)
+	if err != nil {
+		return diag.Errorf("[ERR] failed to create the snapshot of the instance %s: %s", instanceID, err)
+	}
+
+	d.SetId(snapshot.ID)
+
+	createStateConf := &retry.StateChangeConf{
+		Pending: []string{"pending", "creating", "in_progress"},
+		Target:  []string{"ready", "available", "completed"},
+		Refresh: func() (interface{}, string, error) {
+			resp, err := apiClient.GetInstanceSnapshot(instanceID, d.Id())
+			if err != nil {
+				return 0, "", err
+			}
+			state := strings.ToLower(resp.Status.State)
+			if state == "failed" || state == "error" {
+				return resp, state, fmt.Errorf("the snapshot failed")
+			}
+			return resp, state, nil
+		},
+		Timeout:        d.Timeout(schema.TimeoutCreate),
+		Delay:          3 * time.Second,
+		MinTimeout:     3 * time.Second,
+		NotFoundChecks: 60,
+	}
+	if _, err = createStateConf.WaitForStateContext(ctx); err != nil {
+		return diag.Errorf("error waiting for snapshot (%s) to be created: %s", d.Id(), err)
+	}
+
+	if schedule, ok := d.GetOk("schedule"); ok {
+		scheduleID, err := createInstanceSnapshotSchedule(apiClient, d, schedule.(string))
+		if err != nil {
+			return diag.Errorf("[ERR] failed to create the snapshot schedule of the instance %s: %s", instanceID, err)
+		}
+		d.Set("schedule_id", scheduleID)
+	}
+
+	return resourceInstanceSnapshotRead(ctx, d, m)
+}
+
+// function to read an instance snapshot
+func resourceInstanceSnapshotRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*civogo.Client)
+
+	// overwrite the region if is defined in the datasource
+	if region, ok := d.GetOk("region"); ok {
+		apiClient = utils.RegionalClient(apiClient, region.(string))
+	}
+
+	log.Printf("[INFO] retrieving the snapshot %s", d.Id())
+	resp, err := apiClient.GetInstanceSnapshot(d.Get("instance_id").(string), d.Id())
+	if err != nil {
+		if errors.Is(err, civogo.DatabaseSnapshotNotFoundError) || errors.Is(err, civogo.DatabaseInstanceNotFoundError) {
+			log.Printf("[INFO] snapshot %s not found", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("[ERR] failed to retrieve the snapshot: %s", err)
+	}
+
+	d.Set("region", apiClient.Region)
+	d.Set("name", resp.Name)
+	d.Set("description", resp.Description)
+	d.Set("included_volumes", resp.IncludedVolumes)
+	d.Set("status", resp.Status.State)
+	d.Set("created_at", resp.CreatedAt.UTC().String())
+
+	if scheduleID := d.Get("schedule_id").(string); scheduleID != "" {
+		schedule, err := apiClient.GetSnapshotSchedule(scheduleID)
+		if err != nil {
+			if errors.Is(err, civogo.DatabaseSnapshotNotFoundError) || errors.Is(err, civogo.ZeroMatchesError) {
+				d.Set("schedule_id", "")
+				d.Set("schedule", "")
+				return nil
+			}
+			return diag.Errorf("[ERR] failed to retrieve the snapshot schedule: %s", err)
+		}
+		d.Set("schedule", schedule.CronExpression)
+		if schedule.Retention.MaxSnapshots > 0 {
+			d.Set("max_snapshots", schedule.Retention.MaxSnapshots)
+		}
+	}
+
+	return nil
+}
+
+// function to update an instance snapshot
+func resourceInstanceSnapshotUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*civogo.Client)
+
+	// overwrite the region if is defined in the datasource
+	if region, ok := d.GetOk("region"); ok {
+		apiClient = utils.RegionalClient(apiClient, region.(string))
+	}
+
+	if d.HasChanges("name", "description") {
+		params := &civogo.UpdateInstanceSnapshotParams{
+			Name:        d.Get("name").(string),
+			Description: d.Get("description").(string),
+		}
+
+		log.Printf("[INFO] updating the snapshot %s", d.Id())
+		if _, err := apiClient.UpdateInstanceSnapshot(d.Get("instance_id").(string), d.Id(), params); err != nil {
+			return diag.Errorf("[ERR] failed to update the snapshot %s: %s", d.Id(), err)
+		}
+	}
+
+	// The API can't change the cron expression or retention of a schedule,
+	// so it is replaced instead
+	if d.HasChanges("schedule", "max_snapshots") {
+		if scheduleID := d.Get("schedule_id").(string); scheduleID != "" {
+			log.Printf("[INFO] deleting the snapshot schedule %s", scheduleID)
+			if _, err := apiClient.DeleteSnapshotSchedule(scheduleID); err != nil {
+				return diag.Errorf("[ERR] failed to delete the snapshot schedule %s: %s", scheduleID, err)
+			}
+			d.Set("schedule_id", "")
+		}
+
+		if schedule, ok := d.GetOk("schedule"); ok {
+			scheduleID, err := createInstanceSnapshotSchedule(apiClient, d, schedule.(string))
+			if err != nil {
+				return diag.Errorf("[ERR] failed to create the snapshot schedule of the instance %s: %s", d.Get("instance_id").(string), err)
+			}
+			d.Set("schedule_id", scheduleID)
+		}
+	}
+
+	return resourceInstanceSnapshotRead(ctx, d, m)
+}
+
+// function to delete an instance snapshot
+func resourceInstanceSnapshotDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*civogo.Client)
+
+	// overwrite the region if is defined in the datasource
+	if region, ok := d.GetOk("region"); ok {
+		apiClient = utils.RegionalClient(apiClient, region.(string))
+	}
+
+	if scheduleID := d.Get("schedule_id").(string); scheduleID != "" {
+		log.Printf("[INFO] deleting the snapshot schedule %s", scheduleID)
+		if _, err := apiClient.DeleteSnapshotSchedule(scheduleID); err != nil && !errors.Is(err, civogo.DatabaseSnapshotNotFoundError) {
+			return diag.Errorf("[ERR] failed to delete the snapshot schedule %s: %s", scheduleID, err)
+		}
+	}
+
+	instanceID := d.Get("instance_id").(string)
+
+	log.Printf("[INFO] deleting the snapshot %s", d.Id())
+	if err := apiClient.DeleteInstanceSnapshot(instanceID, d.Id()); err != nil {
+		if errors.Is(err, civogo.DatabaseSnapshotNotFoundError) {
+			return nil
+		}
+		return diag.Errorf("[ERR] an error occurred while trying to delete the snapshot %s: %s", d.Id(), err)
+	}
+
+	deleteStateConf := &retry.StateChangeConf{
+		Pending: []string{"deleting"},
+		Target:  []string{"deleted"},
+		Refresh: func() (interface{}, string, error) {
+			resp, err := apiClient.GetInstanceSnapshot(instanceID, d.Id())
+			if err != nil {
+				if errors.Is(err, civogo.DatabaseSnapshotNotFoundError) || errors.Is(err, civogo.DatabaseInstanceNotFoundError) {
+					return 0, "deleted", nil
+				}
+				return 0, "", err
+			}
+			return resp, "deleting", nil
+		},
+		Timeout:    d.Timeout(schema.TimeoutDelete),
+		Delay:      3 * time.Second,
+		MinTimeout: 3 * time.Second,
+	}
+	if _, err := deleteStateConf.WaitForStateContext(ctx); err != nil {
+		return diag.Errorf("error waiting for snapshot (%s) to be deleted: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+// resourceInstanceSnapshotImport imports a snapshot using instance_id:snapshot_id,
+// the API only looks snapshots up through their instance
+func resourceInstanceSnapshotImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	instanceID, snapshotID, found := strings.Cut(d.Id(), ":")
+	if !found || instanceID == "" || snapshotID == "" {
+		return nil, fmt.Errorf("invalid import ID %q, expected instance_id:snapshot_id", d.Id())
+	}
+
+	d.SetId(snapshotID)
+	d.Set("instance_id", instanceID)
+	return []*schema.ResourceData{d}, nil
+}
+
+// createInstanceSnapshotSchedule creates a snapshot schedule for the instance
+// of the resource and returns its ID
+func createInstanceSnapshotSchedule(apiClient *civogo.Client, d *schema.ResourceData, cron string) (string, error) {
+	req := &civogo.CreateSnapshotScheduleRequest{
+		Name:           fmt.Sprintf("%s-schedule", d.Get("name").(string)),
+		Description:    d.Get("description").(string),
+		CronExpression: cron,
+		Retention: civogo.SnapshotRetention{
+			MaxSnapshots: d.Get("max_snapshots").(int),
+		},
+		Instances: []civogo.CreateSnapshotInstance{
+			{InstanceID: d.Get("instance_id").(string)},
+		},
+	}
+
+	log.Printf("[INFO] creating the snapshot schedule %s", req.Name)
+	schedule, err := apiClient.CreateSnapshotSchedule(req)
+	if err != nil {
+		return "", err
+	}
+	return schedule.ID, nil
+}
+
+// validateCronExpression checks the value is a five field cron expression
+func validateCronExpression(v interface{}, k string) (ws []string, es []error) {
+	value := v.(string)
+	if len(strings.Fields(value)) != 5 {
+		es = append(es, fmt.Errorf("%q must be a cron expression with five fields, e.g. \"0 2 * * *\", got: %q", k, value))
+	}
+	return
+}
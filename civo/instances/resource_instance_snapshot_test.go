@@ -0,0 +1,120 @@
+package instances_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/civo/civogo"
+	"github.com/civo/terraform-provider-civo/civo/acceptance"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// TestAccCivoInstanceSnapshot_basic verifies a snapshot can be taken, imported and used to build a new instance.
+func TestAccCivoInstanceSnapshot_basic(t *testing.T) {
+	var snapshot civogo.InstanceSnapshot
+
+	// generate a random name for each test run
+	resName := "civo_instance_snapshot.foobar"
+	var snapshotName = acctest.RandomWithPrefix("tf-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { acceptance.TestAccPreCheck(t) },
+		Providers:    acceptance.TestAccProviders,
+		CheckDestroy: CivoInstanceSnapshotDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: CivoInstanceSnapshotConfigBasic(snapshotName),
+				Check: resource.ComposeTestCheckFunc(
+					CivoInstanceSnapshotResourceExists(resName, &snapshot),
+					resource.TestCheckResourceAttr(resName, "name", snapshotName),
+					resource.TestCheckResourceAttrSet(resName, "status"),
+					resource.TestCheckResourceAttrSet(resName, "created_at"),
+					resource.TestCheckResourceAttr("civo_instance.restored", "source_type", "snapshot"),
+					resource.TestCheckResourceAttrPair("civo_instance.restored", "snapshot_id", resName, "id"),
+				),
+			},
+			{
+				ResourceName:      resName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					rs := s.RootModule().Resources[resName]
+					return fmt.Sprintf("%s:%s", rs.Primary.Attributes["instance_id"], rs.Primary.ID), nil
+				},
+			},
+		},
+	})
+}
+
+// CivoInstanceSnapshotResourceExists queries the API and retrieves the matching snapshot.
+func CivoInstanceSnapshotResourceExists(n string, snapshot *civogo.InstanceSnapshot) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("not found: %s", n)
+		}
+
+		client := acceptance.TestAccProvider.Meta().(*civogo.Client)
+		resp, err := client.GetInstanceSnapshot(rs.Primary.Attributes["instance_id"], rs.Primary.ID)
+		if err != nil {
+			return fmt.Errorf("snapshot not found: (%s) %s", rs.Primary.ID, err)
+		}
+
+		*snapshot = *resp
+		return nil
+	}
+}
+
+// CivoInstanceSnapshotDestroy checks the snapshots created during the test are gone
+func CivoInstanceSnapshotDestroy(s *terraform.State) error {
+	client := acceptance.TestAccProvider.Meta().(*civogo.Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "civo_instance_snapshot" {
+			continue
+		}
+
+		_, err := client.GetInstanceSnapshot(rs.Primary.Attributes["instance_id"], rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("snapshot still exists")
+		}
+	}
+
+	return nil
+}
+
+func CivoInstanceSnapshotConfigBasic(name string) string {
+	return fmt.Sprintf(`
+data "civo_disk_image" "debian" {
+	filter {
+		key = "name"
+		values = ["debian-12"]
+	}
+}
+
+resource "civo_firewall" "foobar" {
+	name = "%[1]s"
+}
+
+resource "civo_instance" "source" {
+	hostname = "%[1]s-source"
+	size = "g3.small"
+	firewall_id = civo_firewall.foobar.id
+	disk_image = element(data.civo_disk_image.debian.diskimages, 0).id
+}
+
+resource "civo_instance_snapshot" "foobar" {
+	instance_id = civo_instance.source.id
+	name = "%[1]s"
+	description = "golden image"
+}
+
+resource "civo_instance" "restored" {
+	hostname = "%[1]s-restored"
+	size = "g3.small"
+	firewall_id = civo_firewall.foobar.id
+	snapshot_id = civo_instance_snapshot.foobar.id
+}`, name)
+}
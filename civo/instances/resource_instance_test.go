@@ -1,11 +1,17 @@
 package instances_test
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/civo/civogo"
 	"github.com/civo/terraform-provider-civo/civo/acceptance"
+	"github.com/civo/terraform-provider-civo/civo/instances"
+	"github.com/civo/terraform-provider-civo/internal/mockapi"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
@@ -207,6 +213,269 @@ func TestAccCivoInstanceFirewall_update(t *testing.T) {
 	})
 }
 
+// TestAccCivoInstance_defaultTags verifies the default tags of the provider are added to the instance
+// without showing in its tags
+func TestAccCivoInstance_defaultTags(t *testing.T) {
+	var instance civogo.Instance
+
+	resName := "civo_instance.foobar"
+	var instanceHostname = acctest.RandomWithPrefix("tf-test") + ".example"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { acceptance.TestAccPreCheck(t) },
+		Providers:    acceptance.TestAccProviders,
+		CheckDestroy: acceptance.CivoInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: CivoInstanceConfigDefaultTags(instanceHostname, "owner:platform"),
+				Check: resource.ComposeTestCheckFunc(
+					acceptance.CivoInstanceResourceExists(resName, &instance),
+					resource.TestCheckResourceAttr(resName, "tags.#", "1"),
+					resource.TestCheckTypeSetElemAttr(resName, "tags.*", "web"),
+					resource.TestCheckResourceAttr(resName, "tags_all.#", "2"),
+					resource.TestCheckTypeSetElemAttr(resName, "tags_all.*", "web"),
+					resource.TestCheckTypeSetElemAttr(resName, "tags_all.*", "owner:platform"),
+				),
+			},
+			{
+				// changing the default tags updates the instance
+				Config: CivoInstanceConfigDefaultTags(instanceHostname, "owner:data"),
+				Check: resource.ComposeTestCheckFunc(
+					acceptance.CivoInstanceResourceExists(resName, &instance),
+					resource.TestCheckResourceAttr(resName, "tags.#", "1"),
+					resource.TestCheckResourceAttr(resName, "tags_all.#", "2"),
+					resource.TestCheckTypeSetElemAttr(resName, "tags_all.*", "owner:data"),
+				),
+			},
+		},
+	})
+}
+
+func TestPlanInstanceResize(t *testing.T) {
+	sizes := []civogo.InstanceSize{
+		{Type: "Instance", Name: "g3.xsmall", CPUCores: 1, RAMMegabytes: 1024, DiskGigabytes: 25, Selectable: true},
+		{Type: "Instance", Name: "g3.small", CPUCores: 1, RAMMegabytes: 2048, DiskGigabytes: 25, Selectable: true},
+		{Type: "Instance", Name: "g3.medium", CPUCores: 2, RAMMegabytes: 4096, DiskGigabytes: 50, Selectable: true},
+		{Type: "Instance", Name: "g2.small", CPUCores: 1, RAMMegabytes: 2048, DiskGigabytes: 25},
+		{Type: "Kubernetes", Name: "g4s.kube.medium", CPUCores: 2, RAMMegabytes: 4096, DiskGigabytes: 50, Selectable: true},
+	}
+
+	cases := []struct {
+		name     string
+		from     string
+		to       string
+		disk     int
+		stopping bool
+		plan     string
+		wantErr  string
+	}{
+		{
+			name: "Larger size",
+			from: "g3.xsmall",
+			to:   "g3.small",
+			disk: 25,
+			plan: "resizing from g3.xsmall to g3.small reboots the instance, set allow_stopping_for_update to stop it cleanly instead",
+		},
+		{
+			name:     "Larger disk with stopping",
+			from:     "g3.small",
+			to:       "g3.medium",
+			disk:     25,
+			stopping: true,
+			plan:     "resizing from g3.small to g3.medium stops the instance and starts it again, the disk grows from 25 GB to 50 GB",
+		},
+		{
+			name: "Retired size",
+			from: "g1.small",
+			to:   "g3.small",
+			disk: 25,
+			plan: "resizing from g1.small to g3.small reboots the instance, set allow_stopping_for_update to stop it cleanly instead",
+		},
+		{
+			name:    "Downgrade",
+			from:    "g3.medium",
+			to:      "g3.small",
+			disk:    25,
+			wantErr: "is not supported",
+		},
+		{
+			name:    "Disk doesn't fit",
+			from:    "g1.small",
+			to:      "g3.small",
+			disk:    50,
+			wantErr: "the disk of an instance can't shrink",
+		},
+		{
+			name:    "Size not selectable",
+			from:    "g3.xsmall",
+			to:      "g2.small",
+			wantErr: "available sizes: g3.xsmall, g3.small, g3.medium",
+		},
+		{
+			name:    "Size of another type",
+			from:    "g3.xsmall",
+			to:      "g4s.kube.medium",
+			wantErr: "is not available for instances",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			target, plan, err := instances.ExportPlanInstanceResize(tc.from, tc.to, tc.disk, tc.stopping, sizes)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("expected an error containing %q, got: %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if target.Name != tc.to {
+				t.Fatalf("expected the target size %s, got: %s", tc.to, target.Name)
+			}
+			if plan != tc.plan {
+				t.Fatalf("expected plan: %q, got: %q", tc.plan, plan)
+			}
+		})
+	}
+}
+
+func TestInstancePowerState(t *testing.T) {
+	s := mockapi.NewServer()
+	defer s.Close()
+	apiClient, err := s.Client(mockapi.DefaultRegion)
+	if err != nil {
+		t.Fatalf("failed to build the client: %s", err)
+	}
+
+	config, err := apiClient.NewInstanceConfig()
+	if err != nil {
+		t.Fatalf("NewInstanceConfig: %s", err)
+	}
+	config.Hostname = "power-state"
+	config.Size = "g3.small"
+	config.TemplateID = "ubuntu-jammy"
+	instance, err := apiClient.CreateInstance(config)
+	if err != nil {
+		t.Fatalf("CreateInstance: %s", err)
+	}
+
+	status := func() string {
+		resp, err := apiClient.GetInstance(instance.ID)
+		if err != nil {
+			t.Fatalf("GetInstance: %s", err)
+		}
+		return resp.Status
+	}
+	ctx := context.Background()
+	stopPath := "/v2/instances/" + instance.ID + "/stop"
+	rebootPath := "/v2/instances/" + instance.ID + "/soft_reboots"
+
+	if err := instances.ExportSetInstancePowerState(ctx, apiClient, instance.ID, "stopped", time.Minute); err != nil {
+		t.Fatalf("failed to stop the instance: %s", err)
+	}
+	if got := status(); got != "SHUTOFF" {
+		t.Fatalf("expected the instance to be SHUTOFF, got: %s", got)
+	}
+
+	// the instance is already stopped, there is nothing to do
+	if err := instances.ExportSetInstancePowerState(ctx, apiClient, instance.ID, "stopped", time.Minute); err != nil {
+		t.Fatalf("failed to stop the instance: %s", err)
+	}
+	if count := s.RequestCount(http.MethodPut, stopPath); count != 1 {
+		t.Fatalf("expected the instance to be stopped once, got: %d", count)
+	}
+	if err := instances.ExportRebootInstance(ctx, apiClient, instance.ID, time.Minute); err != nil {
+		t.Fatalf("failed to reboot the instance: %s", err)
+	}
+	if count := s.RequestCount(http.MethodPost, rebootPath); count != 0 {
+		t.Fatalf("expected a stopped instance not to be rebooted, got %d reboots", count)
+	}
+
+	if err := instances.ExportSetInstancePowerState(ctx, apiClient, instance.ID, "running", time.Minute); err != nil {
+		t.Fatalf("failed to start the instance: %s", err)
+	}
+	if got := status(); got != "ACTIVE" {
+		t.Fatalf("expected the instance to be ACTIVE, got: %s", got)
+	}
+
+	// the reboot is seen in progress for one read, it is waited for until the instance is ACTIVE again
+	s.SetPendingPolls(1)
+	before := s.RequestCount(http.MethodGet, "/v2/instances/"+instance.ID)
+	if err := instances.ExportRebootInstance(ctx, apiClient, instance.ID, time.Minute); err != nil {
+		t.Fatalf("failed to reboot the instance: %s", err)
+	}
+	if count := s.RequestCount(http.MethodPost, rebootPath); count != 1 {
+		t.Fatalf("expected the instance to be rebooted once, got %d reboots", count)
+	}
+	// one read before the reboot, one while rebooting and one once it is ACTIVE again
+	if reads := s.RequestCount(http.MethodGet, "/v2/instances/"+instance.ID) - before; reads < 3 {
+		t.Fatalf("expected the reboot to be waited for, got %d reads", reads)
+	}
+	if got := status(); got != "ACTIVE" {
+		t.Fatalf("expected the instance to be ACTIVE, got: %s", got)
+	}
+}
+
+func TestResizeInstance(t *testing.T) {
+	s := mockapi.NewServer()
+	defer s.Close()
+	apiClient, err := s.Client(mockapi.DefaultRegion)
+	if err != nil {
+		t.Fatalf("failed to build the client: %s", err)
+	}
+
+	config, err := apiClient.NewInstanceConfig()
+	if err != nil {
+		t.Fatalf("NewInstanceConfig: %s", err)
+	}
+	config.Hostname = "resize"
+	config.Size = "g3.small"
+	config.TemplateID = "ubuntu-jammy"
+	instance, err := apiClient.CreateInstance(config)
+	if err != nil {
+		t.Fatalf("CreateInstance: %s", err)
+	}
+
+	// every action is seen in progress for one read
+	s.SetPendingPolls(1)
+	if err := instances.ExportResizeInstance(context.Background(), apiClient, instance.ID, "g3.medium", true, time.Minute); err != nil {
+		t.Fatalf("failed to resize the instance: %s", err)
+	}
+
+	resp, err := apiClient.GetInstance(instance.ID)
+	if err != nil {
+		t.Fatalf("GetInstance: %s", err)
+	}
+	if resp.Size != "g3.medium" || resp.Status != "ACTIVE" {
+		t.Fatalf("expected the instance to be ACTIVE with the size g3.medium, got: %s %s", resp.Status, resp.Size)
+	}
+
+	// the instance is started again only once the resize was seen in progress and then over
+	path := "/v2/instances/" + instance.ID
+	var actions []string
+	readsBefore := map[string]int{}
+	reads := 0
+	for _, r := range s.Requests() {
+		switch r {
+		case http.MethodGet + " " + path:
+			reads++
+		case http.MethodPut + " " + path + "/stop", http.MethodPut + " " + path + "/resize", http.MethodPut + " " + path + "/start":
+			action := r[strings.LastIndex(r, "/")+1:]
+			actions = append(actions, action)
+			readsBefore[action] = reads
+			reads = 0
+		}
+	}
+	if strings.Join(actions, ",") != "stop,resize,start" {
+		t.Fatalf("expected the instance to be stopped, resized and started, got: %v", actions)
+	}
+	if readsBefore["start"] < 2 {
+		t.Fatalf("expected the resize to be waited for before starting the instance, got %d reads", readsBefore["start"])
+	}
+}
+
 func CivoInstanceValues(instance *civogo.Instance, name string) resource.TestCheckFunc {
 	return func(_ *terraform.State) error {
 		if instance.Hostname != name {
@@ -353,3 +622,39 @@ resource "civo_instance" "foobar" {
 	firewall_id = civo_firewall.foobar.id
 }`, hostname)
 }
+
+func CivoInstanceConfigDefaultTags(hostname, defaultTag string) string {
+	return fmt.Sprintf(`
+provider "civo" {
+	default_tags {
+		tags = ["%s"]
+	}
+}
+
+data "civo_size" "small" {
+	filter {
+		key = "name"
+		values = ["g3.small"]
+		match_by = "re"
+	}
+
+	filter {
+		key = "type"
+		values = ["instance"]
+	}
+}
+
+data "civo_disk_image" "debian" {
+	filter {
+		key = "name"
+		values = ["debian-10"]
+	}
+}
+
+resource "civo_instance" "foobar" {
+	hostname = "%s"
+	size = element(data.civo_size.small.sizes, 0).name
+	disk_image = element(data.civo_disk_image.debian.diskimages, 0).id
+	tags = ["web"]
+}`, defaultTag, hostname)
+}
@@ -0,0 +1,113 @@
+package kubernetes
+
+import (
+	"fmt"
+
+	"github.com/civo/civogo"
+	"github.com/civo/terraform-provider-civo/internal/datalist"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// DataSourceKubernetesMarketplace Data source to get and filter all the applications of the Kubernetes marketplace
+func DataSourceKubernetesMarketplace() *schema.Resource {
+	dataListConfig := &datalist.ResourceConfig{
+		Description:         "Provides access to the applications of the Civo Kubernetes marketplace, with their versions, plans and dependencies, to be used with `civo_kubernetes_application` or the `applications` argument of `civo_kubernetes_cluster`. If no filters are specified, all applications will be returned.",
+		RecordSchema:        marketplaceApplicationSchema(),
+		ResultAttributeName: "applications",
+		FlattenRecord:       flattenMarketplaceApplication,
+		GetRecords:          getMarketplaceApplications,
+	}
+
+	return datalist.NewResource(dataListConfig)
+}
+
+func getMarketplaceApplications(m interface{}, _ map[string]interface{}) ([]interface{}, error) {
+	apiClient := m.(*civogo.Client)
+
+	apps, err := apiClient.ListKubernetesMarketplaceApplications()
+	if err != nil {
+		return nil, fmt.Errorf("[ERR] error retrieving the kubernetes marketplace applications: %s", err)
+	}
+
+	var records []interface{}
+	for _, app := range apps {
+		records = append(records, app)
+	}
+
+	return records, nil
+}
+
+func flattenMarketplaceApplication(application, _ interface{}, _ map[string]interface{}) (map[string]interface{}, error) {
+	app := application.(civogo.KubernetesMarketplaceApplication)
+
+	plans := make([]interface{}, 0, len(app.Plans))
+	for _, p := range app.Plans {
+		plans = append(plans, p.Label)
+	}
+
+	dependencies := make([]interface{}, 0, len(app.Dependencies))
+	for _, dep := range app.Dependencies {
+		dependencies = append(dependencies, dep)
+	}
+
+	flattenedApp := map[string]interface{}{}
+	flattenedApp["name"] = app.Name
+	flattenedApp["title"] = app.Title
+	flattenedApp["version"] = app.Version
+	flattenedApp["default"] = app.Default
+	flattenedApp["category"] = app.Category
+	flattenedApp["maintainer"] = app.Maintainer
+	flattenedApp["description"] = app.Description
+	flattenedApp["url"] = app.URL
+	flattenedApp["plans"] = plans
+	flattenedApp["dependencies"] = dependencies
+
+	return flattenedApp, nil
+}
+
+func marketplaceApplicationSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:        schema.TypeString,
+			Description: "The name of the application, used to install it",
+		},
+		"title": {
+			Type:        schema.TypeString,
+			Description: "The title of the application",
+		},
+		"version": {
+			Type:        schema.TypeString,
+			Description: "The version of the application",
+		},
+		"default": {
+			Type:        schema.TypeBool,
+			Description: "If the application is installed in new clusters by default",
+		},
+		"category": {
+			Type:        schema.TypeString,
+			Description: "The category of the application",
+		},
+		"maintainer": {
+			Type:        schema.TypeString,
+			Description: "The maintainer of the application",
+		},
+		"description": {
+			Type:        schema.TypeString,
+			Description: "The description of the application",
+		},
+		"url": {
+			Type:        schema.TypeString,
+			Description: "The URL of the application",
+		},
+		"plans": {
+			Type:        schema.TypeList,
+			Description: "The plans of the application, empty when the application doesn't have plans",
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+		"dependencies": {
+			Type:        schema.TypeList,
+			Description: "The applications installed together with this application",
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+	}
+}
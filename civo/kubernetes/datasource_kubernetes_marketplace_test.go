@@ -0,0 +1,39 @@
+package kubernetes_test
+
+import (
+	"testing"
+
+	"github.com/civo/terraform-provider-civo/civo/acceptance"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceCivoKubernetesMarketplace_basic(t *testing.T) {
+	datasourceName := "data.civo_kubernetes_marketplace.foobar"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { acceptance.TestAccPreCheck(t) },
+		Providers: acceptance.TestAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: DataSourceCivoKubernetesMarketplaceConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(datasourceName, "applications.#", "1"),
+					resource.TestCheckResourceAttr(datasourceName, "applications.0.name", "Redis"),
+					resource.TestCheckResourceAttr(datasourceName, "applications.0.plans.#", "2"),
+					resource.TestCheckResourceAttr(datasourceName, "applications.0.dependencies.0", "Longhorn"),
+				),
+			},
+		},
+	})
+}
+
+func DataSourceCivoKubernetesMarketplaceConfig() string {
+	return `
+data "civo_kubernetes_marketplace" "foobar" {
+	filter {
+		key = "name"
+		values = ["Redis"]
+	}
+}
+`
+}
@@ -0,0 +1,425 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"net/url"
+
+	"github.com/civo/civogo"
+	"github.com/civo/terraform-provider-civo/internal/utils"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"gopkg.in/yaml.v3"
+)
+
+// NewEphemeralKubernetesClusterKubeconfig function returns the ephemeral resource with the kubeconfig of a
+// Kubernetes cluster. The kubeconfig is split into the values needed to configure the kubernetes and helm
+// providers, and as an ephemeral resource it's never saved in the plan or the state.
+func NewEphemeralKubernetesClusterKubeconfig() ephemeral.EphemeralResource {
+	return &ephemeralKubernetesClusterKubeconfig{}
+}
+
+type ephemeralKubernetesClusterKubeconfig struct {
+	apiClient *civogo.Client
+}
+
+var (
+	_ ephemeral.EphemeralResourceWithConfigure      = &ephemeralKubernetesClusterKubeconfig{}
+	_ ephemeral.EphemeralResourceWithValidateConfig = &ephemeralKubernetesClusterKubeconfig{}
+)
+
+// kubeconfigModel is the data of the ephemeral resource
+type kubeconfigModel struct {
+	ClusterID            types.String `tfsdk:"cluster_id"`
+	Name                 types.String `tfsdk:"name"`
+	Region               types.String `tfsdk:"region"`
+	ContextName          types.String `tfsdk:"context_name"`
+	Server               types.String `tfsdk:"server"`
+	Kubeconfig           types.String `tfsdk:"kubeconfig"`
+	Host                 types.String `tfsdk:"host"`
+	ClusterCACertificate types.String `tfsdk:"cluster_ca_certificate"`
+	ClientCertificate    types.String `tfsdk:"client_certificate"`
+	ClientKey            types.String `tfsdk:"client_key"`
+	Token                types.String `tfsdk:"token"`
+	Exec                 types.List   `tfsdk:"exec"`
+}
+
+// kubeconfigExecAttrTypes are the attributes of the exec credential plugin of the kubeconfig
+var kubeconfigExecAttrTypes = map[string]attr.Type{
+	"api_version": types.StringType,
+	"command":     types.StringType,
+	"args":        types.ListType{ElemType: types.StringType},
+	"env":         types.MapType{ElemType: types.StringType},
+}
+
+func (r *ephemeralKubernetesClusterKubeconfig) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_kubernetes_cluster_kubeconfig"
+}
+
+func (r *ephemeralKubernetesClusterKubeconfig) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Provides the kubeconfig of a Civo Kubernetes cluster, parsed into the values used to configure the kubernetes and helm providers. The kubeconfig is read every time Terraform runs, without the need to set `write_kubeconfig` on the cluster, and is never saved in the plan or the state.",
+		Attributes: map[string]schema.Attribute{
+			"cluster_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "The ID of the Kubernetes cluster, exactly one of `cluster_id` and `name` must be set",
+			},
+			"name": schema.StringAttribute{
+				Optional:    true,
+				Description: "The name of the Kubernetes cluster, exactly one of `cluster_id` and `name` must be set",
+			},
+			"region": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "The region where cluster is running",
+			},
+			"context_name": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "The name of the context in the rendered kubeconfig, defaults to the context returned by Civo",
+			},
+			"server": schema.StringAttribute{
+				Optional:    true,
+				Description: "An alternate URL for the API server in the rendered kubeconfig, e.g. when the cluster is reached through a proxy",
+			},
+			// computed attributes
+			"kubeconfig": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The kubeconfig of the cluster, with the `context_name` and `server` applied",
+			},
+			"host": schema.StringAttribute{
+				Computed:    true,
+				Description: "The URL of the API server",
+			},
+			"cluster_ca_certificate": schema.StringAttribute{
+				Computed:    true,
+				Description: "The PEM encoded certificate authority of the API server",
+			},
+			"client_certificate": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The PEM encoded client certificate used to authenticate to the API server, if the kubeconfig uses one",
+			},
+			"client_key": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The PEM encoded private key of the client certificate",
+			},
+			"token": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The bearer token used to authenticate to the API server, if the kubeconfig uses one",
+			},
+			"exec": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.ObjectType{AttrTypes: kubeconfigExecAttrTypes},
+				Description: "The exec plugin that fetches short-lived credentials, if the kubeconfig uses one. It has the `api_version` of the ExecCredential, the `command` with its `args` and the `env` set for the command",
+			},
+		},
+	}
+}
+
+func (r *ephemeralKubernetesClusterKubeconfig) Configure(_ context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	// the provider isn't configured yet when the configuration is validated
+	if req.ProviderData == nil {
+		return
+	}
+
+	apiClient, ok := req.ProviderData.(*civogo.Client)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data", fmt.Sprintf("expected *civogo.Client, got: %T", req.ProviderData))
+		return
+	}
+	r.apiClient = apiClient
+}
+
+func (r *ephemeralKubernetesClusterKubeconfig) ValidateConfig(ctx context.Context, req ephemeral.ValidateConfigRequest, resp *ephemeral.ValidateConfigResponse) {
+	var config kubeconfigModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// unknown values are checked once they are known
+	if config.ClusterID.IsUnknown() || config.Name.IsUnknown() {
+		return
+	}
+	if config.ClusterID.IsNull() == config.Name.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("cluster_id"), "Invalid Attribute Combination", "Exactly one of `cluster_id` and `name` must be set")
+	}
+	for _, value := range []struct {
+		name  string
+		value types.String
+	}{{"cluster_id", config.ClusterID}, {"name", config.Name}, {"region", config.Region}, {"context_name", config.ContextName}} {
+		if !value.value.IsNull() && !value.value.IsUnknown() && value.value.ValueString() == "" {
+			resp.Diagnostics.AddAttributeError(path.Root(value.name), "Invalid Attribute Value", fmt.Sprintf("`%s` can't be empty", value.name))
+		}
+	}
+	if server := config.Server; !server.IsNull() && !server.IsUnknown() {
+		if u, err := url.Parse(server.ValueString()); err != nil || u.Scheme != "https" || u.Host == "" {
+			resp.Diagnostics.AddAttributeError(path.Root("server"), "Invalid Attribute Value", fmt.Sprintf("`server` must be an HTTPS URL, got: %s", server.ValueString()))
+		}
+	}
+}
+
+func (r *ephemeralKubernetesClusterKubeconfig) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data kubeconfigModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if r.apiClient == nil {
+		resp.Diagnostics.AddError("Provider not configured", "The provider must be configured before the kubeconfig can be read")
+		return
+	}
+
+	apiClient := r.apiClient
+	// overwrite the region if it is defined in the ephemeral resource
+	if region := data.Region.ValueString(); region != "" {
+		apiClient = utils.RegionalClient(apiClient, region)
+	}
+
+	search := data.ClusterID.ValueString()
+	if search == "" {
+		search = data.Name.ValueString()
+	}
+
+	log.Printf("[INFO] retrieving the kubeconfig of the kubernetes cluster %s", search)
+	cluster, err := apiClient.FindKubernetesCluster(search)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading the kubernetes cluster", fmt.Sprintf("[ERR] failed to retrive kubernetes cluster: %s", err))
+		return
+	}
+
+	if cluster.KubeConfig == "" {
+		resp.Diagnostics.AddError("Kubeconfig not available", fmt.Sprintf("[ERR] the kubernetes cluster %s doesn't have a kubeconfig yet, it may still be building", cluster.ID))
+		return
+	}
+
+	rendered, err := renderKubeconfig(cluster.KubeConfig, data.ContextName.ValueString(), data.Server.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error rendering the kubeconfig", fmt.Sprintf("[ERR] failed to render the kubeconfig of the kubernetes cluster %s: %s", cluster.ID, err))
+		return
+	}
+
+	credentials, err := parseKubeconfig(rendered)
+	if err != nil {
+		resp.Diagnostics.AddError("Error parsing the kubeconfig", fmt.Sprintf("[ERR] failed to parse the kubeconfig of the kubernetes cluster %s: %s", cluster.ID, err))
+		return
+	}
+
+	data.ClusterID = types.StringValue(cluster.ID)
+	data.Name = types.StringValue(cluster.Name)
+	data.Region = types.StringValue(apiClient.Region)
+	data.ContextName = types.StringValue(credentials.context)
+	data.Kubeconfig = types.StringValue(rendered)
+	data.Host = types.StringValue(credentials.host)
+	data.ClusterCACertificate = types.StringValue(credentials.clusterCACertificate)
+	data.ClientCertificate = types.StringValue(credentials.clientCertificate)
+	data.ClientKey = types.StringValue(credentials.clientKey)
+	data.Token = types.StringValue(credentials.token)
+
+	execType := types.ObjectType{AttrTypes: kubeconfigExecAttrTypes}
+	execs := []attr.Value{}
+	if exec := credentials.exec; exec != nil {
+		args, diags := types.ListValueFrom(ctx, types.StringType, exec.Args)
+		resp.Diagnostics.Append(diags...)
+		env := map[string]string{}
+		for _, e := range exec.Env {
+			env[e.Name] = e.Value
+		}
+		envValue, diags := types.MapValueFrom(ctx, types.StringType, env)
+		resp.Diagnostics.Append(diags...)
+		execValue, diags := types.ObjectValue(kubeconfigExecAttrTypes, map[string]attr.Value{
+			"api_version": types.StringValue(exec.APIVersion),
+			"command":     types.StringValue(exec.Command),
+			"args":        args,
+			"env":         envValue,
+		})
+		resp.Diagnostics.Append(diags...)
+		execs = append(execs, execValue)
+	}
+	execList, diags := types.ListValue(execType, execs)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Exec = execList
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
+
+// kubeconfigFile is the part of a kubeconfig needed to connect to the cluster
+type kubeconfigFile struct {
+	CurrentContext string `yaml:"current-context"`
+	Clusters       []struct {
+		Name    string `yaml:"name"`
+		Cluster struct {
+			Server                   string `yaml:"server"`
+			CertificateAuthorityData string `yaml:"certificate-authority-data"`
+		} `yaml:"cluster"`
+	} `yaml:"clusters"`
+	Contexts []struct {
+		Name    string `yaml:"name"`
+		Context struct {
+			Cluster string `yaml:"cluster"`
+			User    string `yaml:"user"`
+		} `yaml:"context"`
+	} `yaml:"contexts"`
+	Users []struct {
+		Name string `yaml:"name"`
+		User struct {
+			ClientCertificateData string          `yaml:"client-certificate-data"`
+			ClientKeyData         string          `yaml:"client-key-data"`
+			Token                 string          `yaml:"token"`
+			Exec                  *kubeconfigExec `yaml:"exec"`
+		} `yaml:"user"`
+	} `yaml:"users"`
+}
+
+// kubeconfigExec is the exec credential plugin of a kubeconfig user
+type kubeconfigExec struct {
+	APIVersion string   `yaml:"apiVersion"`
+	Command    string   `yaml:"command"`
+	Args       []string `yaml:"args"`
+	Env        []struct {
+		Name  string `yaml:"name"`
+		Value string `yaml:"value"`
+	} `yaml:"env"`
+}
+
+// kubeconfigCredentials are the values of the current context of a kubeconfig
+type kubeconfigCredentials struct {
+	context              string
+	host                 string
+	clusterCACertificate string
+	clientCertificate    string
+	clientKey            string
+	token                string
+	exec                 *kubeconfigExec
+}
+
+// parseKubeconfig function to read the server and credentials of the current context, or
+// of the first context when no current context is set
+func parseKubeconfig(raw string) (*kubeconfigCredentials, error) {
+	config := &kubeconfigFile{}
+	if err := yaml.Unmarshal([]byte(raw), config); err != nil {
+		return nil, err
+	}
+
+	if len(config.Contexts) == 0 {
+		return nil, fmt.Errorf("the kubeconfig doesn't have any context")
+	}
+
+	contextIndex := 0
+	for i, c := range config.Contexts {
+		if c.Name == config.CurrentContext {
+			contextIndex = i
+			break
+		}
+	}
+	current := config.Contexts[contextIndex]
+	credentials := &kubeconfigCredentials{context: current.Name}
+
+	foundCluster := false
+	for _, c := range config.Clusters {
+		if c.Name != current.Context.Cluster {
+			continue
+		}
+		foundCluster = true
+		credentials.host = c.Cluster.Server
+		ca, err := base64.StdEncoding.DecodeString(c.Cluster.CertificateAuthorityData)
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate-authority-data of the cluster %s: %s", c.Name, err)
+		}
+		credentials.clusterCACertificate = string(ca)
+	}
+	if !foundCluster {
+		return nil, fmt.Errorf("the cluster %s of the context %s is not in the kubeconfig", current.Context.Cluster, current.Name)
+	}
+
+	for _, u := range config.Users {
+		if u.Name != current.Context.User {
+			continue
+		}
+		cert, err := base64.StdEncoding.DecodeString(u.User.ClientCertificateData)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client-certificate-data of the user %s: %s", u.Name, err)
+		}
+		key, err := base64.StdEncoding.DecodeString(u.User.ClientKeyData)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client-key-data of the user %s: %s", u.Name, err)
+		}
+		credentials.clientCertificate = string(cert)
+		credentials.clientKey = string(key)
+		credentials.token = u.User.Token
+		credentials.exec = u.User.Exec
+	}
+
+	return credentials, nil
+}
+
+// renderKubeconfig function to rename the current context and replace the server of its cluster,
+// the kubeconfig is returned untouched when there is nothing to change
+func renderKubeconfig(raw, contextName, server string) (string, error) {
+	if contextName == "" && server == "" {
+		return raw, nil
+	}
+
+	config := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(raw), &config); err != nil {
+		return "", err
+	}
+
+	contexts, _ := config["contexts"].([]interface{})
+	if len(contexts) == 0 {
+		return "", fmt.Errorf("the kubeconfig doesn't have any context")
+	}
+
+	currentContext, _ := config["current-context"].(string)
+	var current map[string]interface{}
+	for _, rawContext := range contexts {
+		c, ok := rawContext.(map[string]interface{})
+		if ok && (current == nil || c["name"] == currentContext) {
+			current = c
+		}
+	}
+	if current == nil {
+		return "", fmt.Errorf("the kubeconfig doesn't have any valid context")
+	}
+
+	if contextName != "" {
+		current["name"] = contextName
+		config["current-context"] = contextName
+	}
+
+	if server != "" {
+		clusterName := ""
+		if c, ok := current["context"].(map[string]interface{}); ok {
+			clusterName, _ = c["cluster"].(string)
+		}
+		clusters, _ := config["clusters"].([]interface{})
+		for _, rawCluster := range clusters {
+			c, ok := rawCluster.(map[string]interface{})
+			if !ok || c["name"] != clusterName {
+				continue
+			}
+			if cluster, ok := c["cluster"].(map[string]interface{}); ok {
+				cluster["server"] = server
+			}
+		}
+	}
+
+	rendered, err := yaml.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	return string(rendered), nil
+}
@@ -0,0 +1,105 @@
+package kubernetes_test
+
+import (
+	"testing"
+
+	"github.com/civo/terraform-provider-civo/civo/kubernetes"
+)
+
+const testKubeconfig = `apiVersion: v1
+clusters:
+- cluster:
+    certificate-authority-data: LS0tLS1CRUdJTiBDRVJUSUZJQ0FURS0tLS0tCm1vY2sKLS0tLS1FTkQgQ0VSVElGSUNBVEUtLS0tLQo=
+    server: https://74.220.1.1:6443
+  name: my-cluster
+contexts:
+- context:
+    cluster: my-cluster
+    user: my-cluster
+  name: my-cluster
+current-context: my-cluster
+kind: Config
+preferences: {}
+users:
+- name: my-cluster
+  user:
+    token: secret-token
+`
+
+func TestParseKubeconfig(t *testing.T) {
+	host, ca, context, token, err := kubernetes.ExportParseKubeconfig(testKubeconfig)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if host != "https://74.220.1.1:6443" {
+		t.Fatalf("unexpected host: %s", host)
+	}
+	if ca != "-----BEGIN CERTIFICATE-----\nmock\n-----END CERTIFICATE-----\n" {
+		t.Fatalf("unexpected certificate authority: %q", ca)
+	}
+	if context != "my-cluster" || token != "secret-token" {
+		t.Fatalf("unexpected context %s or token %s", context, token)
+	}
+
+	if _, _, _, _, err := kubernetes.ExportParseKubeconfig("apiVersion: v1\nkind: Config\n"); err == nil {
+		t.Fatal("expected an error for a kubeconfig without contexts")
+	}
+}
+
+func TestRenderKubeconfig(t *testing.T) {
+	unchanged, err := kubernetes.ExportRenderKubeconfig(testKubeconfig, "", "")
+	if err != nil || unchanged != testKubeconfig {
+		t.Fatalf("expected the kubeconfig to be untouched, got: %s (%v)", unchanged, err)
+	}
+
+	rendered, err := kubernetes.ExportRenderKubeconfig(testKubeconfig, "production", "https://proxy.example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	host, _, context, token, err := kubernetes.ExportParseKubeconfig(rendered)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if host != "https://proxy.example.com" || context != "production" || token != "secret-token" {
+		t.Fatalf("unexpected rendered kubeconfig: %s", rendered)
+	}
+}
+
+func TestParseKubeconfigExec(t *testing.T) {
+	raw := `apiVersion: v1
+clusters:
+- cluster:
+    certificate-authority-data: LS0tLS1CRUdJTiBDRVJUSUZJQ0FURS0tLS0tCm1vY2sKLS0tLS1FTkQgQ0VSVElGSUNBVEUtLS0tLQo=
+    server: https://74.220.1.1:6443
+  name: my-cluster
+contexts:
+- context:
+    cluster: my-cluster
+    user: oidc
+  name: my-cluster
+current-context: my-cluster
+kind: Config
+users:
+- name: oidc
+  user:
+    exec:
+      apiVersion: client.authentication.k8s.io/v1beta1
+      command: kubectl
+      args:
+      - oidc-login
+      - get-token
+`
+
+	command, args, err := kubernetes.ExportParseKubeconfigExec(raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if command != "kubectl" || len(args) != 2 || args[0] != "oidc-login" {
+		t.Fatalf("unexpected exec plugin: %s %v", command, args)
+	}
+
+	if command, _, err := kubernetes.ExportParseKubeconfigExec(testKubeconfig); err != nil || command != "" {
+		t.Fatalf("expected no exec plugin, got: %q (%v)", command, err)
+	}
+}
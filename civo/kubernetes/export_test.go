@@ -8,3 +8,57 @@ import (
 func ExportFlattenNodePool(cluster *civogo.KubernetesCluster) []interface{} {
 	return flattenNodePool(cluster)
 }
+
+// ExportOrderNodePools exports orderNodePools for testing
+func ExportOrderNodePools(pools []interface{}, labels []string) []interface{} {
+	return orderNodePools(pools, labels)
+}
+
+// ExportValidateNodePoolAutoscaling exports validateNodePoolAutoscaling for testing
+func ExportValidateNodePoolAutoscaling(name string, pool map[string]interface{}) error {
+	return validateNodePoolAutoscaling(name, pool)
+}
+
+// ExportKeepNodePoolAutoscaling exports keepNodePoolAutoscaling for testing
+func ExportKeepNodePoolAutoscaling(pools []interface{}, statePools []interface{}) {
+	keepNodePoolAutoscaling(pools, statePools)
+}
+
+// ExportSameKubernetesVersion exports sameKubernetesVersion for testing
+func ExportSameKubernetesVersion(a, b string) bool {
+	return sameKubernetesVersion(a, b)
+}
+
+// ExportPlanKubernetesUpgrade exports planKubernetesUpgrade for testing
+func ExportPlanKubernetesUpgrade(from, to string, versions []civogo.KubernetesVersion) ([]string, []string, error) {
+	return planKubernetesUpgrade(from, to, versions)
+}
+
+// ExportRenderKubeconfig exports renderKubeconfig for testing
+func ExportRenderKubeconfig(raw, contextName, server string) (string, error) {
+	return renderKubeconfig(raw, contextName, server)
+}
+
+// ExportParseKubeconfig exports parseKubeconfig for testing, returning the host, CA, context and token
+func ExportParseKubeconfig(raw string) (string, string, string, string, error) {
+	credentials, err := parseKubeconfig(raw)
+	if err != nil {
+		return "", "", "", "", err
+	}
+	return credentials.host, credentials.clusterCACertificate, credentials.context, credentials.token, nil
+}
+
+// ExportParseKubeconfigExec exports parseKubeconfig for testing, returning the command and the
+// arguments of the exec plugin
+func ExportParseKubeconfigExec(raw string) (string, []string, error) {
+	credentials, err := parseKubeconfig(raw)
+	if err != nil || credentials.exec == nil {
+		return "", nil, err
+	}
+	return credentials.exec.Command, credentials.exec.Args, nil
+}
+
+// ExportFindMarketplaceApplication exports findMarketplaceApplication for testing
+func ExportFindMarketplaceApplication(apps []civogo.KubernetesMarketplaceApplication, name, plan string) (*civogo.KubernetesMarketplaceApplication, error) {
+	return findMarketplaceApplication(apps, name, plan)
+}
@@ -1,9 +1,13 @@
 package kubernetes
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"log"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/civo/civogo"
 	"github.com/civo/terraform-provider-civo/internal/utils"
@@ -24,9 +28,28 @@ func nodePoolSchema(isResource bool) map[string]*schema.Schema {
 			Description:      "Node pool label, if you don't provide one, we will generate one for you",
 		},
 		"node_count": {
+			Type:             schema.TypeInt,
+			Required:         true,
+			Description:      "Number of nodes in the nodepool, when `autoscaling` is enabled this is the initial number of nodes and changes made by the autoscaler are ignored",
+			ValidateFunc:     validation.IntAtLeast(1),
+			DiffSuppressFunc: suppressAutoscaledNodeCount,
+		},
+		"autoscaling": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Enable the cluster autoscaler for the nodepool, the autoscaler is installed from the marketplace if the cluster doesn't have it yet. The node limits the autoscaler scales the pool between are set in the configuration of the autoscaler, not by the provider",
+		},
+		"ignored_node_count_min": {
 			Type:         schema.TypeInt,
-			Required:     true,
-			Description:  "Number of nodes in the nodepool",
+			Optional:     true,
+			Description:  "Lowest node count of the pool that is accepted instead of `node_count` in the plan, so the count set by the autoscaler isn't reverted. It isn't sent to the autoscaler, keep it in line with the limits of the autoscaler. Required when `autoscaling` is enabled",
+			ValidateFunc: validation.IntAtLeast(1),
+		},
+		"ignored_node_count_max": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Description:  "Highest node count of the pool that is accepted instead of `node_count` in the plan, so the count set by the autoscaler isn't reverted. It isn't sent to the autoscaler, keep it in line with the limits of the autoscaler. Required when `autoscaling` is enabled",
 			ValidateFunc: validation.IntAtLeast(1),
 		},
 		"size": {
@@ -93,27 +116,18 @@ func nodePoolSchema(isResource bool) map[string]*schema.Schema {
 	return s
 }
 
-// function to flatten all instances inside the cluster
+// function to flatten all node pools inside the cluster, pools that are still
+// being created are only present in the required pools
 func flattenNodePool(cluster *civogo.KubernetesCluster) []interface{} {
 	if len(cluster.Pools) == 0 && len(cluster.RequiredPools) == 0 {
 		return nil
 	}
 
-	var poolID string
-	var count int
-	var size string
-	var labels map[string]string
-	var taints []corev1.Taint
-	var publicIP bool
-	var instanceNames []string
-
-	if len(cluster.Pools) > 0 {
-		p := cluster.Pools[0]
-		poolID = p.ID
-		count = p.Count
-		size = p.Size
-		labels = p.Labels
-		taints = p.Taints
+	flattenedPool := make([]interface{}, 0, len(cluster.Pools))
+	seen := make(map[string]bool, len(cluster.Pools))
+	for _, p := range cluster.Pools {
+		labels := p.Labels
+		taints := p.Taints
 		for _, rp := range cluster.RequiredPools {
 			if rp.ID == p.ID {
 				labels = rp.Labels
@@ -121,19 +135,23 @@ func flattenNodePool(cluster *civogo.KubernetesCluster) []interface{} {
 				break
 			}
 		}
-		publicIP = p.PublicIPNodePool
-		instanceNames = p.InstanceNames
-	} else {
-		p := cluster.RequiredPools[0]
-		poolID = p.ID
-		count = p.Count
-		size = p.Size
-		labels = p.Labels
-		taints = p.Taints
-		publicIP = p.PublicIPNodePool
-	}
-
-	flattenedPool := make([]interface{}, 0)
+		seen[p.ID] = true
+		rawPool := flattenKubernetesPool(p.ID, p.Count, p.Size, labels, taints, p.PublicIPNodePool, p.InstanceNames)
+		flattenedPool = append(flattenedPool, rawPool)
+	}
+
+	for _, rp := range cluster.RequiredPools {
+		if !seen[rp.ID] {
+			rawPool := flattenKubernetesPool(rp.ID, rp.Count, rp.Size, rp.Labels, rp.Taints, rp.PublicIPNodePool, nil)
+			flattenedPool = append(flattenedPool, rawPool)
+		}
+	}
+
+	return flattenedPool
+}
+
+// flattenKubernetesPool function to flatten a single node pool
+func flattenKubernetesPool(poolID string, count int, size string, labels map[string]string, taints []corev1.Taint, publicIP bool, instanceNames []string) map[string]interface{} {
 	poolInstanceNames := make([]string, 0)
 	if len(instanceNames) > 0 {
 		poolInstanceNames = append(poolInstanceNames, instanceNames...)
@@ -173,9 +191,128 @@ func flattenNodePool(cluster *civogo.KubernetesCluster) []interface{} {
 		rawPool["taint"] = rawTaints
 	}
 
-	flattenedPool = append(flattenedPool, rawPool)
+	return rawPool
+}
 
-	return flattenedPool
+// keepNodePoolAutoscaling function to copy the autoscaling arguments of the pools in the
+// state to the pools read from the API. The API doesn't return them, the configuration only
+// tells the provider which node count changes to leave to the autoscaler
+func keepNodePoolAutoscaling(pools []interface{}, statePools []interface{}) {
+	byLabel := make(map[string]map[string]interface{}, len(statePools))
+	for _, rawPool := range statePools {
+		if pool, ok := rawPool.(map[string]interface{}); ok {
+			if label, ok := pool["label"].(string); ok {
+				byLabel[label] = pool
+			}
+		}
+	}
+
+	for _, rawPool := range pools {
+		pool := rawPool.(map[string]interface{})
+		statePool, ok := byLabel[pool["label"].(string)]
+		if !ok {
+			continue
+		}
+		for _, key := range []string{"autoscaling", "ignored_node_count_min", "ignored_node_count_max"} {
+			if v, ok := statePool[key]; ok {
+				pool[key] = v
+			}
+		}
+	}
+}
+
+// orderNodePools function to keep only the pools managed by the cluster resource, in the
+// order they already have in the state. When the state has no pools (e.g. on import) every
+// pool is returned, pools added with civo_kubernetes_node_pool are otherwise left out
+func orderNodePools(pools []interface{}, labels []string) []interface{} {
+	if len(labels) == 0 {
+		return pools
+	}
+
+	byLabel := make(map[string]interface{}, len(pools))
+	for _, rawPool := range pools {
+		byLabel[rawPool.(map[string]interface{})["label"].(string)] = rawPool
+	}
+
+	orderedPools := make([]interface{}, 0, len(labels))
+	for _, label := range labels {
+		if rawPool, ok := byLabel[label]; ok {
+			orderedPools = append(orderedPools, rawPool)
+		}
+	}
+
+	// none of the known pools exists anymore, fall back to the default pool
+	if len(orderedPools) == 0 && len(pools) > 0 {
+		orderedPools = append(orderedPools, pools[0])
+	}
+
+	return orderedPools
+}
+
+// suppressReorderedNodePools function to ignore the changes of the pools when they were only
+// reordered in the configuration. The pools are matched by label, so a plan that moves them
+// around without changing any of them doesn't touch the cluster
+func suppressReorderedNodePools(_, _, _ string, d *schema.ResourceData) bool {
+	oldRaw, newRaw := d.GetChange("pools")
+	oldPools, _ := oldRaw.([]interface{})
+	newPools, _ := newRaw.([]interface{})
+	if len(oldPools) == 0 || len(oldPools) != len(newPools) {
+		return false
+	}
+
+	oldByLabel := make(map[string]map[string]interface{}, len(oldPools))
+	for _, rawPool := range oldPools {
+		if pool, ok := rawPool.(map[string]interface{}); ok {
+			oldByLabel[pool["label"].(string)] = pool
+		}
+	}
+
+	seen := make(map[string]bool, len(newPools))
+	for _, rawPool := range newPools {
+		newPool, ok := rawPool.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		label, _ := newPool["label"].(string)
+		oldPool, ok := oldByLabel[label]
+		if label == "" || !ok || seen[label] || !sameNodePool(oldPool, newPool) {
+			return false
+		}
+		seen[label] = true
+	}
+	return true
+}
+
+// sameNodePool function to check a pool of the configuration matches the pool with its label in
+// the state, the node count set by the autoscaler is ignored like in the plan
+func sameNodePool(oldPool, newPool map[string]interface{}) bool {
+	for _, key := range []string{"size", "autoscaling", "ignored_node_count_min", "ignored_node_count_max"} {
+		if oldPool[key] != newPool[key] {
+			return false
+		}
+	}
+
+	pool := make(map[string]interface{}, len(newPool))
+	for k, v := range newPool {
+		pool[k] = v
+	}
+	if count := oldPool["node_count"].(int); pool["autoscaling"].(bool) && count >= pool["ignored_node_count_min"].(int) && count <= pool["ignored_node_count_max"].(int) {
+		pool["node_count"] = count
+	}
+	return !nodePoolChanged(oldPool, pool)
+}
+
+// nodePoolLabels function to return the label of every pool in the list
+func nodePoolLabels(pools []interface{}) []string {
+	labels := make([]string, 0, len(pools))
+	for _, rawPool := range pools {
+		if pool, ok := rawPool.(map[string]interface{}); ok {
+			if label, ok := pool["label"].(string); ok && label != "" {
+				labels = append(labels, label)
+			}
+		}
+	}
+	return labels
 }
 
 // function to flatten all applications inside the cluster
@@ -203,53 +340,67 @@ func flattenInstalledApplication(apps []civogo.KubernetesInstalledApplication) [
 func expandNodePools(nodePools []interface{}) []civogo.KubernetesClusterPoolConfig {
 	expandedNodePools := make([]civogo.KubernetesClusterPoolConfig, 0, len(nodePools))
 	for _, rawPool := range nodePools {
-		pool := rawPool.(map[string]interface{})
+		expandedNodePools = append(expandedNodePools, expandNodePool(rawPool.(map[string]interface{})))
+	}
 
-		poolID := uuid.NewString()
-		if pool["label"].(string) != "" {
-			poolID = pool["label"].(string)
-		}
+	return expandedNodePools
+}
 
-		// Initialize labels map only if they are provided and valid
-		var labels map[string]string
-		if rawLabels, ok := pool["labels"].(map[string]interface{}); ok {
-			labels = make(map[string]string, len(rawLabels))
-			for k, v := range rawLabels {
-				if strVal, ok := v.(string); ok {
-					labels[k] = strVal
-				}
-			}
-		}
+// expandNodePool function to expand a single node pool, a label is generated when none is set
+func expandNodePool(pool map[string]interface{}) civogo.KubernetesClusterPoolConfig {
+	poolID := uuid.NewString()
+	if label, ok := pool["label"].(string); ok && label != "" {
+		poolID = label
+	}
 
-		// Initialize taints slice only if they are provided and valid
-		var taints []corev1.Taint
-		if taintSet, ok := pool["taint"].(*schema.Set); ok {
-			for _, taintInterface := range taintSet.List() {
-				taintMap := taintInterface.(map[string]interface{})
-				taints = append(taints, corev1.Taint{
-					Key:    taintMap["key"].(string),
-					Value:  taintMap["value"].(string),
-					Effect: corev1.TaintEffect(taintMap["effect"].(string)),
-				})
-			}
-		}
+	cr := civogo.KubernetesClusterPoolConfig{
+		ID:     poolID,
+		Size:   pool["size"].(string),
+		Count:  pool["node_count"].(int),
+		Labels: expandNodePoolLabels(pool["labels"]),
+		Taints: expandNodePoolTaints(pool["taint"]),
+	}
 
-		cr := civogo.KubernetesClusterPoolConfig{
-			ID:     poolID,
-			Size:   pool["size"].(string),
-			Count:  pool["node_count"].(int),
-			Labels: labels,
-			Taints: taints,
-		}
+	if pool["public_ip_node_pool"].(bool) {
+		cr.PublicIPNodePool = pool["public_ip_node_pool"].(bool)
+	}
+
+	return cr
+}
+
+// expandNodePoolLabels function to expand the labels of a node pool, nil when none are set
+func expandNodePoolLabels(raw interface{}) map[string]string {
+	rawLabels, ok := raw.(map[string]interface{})
+	if !ok || len(rawLabels) == 0 {
+		return nil
+	}
 
-		if pool["public_ip_node_pool"].(bool) {
-			cr.PublicIPNodePool = pool["public_ip_node_pool"].(bool)
+	labels := make(map[string]string, len(rawLabels))
+	for k, v := range rawLabels {
+		if strVal, ok := v.(string); ok {
+			labels[k] = strVal
 		}
+	}
+	return labels
+}
 
-		expandedNodePools = append(expandedNodePools, cr)
+// expandNodePoolTaints function to expand the taints of a node pool, nil when none are set
+func expandNodePoolTaints(raw interface{}) []corev1.Taint {
+	taintSet, ok := raw.(*schema.Set)
+	if !ok || taintSet == nil || taintSet.Len() == 0 {
+		return nil
 	}
 
-	return expandedNodePools
+	taints := make([]corev1.Taint, 0, taintSet.Len())
+	for _, taintInterface := range taintSet.List() {
+		taintMap := taintInterface.(map[string]interface{})
+		taints = append(taints, corev1.Taint{
+			Key:    taintMap["key"].(string),
+			Value:  taintMap["value"].(string),
+			Effect: corev1.TaintEffect(taintMap["effect"].(string)),
+		})
+	}
+	return taints
 }
 
 // kubernetesClusterPoolUpdatePayload defines the payload for updating a Kubernetes cluster node pool.
@@ -279,3 +430,74 @@ func updateKubernetesClusterPoolHelper(client *civogo.Client, cid, pid string, c
 
 	return pool, nil
 }
+
+// autoscalerApplication is the marketplace application of the cluster autoscaler
+const autoscalerApplication = "civo-cluster-autoscaler"
+
+// validateNodePoolAutoscaling function to check the autoscaling arguments of a pool, the node
+// counts the plan ignores have to be set together and contain node_count
+func validateNodePoolAutoscaling(name string, pool map[string]interface{}) error {
+	enabled, _ := pool["autoscaling"].(bool)
+	minNodes, _ := pool["ignored_node_count_min"].(int)
+	maxNodes, _ := pool["ignored_node_count_max"].(int)
+
+	if !enabled {
+		if minNodes != 0 || maxNodes != 0 {
+			return fmt.Errorf("ignored_node_count_min and ignored_node_count_max of the pool %s are only used when autoscaling is enabled", name)
+		}
+		return nil
+	}
+
+	if minNodes == 0 || maxNodes == 0 {
+		return fmt.Errorf("autoscaling of the pool %s needs both ignored_node_count_min and ignored_node_count_max", name)
+	}
+	if minNodes > maxNodes {
+		return fmt.Errorf("ignored_node_count_min (%d) of the pool %s can't be greater than ignored_node_count_max (%d)", minNodes, name, maxNodes)
+	}
+	if count, ok := pool["node_count"].(int); ok && count != 0 && (count < minNodes || count > maxNodes) {
+		return fmt.Errorf("node_count (%d) of the pool %s must be between ignored_node_count_min (%d) and ignored_node_count_max (%d)", count, name, minNodes, maxNodes)
+	}
+	return nil
+}
+
+// suppressAutoscaledNodeCount function to ignore the node count set by the autoscaler, as long as
+// the current number of nodes is between ignored_node_count_min and ignored_node_count_max
+func suppressAutoscaledNodeCount(k, old, _ string, d *schema.ResourceData) bool {
+	prefix := strings.TrimSuffix(k, "node_count")
+	if enabled, ok := d.Get(prefix + "autoscaling").(bool); !ok || !enabled {
+		return false
+	}
+
+	current, err := strconv.Atoi(old)
+	if err != nil || current == 0 {
+		return false
+	}
+
+	return current >= d.Get(prefix+"ignored_node_count_min").(int) && current <= d.Get(prefix+"ignored_node_count_max").(int)
+}
+
+// ensureClusterAutoscaler function to install the cluster autoscaler from the marketplace,
+// nothing is done when the cluster already has it
+func ensureClusterAutoscaler(ctx context.Context, apiClient *civogo.Client, clusterID string, timeout time.Duration) error {
+	cluster, err := apiClient.GetKubernetesCluster(clusterID)
+	if err != nil {
+		return err
+	}
+
+	for _, app := range cluster.InstalledApplications {
+		if strings.EqualFold(app.Name, autoscalerApplication) {
+			return nil
+		}
+	}
+
+	log.Printf("[INFO] installing the %s application in the kubernetes cluster %s", autoscalerApplication, clusterID)
+	_, err = apiClient.UpdateKubernetesCluster(clusterID, &civogo.KubernetesClusterConfig{
+		Applications: autoscalerApplication,
+		Region:       apiClient.Region,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to install %s: %s", autoscalerApplication, err)
+	}
+
+	return waitForClusterActive(ctx, apiClient, clusterID, timeout)
+}
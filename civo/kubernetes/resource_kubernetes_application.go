@@ -0,0 +1,327 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/civo/civogo"
+	"github.com/civo/terraform-provider-civo/internal/utils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// ResourceKubernetesApplication function returns a schema.Resource that represents one
+// marketplace application installed in a Kubernetes cluster
+func ResourceKubernetesApplication() *schema.Resource {
+	return &schema.Resource{
+		Description: "Provides a Civo Kubernetes marketplace application, installed in an existing cluster. This is an alternative to the `applications` argument of `civo_kubernetes_cluster` where every application is managed on its own.",
+		Schema: map[string]*schema.Schema{
+			"cluster_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: utils.ValidateUUID,
+				Description:  "The ID of the Kubernetes cluster to install the application in",
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+				Description:  "The name of the marketplace application, as listed by the `civo_kubernetes_marketplace` data source. Names are case-sensitive",
+			},
+			"plan": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.NoZeroValues,
+				Description:  "The plan of the application, only for applications that have plans",
+			},
+			"region": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				Description:      "The region of the cluster, if not declare we use the region in declared in the provider",
+				DiffSuppressFunc: utils.IgnoreCaseDiff,
+			},
+			// Computed resource
+			"version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The version of the installed application",
+			},
+			"category": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The category of the application",
+			},
+			"installed": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the application has finished installing",
+			},
+		},
+		CreateContext: resourceKubernetesApplicationCreate,
+		ReadContext:   resourceKubernetesApplicationRead,
+		DeleteContext: resourceKubernetesApplicationDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceKubernetesApplicationImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
+		CustomizeDiff: customizeDiffKubernetesApplication,
+	}
+}
+
+// customizeDiffKubernetesApplication function to check the application and plan against
+// the marketplace, so a typo fails at plan time
+func customizeDiffKubernetesApplication(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" && !d.HasChanges("name", "plan") {
+		return nil
+	}
+	if !d.NewValueKnown("name") || !d.NewValueKnown("plan") {
+		return nil
+	}
+
+	apiClient := meta.(*civogo.Client)
+	apps, err := apiClient.ListKubernetesMarketplaceApplications()
+	if err != nil {
+		return fmt.Errorf("failed to list the kubernetes marketplace applications: %s", err)
+	}
+
+	_, err = findMarketplaceApplication(apps, d.Get("name").(string), d.Get("plan").(string))
+	return err
+}
+
+// function to install the application in the cluster
+func resourceKubernetesApplicationCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*civogo.Client)
+
+	// overwrite the region if is defined in the datasource
+	if region, ok := d.GetOk("region"); ok {
+		apiClient = utils.RegionalClient(apiClient, region.(string))
+	}
+
+	clusterID := d.Get("cluster_id").(string)
+	name := d.Get("name").(string)
+
+	cluster, err := apiClient.GetKubernetesCluster(clusterID)
+	if err != nil {
+		return diag.Errorf("[ERR] failed to retrive kubernetes cluster: %s", err)
+	}
+	if findInstalledApplication(cluster.InstalledApplications, name) != nil {
+		return diag.Errorf("[ERR] the application %s is already installed in the kubernetes cluster %s, import it instead", name, clusterID)
+	}
+
+	// the cluster only takes one change at a time
+	if err := waitForClusterActive(ctx, apiClient, clusterID, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return diag.Errorf("[ERR] error waiting for kubernetes cluster (%s) to be ready: %s", clusterID, err)
+	}
+
+	application := name
+	if plan, ok := d.GetOk("plan"); ok {
+		application = fmt.Sprintf("%s:%s", name, plan.(string))
+	}
+
+	log.Printf("[INFO] installing the application %s in the kubernetes cluster %s", application, clusterID)
+	_, err = apiClient.UpdateKubernetesCluster(clusterID, &civogo.KubernetesClusterConfig{
+		Applications: application,
+		Region:       apiClient.Region,
+	})
+	if err != nil {
+		return diag.Errorf("[ERR] failed to install the application %s: %s", name, err)
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s", clusterID, name))
+
+	if err := waitForClusterActive(ctx, apiClient, clusterID, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return diag.Errorf("[ERR] error waiting for the application %s to be installed: %s", name, err)
+	}
+
+	return resourceKubernetesApplicationRead(ctx, d, m)
+}
+
+// function to read the application from the cluster
+func resourceKubernetesApplicationRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*civogo.Client)
+
+	// overwrite the region if is defined in the datasource
+	if region, ok := d.GetOk("region"); ok {
+		apiClient = utils.RegionalClient(apiClient, region.(string))
+	}
+
+	clusterID, name, err := utils.ResourceCommonParseID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO] retrieving the application %s of the kubernetes cluster %s", name, clusterID)
+	cluster, err := apiClient.GetKubernetesCluster(clusterID)
+	if err != nil {
+		if errors.Is(err, civogo.DatabaseKubernetesClusterNotFoundError) {
+			log.Printf("[INFO] kubernetes cluster %s not found", clusterID)
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("[ERR] failed to retrive kubernetes cluster: %s", err)
+	}
+
+	app := findInstalledApplication(cluster.InstalledApplications, name)
+	if app == nil {
+		log.Printf("[INFO] the application %s is not installed in the kubernetes cluster %s", name, clusterID)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("cluster_id", clusterID)
+	d.Set("name", app.Name)
+	d.Set("region", apiClient.Region)
+	d.Set("version", app.Version)
+	d.Set("category", app.Category)
+	d.Set("installed", app.Installed)
+	// the plan is not always returned, in that case we keep the configured one
+	if app.Plan != "" {
+		d.Set("plan", app.Plan)
+	}
+
+	return nil
+}
+
+// function to remove the application from the cluster
+func resourceKubernetesApplicationDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*civogo.Client)
+
+	// overwrite the region if is defined in the datasource
+	if region, ok := d.GetOk("region"); ok {
+		apiClient = utils.RegionalClient(apiClient, region.(string))
+	}
+
+	clusterID, name, err := utils.ResourceCommonParseID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := waitForClusterActive(ctx, apiClient, clusterID, d.Timeout(schema.TimeoutDelete)); err != nil {
+		if errors.Is(err, civogo.DatabaseKubernetesClusterNotFoundError) {
+			return nil
+		}
+		return diag.Errorf("[ERR] error waiting for kubernetes cluster (%s) to be ready: %s", clusterID, err)
+	}
+
+	log.Printf("[INFO] removing the application %s from the kubernetes cluster %s", name, clusterID)
+	_, err = apiClient.UpdateKubernetesCluster(clusterID, &civogo.KubernetesClusterConfig{
+		Applications: fmt.Sprintf("-%s", name),
+		Region:       apiClient.Region,
+	})
+	if err != nil {
+		if errors.Is(err, civogo.DatabaseKubernetesClusterNotFoundError) {
+			return nil
+		}
+		return diag.Errorf("[ERR] failed to remove the application %s: %s", name, err)
+	}
+
+	if err := waitForClusterActive(ctx, apiClient, clusterID, d.Timeout(schema.TimeoutDelete)); err != nil {
+		return diag.Errorf("[ERR] error waiting for the application %s to be removed: %s", name, err)
+	}
+
+	return nil
+}
+
+// resourceKubernetesApplicationImport function to import an application using cluster_id:name,
+// the cluster is searched in all the regions
+func resourceKubernetesApplicationImport(_ context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	apiClient := m.(*civogo.Client)
+
+	clusterID, name, err := utils.ResourceCommonParseID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	regions, err := apiClient.ListRegions()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, region := range regions {
+		regionalClient := utils.RegionalClient(apiClient, region.Code)
+
+		log.Printf("[INFO] retrieving the kubernetes cluster %s from region %s", clusterID, region.Code)
+		cluster, err := regionalClient.GetKubernetesCluster(clusterID)
+		if err != nil {
+			continue
+		}
+
+		app := findInstalledApplication(cluster.InstalledApplications, name)
+		if app == nil {
+			return nil, fmt.Errorf("the application %s is not installed in the kubernetes cluster %s", name, clusterID)
+		}
+
+		d.SetId(fmt.Sprintf("%s:%s", clusterID, app.Name))
+		d.Set("region", region.Code)
+		return []*schema.ResourceData{d}, nil
+	}
+
+	return nil, fmt.Errorf("the kubernetes cluster %s could not be found in any region", clusterID)
+}
+
+// findInstalledApplication function to find an application by name in the installed applications of a cluster
+func findInstalledApplication(apps []civogo.KubernetesInstalledApplication, name string) *civogo.KubernetesInstalledApplication {
+	for i := range apps {
+		if strings.EqualFold(apps[i].Name, name) {
+			return &apps[i]
+		}
+	}
+	return nil
+}
+
+// findMarketplaceApplication function to find an application and check its plan, the error
+// suggests the closest names when the application doesn't exist
+func findMarketplaceApplication(apps []civogo.KubernetesMarketplaceApplication, name, plan string) (*civogo.KubernetesMarketplaceApplication, error) {
+	var app *civogo.KubernetesMarketplaceApplication
+	var suggestions []string
+	for i := range apps {
+		switch {
+		case apps[i].Name == name:
+			app = &apps[i]
+		case strings.EqualFold(apps[i].Name, name),
+			strings.Contains(strings.ToLower(apps[i].Name), strings.ToLower(name)),
+			strings.Contains(strings.ToLower(name), strings.ToLower(apps[i].Name)):
+			suggestions = append(suggestions, apps[i].Name)
+		}
+	}
+
+	if app == nil {
+		if len(suggestions) > 0 {
+			sort.Strings(suggestions)
+			return nil, fmt.Errorf("the application %s is not in the kubernetes marketplace, did you mean %s?", name, strings.Join(suggestions, ", "))
+		}
+		return nil, fmt.Errorf("the application %s is not in the kubernetes marketplace", name)
+	}
+
+	if plan == "" {
+		return app, nil
+	}
+
+	plans := make([]string, 0, len(app.Plans))
+	for _, p := range app.Plans {
+		if p.Label == plan {
+			return app, nil
+		}
+		plans = append(plans, p.Label)
+	}
+
+	if len(plans) == 0 {
+		return nil, fmt.Errorf("the application %s doesn't have plans, remove the plan %s", name, plan)
+	}
+	return nil, fmt.Errorf("the plan %s is not available for the application %s, the available plans are: %s", plan, name, strings.Join(plans, ", "))
+}
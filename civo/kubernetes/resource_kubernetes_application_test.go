@@ -0,0 +1,103 @@
+package kubernetes_test
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/civo/civogo"
+	"github.com/civo/terraform-provider-civo/civo/acceptance"
+	"github.com/civo/terraform-provider-civo/civo/kubernetes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCivoKubernetesApplication_basic(t *testing.T) {
+	var cluster civogo.KubernetesCluster
+
+	resName := "civo_kubernetes_cluster.foobar"
+	resAppName := "civo_kubernetes_application.foobar"
+	var kubernetesClusterName = acctest.RandomWithPrefix("tf-test") + "-example"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { acceptance.TestAccPreCheck(t) },
+		Providers:    acceptance.TestAccProviders,
+		CheckDestroy: acceptance.CivoKubernetesClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: CivoKubernetesClusterConfigBasic(kubernetesClusterName) + CivoKubernetesApplicationConfig("cert-manager", ""),
+				Check: resource.ComposeTestCheckFunc(
+					CivoKubernetesClusterResourceExists(resName, &cluster),
+					resource.TestCheckResourceAttr(resAppName, "name", "cert-manager"),
+					resource.TestCheckResourceAttrSet(resAppName, "version"),
+					resource.TestCheckResourceAttr(resAppName, "installed", "true"),
+				),
+			},
+			{
+				ResourceName:      resAppName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config:      CivoKubernetesClusterConfigBasic(kubernetesClusterName) + CivoKubernetesApplicationConfig("Redis", "1GB"),
+				ExpectError: regexp.MustCompile("the plan 1GB is not available"),
+			},
+		},
+	})
+}
+
+func TestFindMarketplaceApplication(t *testing.T) {
+	apps := []civogo.KubernetesMarketplaceApplication{
+		{Name: "cert-manager"},
+		{Name: "metrics-server"},
+		{Name: "Redis", Plans: []civogo.KubernetesMarketplacePlan{{Label: "5GB"}, {Label: "10GB"}}},
+	}
+
+	tests := []struct {
+		name    string
+		app     string
+		plan    string
+		wantErr string
+	}{
+		{name: "exact name", app: "cert-manager"},
+		{name: "name and plan", app: "Redis", plan: "10GB"},
+		{name: "app with plans without plan", app: "Redis"},
+		{name: "wrong case", app: "redis", wantErr: "did you mean Redis?"},
+		{name: "partial name", app: "cert", wantErr: "did you mean cert-manager?"},
+		{name: "unknown name", app: "wordpress", wantErr: "is not in the kubernetes marketplace"},
+		{name: "unknown plan", app: "Redis", plan: "1GB", wantErr: "the available plans are: 5GB, 10GB"},
+		{name: "plan on app without plans", app: "cert-manager", plan: "5GB", wantErr: "doesn't have plans"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, err := kubernetes.ExportFindMarketplaceApplication(apps, tt.app, tt.plan)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				if app.Name != tt.app {
+					t.Fatalf("expected the application %s, got %s", tt.app, app.Name)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected an error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func CivoKubernetesApplicationConfig(name, plan string) string {
+	planArgument := ""
+	if plan != "" {
+		planArgument = fmt.Sprintf("plan = %q", plan)
+	}
+	return fmt.Sprintf(`
+resource "civo_kubernetes_application" "foobar" {
+	cluster_id = civo_kubernetes_cluster.foobar.id
+	name = %q
+	%s
+}`, name, planArgument)
+}
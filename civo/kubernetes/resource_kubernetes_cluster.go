@@ -2,10 +2,13 @@ package kubernetes
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
+	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -85,6 +88,7 @@ func ResourceKubernetesCluster() *schema.Resource {
 				Optional:    true,
 				Description: "Space separated list of tags, to be used freely as required",
 			},
+			"tags_all": utils.TagsAllSchema(),
 			"applications": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -112,11 +116,25 @@ func ResourceKubernetesCluster() *schema.Resource {
 			},
 			// Computed resource
 			"installed_applications": applicationSchema(),
+			"upgrade_path": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The versions the cluster goes through in the upgrade of `kubernetes_version`, shown in the plan when the version changes and kept until the next upgrade",
+			},
+			"upgrade_warnings": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Warnings about the upgrade of `kubernetes_version`, e.g. when the new version is deprecated, shown in the plan when the version changes and kept until the next upgrade",
+			},
 			"pools": {
-				Type:     schema.TypeList,
-				Required: true,
-				MinItems: 1,
-				MaxItems: 1,
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Description: "The node pools of the cluster, pools are matched by `label` so give each one a label when defining more than one. Reordering the pools doesn't change the cluster",
+				// the pools keep the order of the state, a plan that only reorders them is empty
+				DiffSuppressFunc: suppressReorderedNodePools,
 				Elem: &schema.Resource{
 					Schema: nodePoolSchema(false),
 				},
@@ -253,11 +271,7 @@ func resourceKubernetesClusterCreate(ctx context.Context, d *schema.ResourceData
 		config.KubernetesVersion = attr.(string)
 	}
 
-	if attr, ok := d.GetOk("tags"); ok {
-		config.Tags = attr.(string)
-	} else {
-		config.Tags = ""
-	}
+	config.Tags = strings.Join(utils.MergeTags(strings.Fields(d.Get("tags").(string)), utils.DefaultTags(apiClient)), " ")
 
 	if attr, ok := d.GetOk("cni"); ok {
 		config.CNIPlugin = attr.(string)
@@ -322,38 +336,55 @@ func resourceKubernetesClusterCreate(ctx context.Context, d *schema.ResourceData
 
 	// Workaround: Civo API currently ignores labels and taints on the initial cluster creation.
 	// If the user requested them, we must explicitly update the cluster to apply them.
-	if len(pools) > 0 && (len(pools[0].Labels) > 0 || len(pools[0].Taints) > 0) {
-		kubernetesCluster, err := apiClient.GetKubernetesCluster(d.Id())
-		if err != nil {
-			return diag.Errorf("[ERR] failed to find the kubernetes cluster: %s", err)
+	rawPools := d.Get("pools").([]interface{})
+	autoscaling := false
+	var kubernetesCluster *civogo.KubernetesCluster
+	for i, pool := range pools {
+		autoscaling = autoscaling || rawPools[i].(map[string]interface{})["autoscaling"].(bool)
+		if len(pool.Labels) == 0 && len(pool.Taints) == 0 {
+			continue
 		}
 
-		var defaultPoolID string
-		if len(kubernetesCluster.Pools) > 0 {
-			defaultPoolID = kubernetesCluster.Pools[0].ID
-		} else if len(kubernetesCluster.RequiredPools) > 0 {
-			defaultPoolID = kubernetesCluster.RequiredPools[0].ID
-		}
-
-		if defaultPoolID != "" {
-			poolUpdate := &kubernetesClusterPoolUpdatePayload{
-				Region: apiClient.Region,
-			}
-			if pools[0].Labels != nil {
-				poolUpdate.Labels = &pools[0].Labels
-			}
-			if pools[0].Taints != nil {
-				poolUpdate.Taints = &pools[0].Taints
-			}
-			_, err = updateKubernetesClusterPoolHelper(apiClient, d.Id(), defaultPoolID, poolUpdate)
+		if kubernetesCluster == nil {
+			kubernetesCluster, err = apiClient.GetKubernetesCluster(d.Id())
 			if err != nil {
-				return diag.Errorf("[ERR] failed to apply labels/taints to new kubernetes cluster: %s", err)
-			}
-			err = waitForPoolLabelsAndTaints(ctx, apiClient, d.Id(), defaultPoolID, pools[0].Labels, pools[0].Taints, d.Timeout(schema.TimeoutCreate))
-			if err != nil {
-				return diag.Errorf("[ERR] failed to verify labels/taints applied to new kubernetes cluster: %s", err)
+				return diag.Errorf("[ERR] failed to find the kubernetes cluster: %s", err)
 			}
 		}
+
+		poolID := findClusterPoolID(kubernetesCluster, pool.ID, i)
+		if poolID == "" {
+			continue
+		}
+		pools[i].ID = poolID
+
+		poolUpdate := &kubernetesClusterPoolUpdatePayload{
+			Region: apiClient.Region,
+		}
+		if pool.Labels != nil {
+			poolUpdate.Labels = &pools[i].Labels
+		}
+		if pool.Taints != nil {
+			poolUpdate.Taints = &pools[i].Taints
+		}
+		_, err = updateKubernetesClusterPoolHelper(apiClient, d.Id(), poolID, poolUpdate)
+		if err != nil {
+			return diag.Errorf("[ERR] failed to apply labels/taints to new kubernetes cluster: %s", err)
+		}
+		err = waitForPoolLabelsAndTaints(ctx, apiClient, d.Id(), poolID, pool.Labels, pool.Taints, d.Timeout(schema.TimeoutCreate))
+		if err != nil {
+			return diag.Errorf("[ERR] failed to verify labels/taints applied to new kubernetes cluster: %s", err)
+		}
+	}
+
+	if autoscaling {
+		if err := ensureClusterAutoscaler(ctx, apiClient, d.Id(), d.Timeout(schema.TimeoutCreate)); err != nil {
+			return diag.Errorf("[ERR] failed to enable the autoscaler of the kubernetes cluster: %s", err)
+		}
+	}
+
+	if err := setNodePoolLabels(d, pools); err != nil {
+		return diag.Errorf("[ERR] error setting the pools for kubernetes cluster: %s", err)
 	}
 
 	return resourceKubernetesClusterRead(ctx, d, m)
@@ -386,7 +417,9 @@ func resourceKubernetesClusterRead(_ context.Context, d *schema.ResourceData, m
 	d.Set("kubernetes_version", resp.KubernetesVersion)
 	d.Set("cluster_type", resp.ClusterType)
 	d.Set("cni", resp.CNIPlugin)
-	d.Set("tags", strings.Join(resp.Tags, " ")) // space separated tags
+	// space separated tags, the default tags of the provider are only shown in tags_all
+	d.Set("tags", strings.Join(utils.ResourceTags(resp.Tags, strings.Fields(d.Get("tags").(string)), utils.DefaultTags(apiClient)), " "))
+	d.Set("tags_all", resp.Tags)
 	d.Set("status", resp.Status)
 	d.Set("ready", resp.Ready)
 	// d.Set("kubeconfig", resp.KubeConfig)
@@ -404,7 +437,10 @@ func resourceKubernetesClusterRead(_ context.Context, d *schema.ResourceData, m
 		d.Set("kubeconfig", nil)
 	}
 
-	if err := d.Set("pools", flattenNodePool(resp)); err != nil {
+	statePools := d.Get("pools").([]interface{})
+	pools := orderNodePools(flattenNodePool(resp), nodePoolLabels(statePools))
+	keepNodePoolAutoscaling(pools, statePools)
+	if err := d.Set("pools", pools); err != nil {
 		return diag.Errorf("[ERR] error retrieving the pool for kubernetes cluster error: %#v", err)
 	}
 
@@ -455,8 +491,8 @@ func resourceKubernetesClusterUpdate(ctx context.Context, d *schema.ResourceData
 		hasClusterUpdate = true
 	}
 
-	if d.HasChange("tags") {
-		config.Tags = d.Get("tags").(string)
+	if d.HasChanges("tags", "tags_all") {
+		config.Tags = strings.Join(utils.MergeTags(strings.Fields(d.Get("tags").(string)), utils.DefaultTags(apiClient)), " ")
 		hasClusterUpdate = true
 	}
 
@@ -484,114 +520,208 @@ func resourceKubernetesClusterUpdate(ctx context.Context, d *schema.ResourceData
 		if err != nil {
 			return diag.Errorf("Error waiting for Kubernetes cluster update: %s", err)
 		}
+
+		if d.HasChange("kubernetes_version") {
+			err = waitForKubernetesClusterVersion(ctx, apiClient, d.Id(), config.KubernetesVersion, d.Timeout(schema.TimeoutUpdate))
+			if err != nil {
+				return diag.Errorf("Error waiting for Kubernetes cluster upgrade: %s", err)
+			}
+		}
+	}
+
+	// the deprecation of the new version is also shown in the upgrade_warnings of the plan
+	var diags diag.Diagnostics
+	if d.HasChange("kubernetes_version") {
+		versions, err := kubernetesVersions(m, d.Get("cluster_type").(string))
+		if err == nil {
+			for _, v := range versions {
+				if sameKubernetesVersion(v.Label, config.KubernetesVersion) && isDeprecatedKubernetesVersion(v) {
+					diags = append(diags, diag.Diagnostic{
+						Severity: diag.Warning,
+						Summary:  fmt.Sprintf("Kubernetes version %s is deprecated", config.KubernetesVersion),
+						Detail:   "The cluster was upgraded to a deprecated version, plan an upgrade to a stable version soon.",
+					})
+					break
+				}
+			}
+		}
 	}
 
 	if d.HasChange("pools") {
 		old, new := d.GetChange("pools")
-		oldPool := old.([]interface{})[0].(map[string]interface{})
-		newPool := new.([]interface{})[0].(map[string]interface{})
-
-		// if the size is different, then return and error as we can't change the size of a pool
-		if oldPool["size"].(string) != newPool["size"].(string) {
-			return diag.Errorf("[ERR] Size change (%q) for existing cluster is not available at this moment", "size")
+		if poolDiags := updateKubernetesClusterPools(ctx, apiClient, d, old.([]interface{}), new.([]interface{})); poolDiags.HasError() {
+			return append(diags, poolDiags...)
 		}
+	}
 
-		kubernetesCluster, err := apiClient.FindKubernetesCluster(d.Id())
-		if err != nil {
-			return diag.Errorf("[ERR] failed to find the kubernetes cluster: %s", err)
-		}
+	return append(diags, resourceKubernetesClusterRead(ctx, d, m)...)
+}
 
-		targetNodePool := ""
-		for _, v := range kubernetesCluster.Pools {
-			if targetNodePool == "" && v.ID == newPool["label"].(string) {
-				targetNodePool = v.ID
+// updateKubernetesClusterPools function to apply the difference between the old and new pools,
+// pools are matched by label so reordering them doesn't touch the cluster. New pools are created
+// first and removed pools are deleted last, so the cluster always keeps at least one pool
+func updateKubernetesClusterPools(ctx context.Context, apiClient *civogo.Client, d *schema.ResourceData, oldPools, newPools []interface{}) diag.Diagnostics {
+	oldByLabel := make(map[string]map[string]interface{}, len(oldPools))
+	for _, rawPool := range oldPools {
+		pool := rawPool.(map[string]interface{})
+		oldByLabel[pool["label"].(string)] = pool
+	}
+
+	expandedPools := expandNodePools(newPools)
+	keep := make(map[string]bool, len(expandedPools))
+	autoscaling := false
+	for i, pool := range expandedPools {
+		newPool := newPools[i].(map[string]interface{})
+		keep[pool.ID] = true
+		autoscaling = autoscaling || newPool["autoscaling"].(bool)
+
+		oldPool, ok := oldByLabel[pool.ID]
+		if !ok {
+			pool.Region = apiClient.Region
+			log.Printf("[INFO] adding the pool %s to the kubernetes cluster %s", pool.ID, d.Id())
+			if _, err := apiClient.CreateKubernetesClusterPool(d.Id(), &pool); err != nil {
+				return diag.Errorf("[ERR] failed to create the kubernetes cluster pool %s: %s", pool.ID, err)
 			}
-		}
-		if targetNodePool == "" {
-			for _, v := range kubernetesCluster.RequiredPools {
-				if v.ID == newPool["label"].(string) {
-					targetNodePool = v.ID
-					break
-				}
+			if err := waitForKubernetesNodePool(apiClient, d.Id(), pool.ID, d.Timeout(schema.TimeoutUpdate)); err != nil {
+				return diag.Errorf("Error creating Kubernetes node pool: %s", err)
 			}
+			continue
 		}
-		if targetNodePool == "" {
-			if len(kubernetesCluster.Pools) > 0 {
-				targetNodePool = kubernetesCluster.Pools[0].ID
-			} else if len(kubernetesCluster.RequiredPools) > 0 {
-				targetNodePool = kubernetesCluster.RequiredPools[0].ID
-			}
+
+		// if the size is different, then return and error as we can't change the size of a pool
+		if oldPool["size"].(string) != newPool["size"].(string) {
+			return diag.Errorf("[ERR] Size change (%q) for existing pool %s is not available at this moment", "size", pool.ID)
 		}
 
-		// Extract labels from new desired state
-		var newLabels map[string]string
-		hasLabels := false
-		if rawLabels, ok := newPool["labels"].(map[string]interface{}); ok && rawLabels != nil && len(rawLabels) > 0 {
-			hasLabels = true
-			newLabels = make(map[string]string, len(rawLabels))
-			for k, v := range rawLabels {
-				if strVal, ok := v.(string); ok {
-					newLabels[k] = strVal
-				}
-			}
+		if !nodePoolChanged(oldPool, newPool) {
+			continue
 		}
 
-		// If newLabels is nil/unset, but oldPool had labels, it means the user removed them.
-		// In this case, we want to explicitly clear them on the node pool.
-		if !hasLabels {
-			if oldLabels, ok := oldPool["labels"].(map[string]interface{}); ok && len(oldLabels) > 0 {
-				newLabels = make(map[string]string)
-			}
+		poolUpdate, newLabels, newTaints := nodePoolUpdatePayload(oldPool, newPool)
+		poolUpdate.Region = apiClient.Region
+
+		log.Printf("[INFO] updating the kubernetes cluster pool %s", pool.ID)
+		if _, err := updateKubernetesClusterPoolHelper(apiClient, d.Id(), pool.ID, poolUpdate); err != nil {
+			return diag.Errorf("[ERR] failed to update kubernetes cluster pool: %s", err)
 		}
 
-		// Extract taints from new desired state
-		var newTaints []corev1.Taint
-		hasTaints := false
-		if taintSet, ok := newPool["taint"].(*schema.Set); ok && taintSet != nil && taintSet.Len() > 0 {
-			hasTaints = true
-			newTaints = make([]corev1.Taint, 0, taintSet.Len())
-			for _, taintInterface := range taintSet.List() {
-				taintMap := taintInterface.(map[string]interface{})
-				newTaints = append(newTaints, corev1.Taint{
-					Key:    taintMap["key"].(string),
-					Value:  taintMap["value"].(string),
-					Effect: corev1.TaintEffect(taintMap["effect"].(string)),
-				})
-			}
+		if err := waitForPoolLabelsAndTaints(ctx, apiClient, d.Id(), pool.ID, newLabels, newTaints, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return diag.Errorf("Error waiting for Kubernetes node pool update: %s", err)
 		}
+	}
 
-		if !hasTaints {
-			if oldTaints, ok := oldPool["taint"].(*schema.Set); ok && oldTaints != nil && oldTaints.Len() > 0 {
-				newTaints = make([]corev1.Taint, 0)
-			}
+	for _, label := range nodePoolLabels(oldPools) {
+		if keep[label] {
+			continue
 		}
 
-		poolUpdate := &kubernetesClusterPoolUpdatePayload{
-			Region: apiClient.Region,
+		log.Printf("[INFO] removing the pool %s from the kubernetes cluster %s", label, d.Id())
+		if _, err := apiClient.DeleteKubernetesClusterPool(d.Id(), label); err != nil {
+			if errors.Is(err, civogo.DatabaseClusterPoolNotFoundError) {
+				continue
+			}
+			return diag.Errorf("[ERR] failed to delete the kubernetes cluster pool %s: %s", label, err)
 		}
-		if newLabels != nil {
-			poolUpdate.Labels = &newLabels
+		if err := waitForKubernetesNodePoolDelete(ctx, apiClient, d.Id(), label, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return diag.FromErr(err)
 		}
-		if newTaints != nil {
-			poolUpdate.Taints = &newTaints
+	}
+
+	if autoscaling {
+		if err := ensureClusterAutoscaler(ctx, apiClient, d.Id(), d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return diag.Errorf("[ERR] failed to enable the autoscaler of the kubernetes cluster: %s", err)
 		}
+	}
+
+	if err := setNodePoolLabels(d, expandedPools); err != nil {
+		return diag.Errorf("[ERR] error setting the pools for kubernetes cluster: %s", err)
+	}
+
+	return nil
+}
+
+// nodePoolChanged function to check if the node count, labels or taints of a pool changed
+func nodePoolChanged(oldPool, newPool map[string]interface{}) bool {
+	if oldPool["node_count"].(int) != newPool["node_count"].(int) {
+		return true
+	}
+
+	if !reflect.DeepEqual(expandNodePoolLabels(oldPool["labels"]), expandNodePoolLabels(newPool["labels"])) {
+		return true
+	}
+
+	oldTaints, _ := oldPool["taint"].(*schema.Set)
+	newTaints, _ := newPool["taint"].(*schema.Set)
+	if oldTaints == nil || newTaints == nil {
+		return (oldTaints != nil && oldTaints.Len() > 0) || (newTaints != nil && newTaints.Len() > 0)
+	}
+	return !oldTaints.Equal(newTaints)
+}
+
+// nodePoolUpdatePayload function to build the update of a pool, labels and taints removed from
+// the configuration are explicitly cleared on the node pool
+func nodePoolUpdatePayload(oldPool, newPool map[string]interface{}) (*kubernetesClusterPoolUpdatePayload, map[string]string, []corev1.Taint) {
+	newLabels := expandNodePoolLabels(newPool["labels"])
+
+	// If newLabels is nil/unset, but oldPool had labels, it means the user removed them.
+	// In this case, we want to explicitly clear them on the node pool.
+	if newLabels == nil && expandNodePoolLabels(oldPool["labels"]) != nil {
+		newLabels = make(map[string]string)
+	}
+
+	newTaints := expandNodePoolTaints(newPool["taint"])
+	if newTaints == nil && expandNodePoolTaints(oldPool["taint"]) != nil {
+		newTaints = make([]corev1.Taint, 0)
+	}
 
-		newCount := newPool["node_count"].(int)
+	poolUpdate := &kubernetesClusterPoolUpdatePayload{}
+	if newLabels != nil {
+		poolUpdate.Labels = &newLabels
+	}
+	if newTaints != nil {
+		poolUpdate.Taints = &newTaints
+	}
+
+	// the node count of an autoscaled pool is left to the autoscaler unless it was changed
+	if newCount := newPool["node_count"].(int); newCount != oldPool["node_count"].(int) || !newPool["autoscaling"].(bool) {
 		poolUpdate.Count = &newCount
+	}
 
-		log.Printf("[INFO] updating the kubernetes cluster pool %s", targetNodePool)
-		_, err = updateKubernetesClusterPoolHelper(apiClient, d.Id(), targetNodePool, poolUpdate)
-		if err != nil {
-			return diag.Errorf("[ERR] failed to update kubernetes cluster pool: %s", err)
+	return poolUpdate, newLabels, newTaints
+}
+
+// setNodePoolLabels function to store the label of every pool, including the generated ones,
+// so the following read is able to tell which pools belong to the cluster resource
+func setNodePoolLabels(d *schema.ResourceData, pools []civogo.KubernetesClusterPoolConfig) error {
+	rawPools := d.Get("pools").([]interface{})
+	for i := range rawPools {
+		if i < len(pools) {
+			rawPools[i].(map[string]interface{})["label"] = pools[i].ID
 		}
+	}
+	return d.Set("pools", rawPools)
+}
 
-		err = waitForPoolLabelsAndTaints(ctx, apiClient, d.Id(), targetNodePool, newLabels, newTaints, d.Timeout(schema.TimeoutUpdate))
-		if err != nil {
-			return diag.Errorf("Error waiting for Kubernetes node pool update: %s", err)
+// findClusterPoolID function to find the pool of the cluster with the given ID, falling back to
+// the pool at the same position in case the API didn't keep the requested ID
+func findClusterPoolID(cluster *civogo.KubernetesCluster, poolID string, index int) string {
+	for _, p := range cluster.Pools {
+		if p.ID == poolID {
+			return p.ID
+		}
+	}
+	for _, p := range cluster.RequiredPools {
+		if p.ID == poolID {
+			return p.ID
 		}
 	}
 
-	return resourceKubernetesClusterRead(ctx, d, m)
+	if index < len(cluster.Pools) {
+		return cluster.Pools[index].ID
+	} else if index < len(cluster.RequiredPools) {
+		return cluster.RequiredPools[index].ID
+	}
+	return ""
 }
 
 func waitForClusterActive(ctx context.Context, apiClient *civogo.Client, clusterID string, timeout time.Duration) error {
@@ -658,6 +788,30 @@ func resourceKubernetesClusterDelete(ctx context.Context, d *schema.ResourceData
 
 func customizeDiffKubernetesCluster(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
 
+	// Check the pools can be told apart by their label
+	seenLabels := map[string]bool{}
+	for _, label := range nodePoolLabels(d.Get("pools").([]interface{})) {
+		if seenLabels[label] {
+			return fmt.Errorf("the pool label %q is used more than once, every pool of the cluster needs a unique label", label)
+		}
+		seenLabels[label] = true
+	}
+
+	// Check the autoscaling arguments of every pool
+	for i, rawPool := range d.Get("pools").([]interface{}) {
+		pool, ok := rawPool.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name := fmt.Sprintf("#%d", i)
+		if label, ok := pool["label"].(string); ok && label != "" {
+			name = label
+		}
+		if err := validateNodePoolAutoscaling(name, pool); err != nil {
+			return err
+		}
+	}
+
 	// Check if cluster type is talos and CNI is cilium
 	if clusterType, ok := d.GetOk("cluster_type"); ok && clusterType.(string) == "talos" {
 		if cni, ok := d.GetOk("cni"); ok && cni.(string) == "cilium" {
@@ -726,6 +880,36 @@ func customizeDiffKubernetesCluster(ctx context.Context, d *schema.ResourceDiff,
 			}
 		}
 
+		// Check the version upgrade is possible before it's sent to the API, the path and the
+		// warnings of the upgrade are shown in the plan
+		if d.HasChange("kubernetes_version") {
+			oldVersion, newVersion := d.GetChange("kubernetes_version")
+			if !d.NewValueKnown("kubernetes_version") {
+				if err := d.SetNewComputed("upgrade_path"); err != nil {
+					return err
+				}
+				if err := d.SetNewComputed("upgrade_warnings"); err != nil {
+					return err
+				}
+			} else if oldVersion.(string) != "" && newVersion.(string) != "" {
+				versions, err := kubernetesVersions(meta, d.Get("cluster_type").(string))
+				if err != nil {
+					return fmt.Errorf("failed to get available Kubernetes versions: %w", err)
+				}
+
+				path, warnings, err := planKubernetesUpgrade(oldVersion.(string), newVersion.(string), versions)
+				if err != nil {
+					return err
+				}
+				if err := d.SetNew("upgrade_path", path); err != nil {
+					return err
+				}
+				if err := d.SetNew("upgrade_warnings", warnings); err != nil {
+					return err
+				}
+			}
+		}
+
 		if d.HasChange("applications") {
 			return fmt.Errorf("the 'applications' field is immutable")
 		}
@@ -736,7 +920,8 @@ func customizeDiffKubernetesCluster(ctx context.Context, d *schema.ResourceDiff,
 			return fmt.Errorf("the 'cni' field is immutable")
 		}
 	}
-	return nil
+
+	return utils.CustomizeDiffTagsAll(d, meta, strings.Fields(d.Get("tags").(string)))
 }
 
 func waitForPoolLabelsAndTaints(ctx context.Context, apiClient *civogo.Client, clusterID, poolID string, expectedLabels map[string]string, expectedTaints []corev1.Taint, timeout time.Duration) error {
@@ -828,3 +1013,193 @@ func waitForPoolLabelsAndTaints(ctx context.Context, apiClient *civogo.Client, c
 	_, err := stateConf.WaitForStateContext(ctx)
 	return err
 }
+
+// kubernetesVersions function to return the available versions for the cluster type
+func kubernetesVersions(meta interface{}, clusterType string) ([]civogo.KubernetesVersion, error) {
+	if clusterType == "" {
+		clusterType = "k3s"
+	}
+
+	availableVersions, err := getKubernetesVersions(meta, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]civogo.KubernetesVersion, 0, len(availableVersions))
+	for _, v := range availableVersions {
+		kv := v.(civogo.KubernetesVersion)
+		if kv.ClusterType == clusterType || (kv.ClusterType == "" && clusterType == "k3s") {
+			versions = append(versions, kv)
+		}
+	}
+	return versions, nil
+}
+
+// isDeprecatedKubernetesVersion function to check if a version is still offered but about to be removed
+func isDeprecatedKubernetesVersion(v civogo.KubernetesVersion) bool {
+	return v.Type == "deprecated" || v.Type == "legacy"
+}
+
+var kubernetesVersionRegex = regexp.MustCompile(`(\d+)\.(\d+)\.(\d+)(?:-k3s(\d+))?`)
+
+// parseKubernetesVersion function to return the major, minor, patch and k3s release of a version
+// like 1.31.2-k3s1 or talos-v1.5.0
+func parseKubernetesVersion(version string) ([4]int, bool) {
+	var parsed [4]int
+	match := kubernetesVersionRegex.FindStringSubmatch(version)
+	if match == nil {
+		return parsed, false
+	}
+	for i := 1; i < len(match); i++ {
+		if match[i] != "" {
+			parsed[i-1], _ = strconv.Atoi(match[i])
+		}
+	}
+	return parsed, true
+}
+
+// compareKubernetesVersions function to compare two parsed versions, like strings.Compare
+func compareKubernetesVersions(a, b [4]int) int {
+	for i := range a {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// sameKubernetesVersion function to check if two versions have the same major, minor and patch
+// version, so 1.31.2-k3s1 matches v1.31.2+k3s1 as reported by the nodes
+func sameKubernetesVersion(a, b string) bool {
+	parsedA, okA := parseKubernetesVersion(a)
+	parsedB, okB := parseKubernetesVersion(b)
+	if !okA || !okB {
+		return strings.TrimPrefix(a, "v") == strings.TrimPrefix(b, "v")
+	}
+	return parsedA[0] == parsedB[0] && parsedA[1] == parsedB[1] && parsedA[2] == parsedB[2]
+}
+
+// planKubernetesUpgrade function to check an upgrade between two versions against the available
+// versions. Downgrades and upgrades that skip a minor version are rejected, the returned path
+// lists every version the cluster goes through and the warnings flag deprecated versions
+func planKubernetesUpgrade(from, to string, versions []civogo.KubernetesVersion) ([]string, []string, error) {
+	fromVersion, ok := parseKubernetesVersion(from)
+	if !ok {
+		return nil, nil, fmt.Errorf("unable to parse the current Kubernetes version %s", from)
+	}
+	toVersion, ok := parseKubernetesVersion(to)
+	if !ok {
+		return nil, nil, fmt.Errorf("unable to parse the Kubernetes version %s", to)
+	}
+
+	switch compareKubernetesVersions(toVersion, fromVersion) {
+	case 0:
+		return []string{from}, nil, nil
+	case -1:
+		return nil, nil, fmt.Errorf("downgrading the Kubernetes version from %s to %s is not supported", from, to)
+	}
+
+	var target *civogo.KubernetesVersion
+	labels := make([]string, 0, len(versions))
+	for i, v := range versions {
+		labels = append(labels, v.Label)
+		if v.Label == to || v.Version == to {
+			target = &versions[i]
+		}
+	}
+	for i, v := range versions {
+		if target == nil && (sameKubernetesVersion(v.Label, to) || sameKubernetesVersion(v.Version, to)) {
+			target = &versions[i]
+		}
+	}
+	if target == nil {
+		return nil, nil, fmt.Errorf("the Kubernetes version %s is not available, available versions: %s", to, strings.Join(labels, ", "))
+	}
+
+	if toVersion[0] != fromVersion[0] {
+		return nil, nil, fmt.Errorf("upgrading the Kubernetes version from %s to %s changes the major version, which is not supported", from, to)
+	}
+
+	// pick the latest available version of every minor version between the two
+	path := []string{from}
+	for minor := fromVersion[1] + 1; minor < toVersion[1]; minor++ {
+		step := fmt.Sprintf("%d.%d.x", fromVersion[0], minor)
+		var latest [4]int
+		for _, v := range versions {
+			parsed, ok := parseKubernetesVersion(v.Label)
+			if ok && parsed[0] == fromVersion[0] && parsed[1] == minor && compareKubernetesVersions(parsed, latest) > 0 {
+				latest = parsed
+				step = v.Label
+			}
+		}
+		path = append(path, step)
+	}
+	path = append(path, to)
+
+	if len(path) > 2 {
+		return path, nil, fmt.Errorf("upgrading the Kubernetes version from %s to %s skips %d minor version(s), upgrade one minor version at a time: %s",
+			from, to, len(path)-2, strings.Join(path, " -> "))
+	}
+
+	var warnings []string
+	if isDeprecatedKubernetesVersion(*target) {
+		warnings = append(warnings, fmt.Sprintf("the Kubernetes version %s is deprecated, consider upgrading to a stable version", to))
+	}
+
+	return path, warnings, nil
+}
+
+// kubernetesClusterNodeVersions is the part of the cluster returned by the API with the
+// version running on every node, which civogo doesn't decode
+type kubernetesClusterNodeVersions struct {
+	Status            string `json:"status"`
+	KubernetesVersion string `json:"kubernetes_version"`
+	Pools             []struct {
+		ID        string `json:"id"`
+		Instances []struct {
+			Hostname string `json:"hostname"`
+			Version  string `json:"version"`
+		} `json:"instances"`
+	} `json:"pools"`
+}
+
+// waitForKubernetesClusterVersion function to wait until the cluster and the nodes of every pool
+// run the version, nodes that don't report a version only wait for the cluster
+func waitForKubernetesClusterVersion(ctx context.Context, apiClient *civogo.Client, clusterID, version string, timeout time.Duration) error {
+	stateConf := &resource.StateChangeConf{
+		Pending: []string{"UPGRADING"},
+		Target:  []string{"UPGRADED"},
+		Refresh: func() (interface{}, string, error) {
+			resp, err := apiClient.SendGetRequest(fmt.Sprintf("/v2/kubernetes/clusters/%s", clusterID))
+			if err != nil {
+				return nil, "", err
+			}
+
+			cluster := &kubernetesClusterNodeVersions{}
+			if err := json.Unmarshal(resp, cluster); err != nil {
+				return nil, "", err
+			}
+
+			if cluster.Status != "ACTIVE" || !sameKubernetesVersion(cluster.KubernetesVersion, version) {
+				return cluster, "UPGRADING", nil
+			}
+			for _, pool := range cluster.Pools {
+				for _, node := range pool.Instances {
+					if node.Version != "" && !sameKubernetesVersion(node.Version, version) {
+						log.Printf("[INFO] node %s of the pool %s is still running %s", node.Hostname, pool.ID, node.Version)
+						return cluster, "UPGRADING", nil
+					}
+				}
+			}
+			return cluster, "UPGRADED", nil
+		},
+		Timeout:    timeout,
+		Delay:      3 * time.Second,
+		MinTimeout: 3 * time.Second,
+	}
+	_, err := stateConf.WaitForStateContext(ctx)
+	return err
+}
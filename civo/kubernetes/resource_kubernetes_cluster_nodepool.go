@@ -36,9 +36,25 @@ func ResourceKubernetesClusterNodePool() *schema.Resource {
 			Update: schema.DefaultTimeout(30 * time.Minute),
 			Delete: schema.DefaultTimeout(30 * time.Minute),
 		},
+		CustomizeDiff: customizeDiffKubernetesClusterNodePool,
 	}
 }
 
+// customizeDiffKubernetesClusterNodePool function to check the autoscaling arguments of the pool
+func customizeDiffKubernetesClusterNodePool(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	name := d.Get("label").(string)
+	if name == "" {
+		name = d.Id()
+	}
+
+	return validateNodePoolAutoscaling(name, map[string]interface{}{
+		"autoscaling":            d.Get("autoscaling"),
+		"ignored_node_count_min": d.Get("ignored_node_count_min"),
+		"ignored_node_count_max": d.Get("ignored_node_count_max"),
+		"node_count":             d.Get("node_count"),
+	})
+}
+
 // function to create a new cluster
 func resourceKubernetesClusterNodePoolCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	apiClient := m.(*civogo.Client)
@@ -122,6 +138,12 @@ func resourceKubernetesClusterNodePoolCreate(ctx context.Context, d *schema.Reso
 		return diag.Errorf("Error creating Kubernetes node pool: %s", err)
 	}
 
+	if d.Get("autoscaling").(bool) {
+		if err := ensureClusterAutoscaler(ctx, apiClient, clusterID, d.Timeout(schema.TimeoutCreate)); err != nil {
+			return diag.Errorf("[ERR] failed to enable the autoscaler of the kubernetes cluster: %s", err)
+		}
+	}
+
 	return resourceKubernetesClusterNodePoolRead(ctx, d, m)
 }
 
@@ -266,6 +288,12 @@ func resourceKubernetesClusterNodePoolUpdate(ctx context.Context, d *schema.Reso
 		return diag.Errorf("Error updating Kubernetes node pool: %s", err)
 	}
 
+	if d.HasChange("autoscaling") && d.Get("autoscaling").(bool) {
+		if err := ensureClusterAutoscaler(ctx, apiClient, clusterID, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return diag.Errorf("[ERR] failed to enable the autoscaler of the kubernetes cluster: %s", err)
+		}
+	}
+
 	return resourceKubernetesClusterNodePoolRead(ctx, d, m)
 }
 
@@ -291,19 +319,7 @@ func resourceKubernetesClusterNodePoolDelete(ctx context.Context, d *schema.Reso
 	}
 
 	// Add retry logic here to delete the node pool
-	err = retry.RetryContext(ctx, d.Timeout(schema.TimeoutDelete)-time.Minute, func() *retry.RetryError {
-		_, err := apiClient.GetKubernetesClusterPool(getKubernetesCluster.ID, d.Id())
-		if err != nil {
-			if errors.Is(err, civogo.DatabaseClusterPoolNotFoundError) {
-				log.Printf("[INFO] kubernetes node pool %s deleted", d.Id())
-				return nil
-			}
-			log.Printf("[INFO] error trying to read kubernetes cluster pool: %s", err)
-			return retry.NonRetryableError(fmt.Errorf("error waiting for Kubernetes node pool to be deleted: %s", err))
-		}
-		log.Printf("[INFO] kubernetes node pool %s still exists", d.Id())
-		return retry.RetryableError(fmt.Errorf("kubernetes node pool still exists"))
-	})
+	err = waitForKubernetesNodePoolDelete(ctx, apiClient, getKubernetesCluster.ID, d.Id(), d.Timeout(schema.TimeoutDelete))
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -362,15 +378,19 @@ func resourceKubernetesClusterNodePoolImport(d *schema.ResourceData, m interface
 
 // waitForKubernetesNodePoolCreate is a utility function to wait for a node pool to be created
 func waitForKubernetesNodePoolCreate(client *civogo.Client, d *schema.ResourceData, clusterID string) error {
+	return waitForKubernetesNodePool(client, clusterID, d.Id(), d.Timeout(schema.TimeoutCreate))
+}
+
+// waitForKubernetesNodePool is a utility function to wait for all the nodes of a node pool to be running
+func waitForKubernetesNodePool(client *civogo.Client, clusterID, nodePoolID string, poolTimeout time.Duration) error {
 	var (
 		tickerInterval        = 10 * time.Second
-		timeoutSeconds        = d.Timeout(schema.TimeoutCreate).Seconds()
+		timeoutSeconds        = poolTimeout.Seconds()
 		timeout               = int(timeoutSeconds / tickerInterval.Seconds())
 		n                     = 0
 		totalRequiredInstance = 0
 		totalRunningInstance  = 0
 		ticker                = time.NewTicker(tickerInterval)
-		nodePoolID            = d.Id()
 	)
 
 	for range ticker.C {
@@ -420,3 +440,20 @@ func waitForKubernetesNodePoolCreate(client *civogo.Client, d *schema.ResourceDa
 
 	return fmt.Errorf("timeout waiting to create nodepool %s", nodePoolID)
 }
+
+// waitForKubernetesNodePoolDelete is a utility function to wait for a node pool to be removed from the cluster
+func waitForKubernetesNodePoolDelete(ctx context.Context, client *civogo.Client, clusterID, nodePoolID string, timeout time.Duration) error {
+	return retry.RetryContext(ctx, timeout-time.Minute, func() *retry.RetryError {
+		_, err := client.GetKubernetesClusterPool(clusterID, nodePoolID)
+		if err != nil {
+			if errors.Is(err, civogo.DatabaseClusterPoolNotFoundError) {
+				log.Printf("[INFO] kubernetes node pool %s deleted", nodePoolID)
+				return nil
+			}
+			log.Printf("[INFO] error trying to read kubernetes cluster pool: %s", err)
+			return retry.NonRetryableError(fmt.Errorf("error waiting for Kubernetes node pool to be deleted: %s", err))
+		}
+		log.Printf("[INFO] kubernetes node pool %s still exists", nodePoolID)
+		return retry.RetryableError(fmt.Errorf("kubernetes node pool still exists"))
+	})
+}
@@ -196,3 +196,211 @@ func TestFlattenNodePool(t *testing.T) {
 		})
 	}
 }
+
+func TestFlattenNodePoolMultiplePools(t *testing.T) {
+	cluster := &civogo.KubernetesCluster{
+		Pools: []civogo.KubernetesPool{
+			{ID: "workers", Count: 2, Size: "g4s.kube.small", InstanceNames: []string{"node-1", "node-2"}},
+			{ID: "gpu", Count: 1, Size: "g4g.kube.small", InstanceNames: []string{"node-3"}},
+		},
+		RequiredPools: []civogo.RequiredPools{
+			{ID: "workers", Count: 2, Size: "g4s.kube.small"},
+			{ID: "gpu", Count: 1, Size: "g4g.kube.small", Labels: map[string]string{"gpu": "true"}},
+			{ID: "batch", Count: 3, Size: "g4s.kube.large"},
+		},
+	}
+
+	expected := []interface{}{
+		map[string]interface{}{
+			"label":               "workers",
+			"node_count":          2,
+			"size":                "g4s.kube.small",
+			"instance_names":      []string{"node-1", "node-2"},
+			"public_ip_node_pool": false,
+		},
+		map[string]interface{}{
+			"label":               "gpu",
+			"node_count":          1,
+			"size":                "g4g.kube.small",
+			"instance_names":      []string{"node-3"},
+			"public_ip_node_pool": false,
+			"labels":              map[string]string{"gpu": "true"},
+		},
+		map[string]interface{}{
+			"label":               "batch",
+			"node_count":          3,
+			"size":                "g4s.kube.large",
+			"instance_names":      []string{},
+			"public_ip_node_pool": false,
+		},
+	}
+
+	actual := kubernetes.ExportFlattenNodePool(cluster)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected: %#v, got: %#v", expected, actual)
+	}
+}
+
+func TestOrderNodePools(t *testing.T) {
+	pool := func(label string) interface{} {
+		return map[string]interface{}{"label": label}
+	}
+	pools := []interface{}{pool("a"), pool("b"), pool("c")}
+
+	cases := []struct {
+		name     string
+		labels   []string
+		expected []interface{}
+	}{
+		{
+			name:     "Importing a cluster keeps every pool",
+			labels:   nil,
+			expected: []interface{}{pool("a"), pool("b"), pool("c")},
+		},
+		{
+			name:     "Pools follow the order of the state",
+			labels:   []string{"c", "a", "b"},
+			expected: []interface{}{pool("c"), pool("a"), pool("b")},
+		},
+		{
+			name:     "Pools managed by other resources are left out",
+			labels:   []string{"b"},
+			expected: []interface{}{pool("b")},
+		},
+		{
+			name:     "Pools removed outside terraform are dropped",
+			labels:   []string{"a", "d"},
+			expected: []interface{}{pool("a")},
+		},
+		{
+			name:     "Unknown labels fall back to the default pool",
+			labels:   []string{"d"},
+			expected: []interface{}{pool("a")},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := kubernetes.ExportOrderNodePools(pools, tc.labels)
+			if !reflect.DeepEqual(actual, tc.expected) {
+				t.Fatalf("expected: %#v, got: %#v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestKeepNodePoolAutoscaling(t *testing.T) {
+	cluster := &civogo.KubernetesCluster{
+		Pools: []civogo.KubernetesPool{
+			{ID: "workers", Count: 4, Size: "g4s.kube.small"},
+			{ID: "batch", Count: 1, Size: "g4s.kube.small"},
+		},
+	}
+	statePools := []interface{}{
+		map[string]interface{}{"label": "workers", "autoscaling": true, "ignored_node_count_min": 2, "ignored_node_count_max": 6},
+	}
+
+	pools := kubernetes.ExportFlattenNodePool(cluster)
+	kubernetes.ExportKeepNodePoolAutoscaling(pools, statePools)
+
+	workers := pools[0].(map[string]interface{})
+	if workers["autoscaling"] != true || workers["ignored_node_count_min"] != 2 || workers["ignored_node_count_max"] != 6 {
+		t.Fatalf("expected the autoscaling arguments of the state to be kept, got: %#v", workers)
+	}
+	if _, ok := pools[1].(map[string]interface{})["autoscaling"]; ok {
+		t.Fatalf("expected no autoscaling arguments for a pool missing from the state, got: %#v", pools[1])
+	}
+}
+
+func TestValidateNodePoolAutoscaling(t *testing.T) {
+	cases := []struct {
+		name    string
+		pool    map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name: "Fixed size pool",
+			pool: map[string]interface{}{"autoscaling": false, "ignored_node_count_min": 0, "ignored_node_count_max": 0, "node_count": 3},
+		},
+		{
+			name:    "Limits without autoscaling",
+			pool:    map[string]interface{}{"autoscaling": false, "ignored_node_count_min": 1, "ignored_node_count_max": 3, "node_count": 3},
+			wantErr: true,
+		},
+		{
+			name:    "Autoscaling without limits",
+			pool:    map[string]interface{}{"autoscaling": true, "ignored_node_count_min": 1, "ignored_node_count_max": 0, "node_count": 1},
+			wantErr: true,
+		},
+		{
+			name:    "Minimum greater than maximum",
+			pool:    map[string]interface{}{"autoscaling": true, "ignored_node_count_min": 5, "ignored_node_count_max": 2, "node_count": 3},
+			wantErr: true,
+		},
+		{
+			name:    "Node count outside of the limits",
+			pool:    map[string]interface{}{"autoscaling": true, "ignored_node_count_min": 2, "ignored_node_count_max": 4, "node_count": 6},
+			wantErr: true,
+		},
+		{
+			name: "Autoscaled pool",
+			pool: map[string]interface{}{"autoscaling": true, "ignored_node_count_min": 2, "ignored_node_count_max": 4, "node_count": 3},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := kubernetes.ExportValidateNodePoolAutoscaling("workers", tc.pool)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected error: %t, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestAccCivoKubernetesClusterNodePool_autoscaling(t *testing.T) {
+	var kubernetes civogo.KubernetesCluster
+	var kubernetesNodePool civogo.KubernetesPool
+
+	resName := "civo_kubernetes_cluster.foobar"
+	resPoolName := "civo_kubernetes_node_pool.foobar"
+	var kubernetesClusterName = acctest.RandomWithPrefix("tf-test") + "-example"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { acceptance.TestAccPreCheck(t) },
+		Providers:    acceptance.TestAccProviders,
+		CheckDestroy: acceptance.CivoKubernetesClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: CivoKubernetesClusterConfigBasic(kubernetesClusterName) + CivoKubernetesClusterNodePoolConfigAutoscaling(1, 4),
+				Check: resource.ComposeTestCheckFunc(
+					CivoKubernetesClusterResourceExists(resName, &kubernetes),
+					CivoKubernetesClusterNodePoolResourceExists(resPoolName, &kubernetes, &kubernetesNodePool),
+					resource.TestCheckResourceAttr(resPoolName, "autoscaling", "true"),
+					resource.TestCheckResourceAttr(resPoolName, "ignored_node_count_min", "1"),
+					resource.TestCheckResourceAttr(resPoolName, "ignored_node_count_max", "4"),
+				),
+			},
+			{
+				Config: CivoKubernetesClusterConfigBasic(kubernetesClusterName) + CivoKubernetesClusterNodePoolConfigAutoscaling(2, 6),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resPoolName, "ignored_node_count_min", "2"),
+					resource.TestCheckResourceAttr(resPoolName, "ignored_node_count_max", "6"),
+				),
+			},
+		},
+	})
+}
+
+func CivoKubernetesClusterNodePoolConfigAutoscaling(minNodes, maxNodes int) string {
+	return fmt.Sprintf(`
+resource "civo_kubernetes_node_pool" "foobar" {
+	cluster_id = civo_kubernetes_cluster.foobar.id
+	node_count = 2
+	size = "g4s.kube.small"
+	autoscaling = true
+	ignored_node_count_min = %d
+	ignored_node_count_max = %d
+	depends_on = [civo_kubernetes_cluster.foobar]
+}`, minNodes, maxNodes)
+}
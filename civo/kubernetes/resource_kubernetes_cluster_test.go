@@ -1,11 +1,16 @@
 package kubernetes_test
 
 import (
+	"context"
 	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/civo/civogo"
 	"github.com/civo/terraform-provider-civo/civo/acceptance"
+	"github.com/civo/terraform-provider-civo/civo/kubernetes"
+	"github.com/civo/terraform-provider-civo/internal/mockapi"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
@@ -233,3 +238,326 @@ resource "civo_kubernetes_cluster" "foobar" {
 	}
 }`, name, name)
 }
+
+func TestAccCivoKubernetesCluster_multiplePools(t *testing.T) {
+	var kubernetes civogo.KubernetesCluster
+
+	resName := "civo_kubernetes_cluster.foobar"
+	var kubernetesClusterName = acctest.RandomWithPrefix("tf-test") + "-example"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { acceptance.TestAccPreCheck(t) },
+		Providers:    acceptance.TestAccProviders,
+		CheckDestroy: acceptance.CivoKubernetesClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: CivoKubernetesClusterConfigPools(kubernetesClusterName, `
+	pools {
+		label = "workers"
+		node_count = 2
+		size = "g4s.kube.small"
+	}
+	pools {
+		label = "batch"
+		node_count = 1
+		size = "g4s.kube.small"
+	}`),
+				Check: resource.ComposeTestCheckFunc(
+					CivoKubernetesClusterResourceExists(resName, &kubernetes),
+					resource.TestCheckResourceAttr(resName, "pools.#", "2"),
+					resource.TestCheckResourceAttr(resName, "pools.0.label", "workers"),
+					resource.TestCheckResourceAttr(resName, "pools.1.label", "batch"),
+					resource.TestCheckResourceAttr(resName, "pools.1.node_count", "1"),
+				),
+			},
+			{
+				ResourceName:            resName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"kubeconfig", "write_kubeconfig", "volume_type", "applications"},
+			},
+			{
+				// reorder the pools, resize one and swap the other for a new pool
+				Config: CivoKubernetesClusterConfigPools(kubernetesClusterName, `
+	pools {
+		label = "gpu"
+		node_count = 1
+		size = "g4s.kube.small"
+		labels = {
+			accelerator = "gpu"
+		}
+	}
+	pools {
+		label = "workers"
+		node_count = 3
+		size = "g4s.kube.small"
+	}`),
+				Check: resource.ComposeTestCheckFunc(
+					CivoKubernetesClusterResourceExists(resName, &kubernetes),
+					resource.TestCheckResourceAttr(resName, "pools.#", "2"),
+					resource.TestCheckResourceAttr(resName, "pools.0.label", "gpu"),
+					resource.TestCheckResourceAttr(resName, "pools.0.labels.accelerator", "gpu"),
+					resource.TestCheckResourceAttr(resName, "pools.1.label", "workers"),
+					resource.TestCheckResourceAttr(resName, "pools.1.node_count", "3"),
+					func(_ *terraform.State) error {
+						if len(kubernetes.Pools) != 2 {
+							return fmt.Errorf("expected 2 pools in the cluster, got %d", len(kubernetes.Pools))
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func CivoKubernetesClusterConfigPools(name, pools string) string {
+	return fmt.Sprintf(`
+resource "civo_firewall" "default" {
+	name = "%s"
+	create_default_rules = true
+}
+
+resource "civo_kubernetes_cluster" "foobar" {
+	name = "%s"
+	firewall_id = civo_firewall.default.id
+%s
+}`, name, name, pools)
+}
+
+func TestPlanKubernetesUpgrade(t *testing.T) {
+	versions := []civogo.KubernetesVersion{
+		{Label: "1.29.8-k3s1", Version: "1.29.8-k3s1", Type: "deprecated", ClusterType: "k3s"},
+		{Label: "1.30.4-k3s1", Version: "1.30.4-k3s1", Type: "stable", ClusterType: "k3s"},
+		{Label: "1.30.5-k3s1", Version: "1.30.5-k3s1", Type: "stable", ClusterType: "k3s"},
+		{Label: "1.31.2-k3s1", Version: "1.31.2-k3s1", Type: "stable", ClusterType: "k3s"},
+		{Label: "1.32.5-k3s1", Version: "1.32.5-k3s1", Type: "stable", ClusterType: "k3s"},
+	}
+
+	cases := []struct {
+		name         string
+		from         string
+		to           string
+		expectedPath []string
+		warnings     int
+		wantErr      bool
+	}{
+		{
+			name:         "Next minor version",
+			from:         "1.30.5-k3s1",
+			to:           "1.31.2-k3s1",
+			expectedPath: []string{"1.30.5-k3s1", "1.31.2-k3s1"},
+		},
+		{
+			name:         "Patch version",
+			from:         "1.30.4-k3s1",
+			to:           "1.30.5-k3s1",
+			expectedPath: []string{"1.30.4-k3s1", "1.30.5-k3s1"},
+		},
+		{
+			name:         "Deprecated version",
+			from:         "1.28.3-k3s1",
+			to:           "1.29.8-k3s1",
+			expectedPath: []string{"1.28.3-k3s1", "1.29.8-k3s1"},
+			warnings:     1,
+		},
+		{
+			name:         "Skipped minor version",
+			from:         "1.29.8-k3s1",
+			to:           "1.31.2-k3s1",
+			expectedPath: []string{"1.29.8-k3s1", "1.30.5-k3s1", "1.31.2-k3s1"},
+			wantErr:      true,
+		},
+		{
+			name:    "Downgrade",
+			from:    "1.31.2-k3s1",
+			to:      "1.30.5-k3s1",
+			wantErr: true,
+		},
+		{
+			name:    "Unknown version",
+			from:    "1.31.2-k3s1",
+			to:      "1.31.9-k3s1",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path, warnings, err := kubernetes.ExportPlanKubernetesUpgrade(tc.from, tc.to, versions)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected error: %t, got: %v", tc.wantErr, err)
+			}
+			if tc.expectedPath != nil && !reflect.DeepEqual(path, tc.expectedPath) {
+				t.Fatalf("expected path: %v, got: %v", tc.expectedPath, path)
+			}
+			if len(warnings) != tc.warnings {
+				t.Fatalf("expected %d warnings, got: %v", tc.warnings, warnings)
+			}
+		})
+	}
+}
+
+func TestSameKubernetesVersion(t *testing.T) {
+	cases := []struct {
+		a, b     string
+		expected bool
+	}{
+		{a: "1.31.2-k3s1", b: "1.31.2-k3s1", expected: true},
+		{a: "1.31.2-k3s1", b: "v1.31.2-k3s1", expected: true},
+		{a: "1.31.2-k3s1", b: "v1.31.2+k3s1", expected: true},
+		{a: "talos-v1.5.0", b: "v1.5.0", expected: true},
+		{a: "1.31.2-k3s1", b: "1.31.3-k3s1", expected: false},
+		{a: "1.31.2-k3s1", b: "1.30.2-k3s1", expected: false},
+	}
+
+	for _, tc := range cases {
+		if actual := kubernetes.ExportSameKubernetesVersion(tc.a, tc.b); actual != tc.expected {
+			t.Errorf("expected %s and %s to match: %t, got: %t", tc.a, tc.b, tc.expected, actual)
+		}
+	}
+}
+
+// TestCustomizeDiffKubernetesClusterUpgrade plans version changes of an existing cluster
+// against the fake API, which offers 1.30.5-k3s1, 1.31.2-k3s1 and 1.32.5-k3s1
+func TestCustomizeDiffKubernetesClusterUpgrade(t *testing.T) {
+	server := mockapi.NewServer()
+	defer server.Close()
+	client, err := server.Client(mockapi.DefaultRegion)
+	if err != nil {
+		t.Fatalf("failed to build the client: %s", err)
+	}
+
+	state := &terraform.InstanceState{
+		ID: "cluster",
+		Attributes: map[string]string{
+			"id":                             "cluster",
+			"name":                           "cluster",
+			"region":                         mockapi.DefaultRegion,
+			"cluster_type":                   "k3s",
+			"cni":                            "flannel",
+			"firewall_id":                    "5f0bb8d1-8d6c-4a5b-9a8e-2d3a5c6b7e8f",
+			"kubernetes_version":             "1.30.5-k3s1",
+			"write_kubeconfig":               "false",
+			"pools.#":                        "1",
+			"pools.0.label":                  "workers",
+			"pools.0.size":                   "g4s.kube.small",
+			"pools.0.node_count":             "1",
+			"pools.0.autoscaling":            "false",
+			"pools.0.ignored_node_count_min": "0",
+			"pools.0.ignored_node_count_max": "0",
+		},
+	}
+	config := func(version string) *terraform.ResourceConfig {
+		return terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":               "cluster",
+			"region":             mockapi.DefaultRegion,
+			"cluster_type":       "k3s",
+			"cni":                "flannel",
+			"firewall_id":        "5f0bb8d1-8d6c-4a5b-9a8e-2d3a5c6b7e8f",
+			"kubernetes_version": version,
+			"pools": []interface{}{
+				map[string]interface{}{"label": "workers", "size": "g4s.kube.small", "node_count": 1},
+			},
+		})
+	}
+
+	diff, err := kubernetes.ResourceKubernetesCluster().Diff(context.Background(), state, config("1.31.2-k3s1"), client)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for k, expected := range map[string]string{"upgrade_path.#": "2", "upgrade_path.0": "1.30.5-k3s1", "upgrade_path.1": "1.31.2-k3s1"} {
+		if attr, ok := diff.Attributes[k]; !ok || attr.New != expected {
+			t.Errorf("expected %s to be %q in the plan, got: %#v", k, expected, attr)
+		}
+	}
+
+	if _, err := kubernetes.ResourceKubernetesCluster().Diff(context.Background(), state, config("1.32.5-k3s1"), client); err == nil {
+		t.Error("expected an error for an upgrade that skips a minor version")
+	}
+}
+
+// TestDiffKubernetesClusterReorderedPools plans a cluster whose pools were reordered in the
+// configuration, which is matched by label and doesn't change anything
+func TestDiffKubernetesClusterReorderedPools(t *testing.T) {
+	server := mockapi.NewServer()
+	defer server.Close()
+	client, err := server.Client(mockapi.DefaultRegion)
+	if err != nil {
+		t.Fatalf("failed to build the client: %s", err)
+	}
+
+	state := &terraform.InstanceState{
+		ID: "cluster",
+		Attributes: map[string]string{
+			"id":                             "cluster",
+			"name":                           "cluster",
+			"region":                         mockapi.DefaultRegion,
+			"cluster_type":                   "k3s",
+			"cni":                            "flannel",
+			"firewall_id":                    "5f0bb8d1-8d6c-4a5b-9a8e-2d3a5c6b7e8f",
+			"kubernetes_version":             "1.30.5-k3s1",
+			"write_kubeconfig":               "false",
+			"pools.#":                        "2",
+			"pools.0.label":                  "workers",
+			"pools.0.size":                   "g4s.kube.small",
+			"pools.0.node_count":             "3",
+			"pools.0.autoscaling":            "false",
+			"pools.0.ignored_node_count_min": "0",
+			"pools.0.ignored_node_count_max": "0",
+			"pools.0.labels.%":               "1",
+			"pools.0.labels.tier":            "web",
+			"pools.1.label":                  "batch",
+			"pools.1.size":                   "g4s.kube.large",
+			"pools.1.node_count":             "1",
+			"pools.1.autoscaling":            "false",
+			"pools.1.ignored_node_count_min": "0",
+			"pools.1.ignored_node_count_max": "0",
+		},
+	}
+	config := func(pools ...interface{}) *terraform.ResourceConfig {
+		return terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":               "cluster",
+			"region":             mockapi.DefaultRegion,
+			"cluster_type":       "k3s",
+			"cni":                "flannel",
+			"firewall_id":        "5f0bb8d1-8d6c-4a5b-9a8e-2d3a5c6b7e8f",
+			"kubernetes_version": "1.30.5-k3s1",
+			"pools":              pools,
+		})
+	}
+	workers := map[string]interface{}{"label": "workers", "size": "g4s.kube.small", "node_count": 3, "labels": map[string]interface{}{"tier": "web"}}
+	batch := map[string]interface{}{"label": "batch", "size": "g4s.kube.large", "node_count": 1}
+	resizedBatch := map[string]interface{}{"label": "batch", "size": "g4s.kube.large", "node_count": 2}
+
+	cases := []struct {
+		name       string
+		config     *terraform.ResourceConfig
+		wantChange bool
+	}{
+		{name: "same order", config: config(workers, batch)},
+		{name: "reordered", config: config(batch, workers)},
+		{name: "reordered and resized", config: config(resizedBatch, workers), wantChange: true},
+		{name: "pool removed", config: config(batch), wantChange: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			diff, err := kubernetes.ResourceKubernetesCluster().Diff(context.Background(), state, tc.config, client)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			changed := []string{}
+			if diff != nil {
+				for k := range diff.Attributes {
+					if strings.HasPrefix(k, "pools.") {
+						changed = append(changed, k)
+					}
+				}
+			}
+			if tc.wantChange != (len(changed) > 0) {
+				t.Errorf("expected a change of the pools: %t, got: %v", tc.wantChange, changed)
+			}
+		})
+	}
+}
@@ -0,0 +1,715 @@
+package loadbalancer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"time"
+
+	"github.com/civo/civogo"
+	"github.com/civo/terraform-provider-civo/internal/utils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// ResourceLoadBalancer function returns a schema.Resource that represents a Load Balancer.
+// This can be used to create, read, update, and delete operations for a Load Balancer in the infrastructure.
+func ResourceLoadBalancer() *schema.Resource {
+	return &schema.Resource{
+		Description: "Provides a Civo load balancer resource. This can be used to create, modify, and delete load balancers.",
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: utils.ValidateName,
+				Description:  "The name of the load balancer",
+			},
+			"region": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				Description:      "The region of the load balancer, if is not defined we use the global defined in the provider",
+				DiffSuppressFunc: utils.IgnoreCaseDiff,
+			},
+			"network_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: utils.ValidateUUID,
+				Description:  "The network of the load balancer, if is not defined we use the default network",
+			},
+			"algorithm": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "round_robin",
+				Description: "The algorithm used to balance the traffic, can be `round_robin` or `least_connections` (the default if unspecified is `round_robin`)",
+				ValidateFunc: validation.StringInSlice([]string{
+					"round_robin",
+					"least_connections",
+				}, false),
+			},
+			"external_traffic_policy": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The external traffic policy of the load balancer, can be `Cluster` or `Local`",
+				ValidateFunc: validation.StringInSlice([]string{
+					"Cluster",
+					"Local",
+				}, false),
+			},
+			"session_affinity": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The session affinity of the load balancer, can be `ClientIP` or `None`",
+				ValidateFunc: validation.StringInSlice([]string{
+					"ClientIP",
+					"None",
+				}, false),
+			},
+			"session_affinity_config_timeout": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				Description:  "The session affinity timeout in seconds, only used when `session_affinity` is `ClientIP`",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"enable_proxy_protocol": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The proxy protocol to send to the backends, can be `send-proxy` or `send-proxy-v2`",
+				ValidateFunc: validation.StringInSlice([]string{
+					"send-proxy",
+					"send-proxy-v2",
+				}, false),
+			},
+			"max_concurrent_requests": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				Description:  "The maximum number of concurrent requests the load balancer will accept",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"server_timeout": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The timeout for the connection to the backends, e.g. `60s`",
+			},
+			"client_timeout": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The timeout for the connection from the clients, e.g. `60s`",
+			},
+			"firewall_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: utils.ValidateUUID,
+				Description:  "The ID of the firewall to use, if is not defined a new firewall will be created for the load balancer",
+			},
+			"firewall_rule": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"firewall_id"},
+				Description:   "The ports to open in the firewall created for the load balancer, e.g. `80,443` or `all`. Only used when `firewall_id` is not defined",
+				ValidateFunc:  validation.NoZeroValues,
+			},
+			"reserved_ip_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.NoZeroValues,
+				Description:  "The ID of a reserved IP to assign to the load balancer",
+			},
+			"backend": {
+				Type:         schema.TypeSet,
+				Optional:     true,
+				MinItems:     1,
+				Elem:         loadBalancerBackendSchema(),
+				AtLeastOneOf: []string{"backend", "instance_pool"},
+				Description:  "The backends of the load balancer, every backend receives the traffic sent to its source port",
+			},
+			"instance_pool": {
+				Type:         schema.TypeSet,
+				Optional:     true,
+				MinItems:     1,
+				Elem:         loadBalancerInstancePoolSchema(),
+				AtLeastOneOf: []string{"backend", "instance_pool"},
+				Description:  "Pools of instances selected by tag or name, every instance of a pool receives the traffic sent to its source port. Pools support HTTP health checks on a path",
+			},
+			// Computed resource
+			"public_ip": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The public ip of the load balancer",
+			},
+			"private_ip": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The private ip of the load balancer",
+			},
+			"reserved_ip": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The reserved ip assigned to the load balancer",
+			},
+			"cluster_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The cluster id of the load balancer",
+			},
+			"state": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The state of the load balancer",
+			},
+		},
+		CreateContext: resourceLoadBalancerCreate,
+		ReadContext:   resourceLoadBalancerRead,
+		UpdateContext: resourceLoadBalancerUpdate,
+		DeleteContext: resourceLoadBalancerDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
+	}
+}
+
+func loadBalancerBackendSchema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"ip": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.IsIPAddress,
+				Description:  "The IP address of the backend",
+			},
+			"protocol": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "TCP",
+				Description: "The protocol used by the backend, can be `TCP` or `UDP` (the default if unspecified is `TCP`)",
+				ValidateFunc: validation.StringInSlice([]string{
+					"TCP",
+					"UDP",
+				}, false),
+			},
+			"source_port": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IsPortNumber,
+				Description:  "The port the load balancer listens on",
+			},
+			"target_port": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IsPortNumber,
+				Description:  "The port on the backend the traffic is sent to",
+			},
+			"health_check_port": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IsPortNumber,
+				Description:  "The port on the backend used to check its health, if is not defined the target port is used",
+			},
+		},
+	}
+}
+
+func loadBalancerInstancePoolSchema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"tags": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The tags of the instances in the pool",
+			},
+			"names": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The names of the instances in the pool",
+			},
+			"protocol": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "TCP",
+				Description: "The protocol used by the pool, can be `TCP` or `UDP` (the default if unspecified is `TCP`)",
+				ValidateFunc: validation.StringInSlice([]string{
+					"TCP",
+					"UDP",
+				}, false),
+			},
+			"source_port": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IsPortNumber,
+				Description:  "The port the load balancer listens on",
+			},
+			"target_port": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IsPortNumber,
+				Description:  "The port on the instances the traffic is sent to",
+			},
+			"health_check_port": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IsPortNumber,
+				Description:  "The port on the instances used to check their health, if is not defined the target port is used",
+			},
+			"health_check_path": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^/`), "must start with /"),
+				Description:  "The HTTP path requested to check the health of the instances, e.g. `/healthz`",
+			},
+		},
+	}
+}
+
+// function to create a load balancer
+func resourceLoadBalancerCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*civogo.Client)
+
+	// overwrite the region if it's defined
+	if region, ok := d.GetOk("region"); ok {
+		apiClient = utils.RegionalClient(apiClient, region.(string))
+	}
+
+	log.Printf("[INFO] configuring the load balancer %s", d.Get("name").(string))
+
+	config := &civogo.LoadBalancerConfig{
+		Region:                       apiClient.Region,
+		Name:                         d.Get("name").(string),
+		Algorithm:                    d.Get("algorithm").(string),
+		Backends:                     expandLoadBalancerBackend(d.Get("backend").(*schema.Set).List()),
+		InstancePools:                expandLoadBalancerInstancePools(d.Get("instance_pool").(*schema.Set).List()),
+		ExternalTrafficPolicy:        d.Get("external_traffic_policy").(string),
+		SessionAffinity:              d.Get("session_affinity").(string),
+		SessionAffinityConfigTimeout: int32(d.Get("session_affinity_config_timeout").(int)),
+		EnableProxyProtocol:          d.Get("enable_proxy_protocol").(string),
+		FirewallRules:                d.Get("firewall_rule").(string),
+		LoadBalancerOptions:          expandLoadBalancerOptions(d),
+	}
+
+	if attr, ok := d.GetOk("max_concurrent_requests"); ok {
+		maxConcurrentRequests := attr.(int)
+		config.MaxConcurrentRequests = &maxConcurrentRequests
+	}
+
+	if attr, ok := d.GetOk("network_id"); ok {
+		config.NetworkID = attr.(string)
+	} else {
+		network, err := apiClient.GetDefaultVPCNetwork()
+		if err != nil {
+			return diag.Errorf("[ERR] failed to get the default network: %s", err)
+		}
+		config.NetworkID = network.ID
+	}
+
+	if attr, ok := d.GetOk("firewall_id"); ok {
+		firewall, err := findLoadBalancerFirewall(apiClient, attr.(string), config.NetworkID)
+		if err != nil {
+			return diag.Errorf("[ERR] %s", err)
+		}
+		config.FirewallID = firewall.ID
+	}
+
+	log.Printf("[INFO] creating the load balancer %s", config.Name)
+	loadBalancer, err := apiClient.CreateVPCLoadBalancer(config)
+	if err != nil {
+		return diag.Errorf("[ERR] failed to create the load balancer: %s", err)
+	}
+
+	d.SetId(loadBalancer.ID)
+
+	if err := waitForLoadBalancerAvailable(ctx, apiClient, d.Id(), d.Timeout(schema.TimeoutCreate)); err != nil {
+		return diag.Errorf("error waiting for load balancer (%s) to be created: %s", d.Id(), err)
+	}
+
+	if attr, ok := d.GetOk("reserved_ip_id"); ok {
+		if err := assignLoadBalancerReservedIP(ctx, apiClient, d.Id(), attr.(string), d.Timeout(schema.TimeoutCreate)); err != nil {
+			return diag.Errorf("[ERR] %s", err)
+		}
+	}
+
+	return resourceLoadBalancerRead(ctx, d, m)
+}
+
+// function to read a load balancer
+func resourceLoadBalancerRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*civogo.Client)
+
+	// overwrite the region if it's defined
+	if region, ok := d.GetOk("region"); ok {
+		apiClient = utils.RegionalClient(apiClient, region.(string))
+	}
+
+	log.Printf("[INFO] retrieving the load balancer %s", d.Id())
+	resp, err := apiClient.GetVPCLoadBalancer(d.Id())
+	if err != nil {
+		if errors.Is(err, civogo.DatabaseLoadBalancerNotFoundError) {
+			log.Printf("[INFO] load balancer %s not found, removing it from the state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("[ERR] failed to retrieve the load balancer: %s", err)
+	}
+
+	d.Set("name", resp.Name)
+	d.Set("region", apiClient.Region)
+	d.Set("network_id", resp.NetworkID)
+	d.Set("algorithm", resp.Algorithm)
+	d.Set("external_traffic_policy", resp.ExternalTrafficPolicy)
+	d.Set("session_affinity", resp.SessionAffinity)
+	d.Set("session_affinity_config_timeout", resp.SessionAffinityConfigTimeout)
+	d.Set("enable_proxy_protocol", resp.EnableProxyProtocol)
+	d.Set("max_concurrent_requests", resp.MaxConcurrentRequests)
+	d.Set("firewall_id", resp.FirewallID)
+	d.Set("reserved_ip_id", resp.ReservedIPID)
+	d.Set("reserved_ip", resp.ReservedIP)
+	d.Set("public_ip", resp.PublicIP)
+	d.Set("private_ip", resp.PrivateIP)
+	d.Set("cluster_id", resp.ClusterID)
+	d.Set("state", resp.State)
+
+	if resp.Options != nil {
+		d.Set("server_timeout", resp.Options.ServerTimeout)
+		d.Set("client_timeout", resp.Options.ClientTimeout)
+	}
+
+	if err := d.Set("backend", flattenLoadBalancerBackend(resp.Backends)); err != nil {
+		return diag.Errorf("[ERR] error setting the backends for load balancer: %s", err)
+	}
+
+	if err := d.Set("instance_pool", flattenLoadBalancerInstancePools(resp.InstancePool)); err != nil {
+		return diag.Errorf("[ERR] error setting the instance pools for load balancer: %s", err)
+	}
+
+	return nil
+}
+
+// function to update a load balancer
+func resourceLoadBalancerUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*civogo.Client)
+
+	// overwrite the region if it's defined
+	if region, ok := d.GetOk("region"); ok {
+		apiClient = utils.RegionalClient(apiClient, region.(string))
+	}
+
+	if d.HasChanges("name", "algorithm", "external_traffic_policy", "session_affinity", "session_affinity_config_timeout",
+		"enable_proxy_protocol", "max_concurrent_requests", "server_timeout", "client_timeout", "firewall_id", "backend", "instance_pool") {
+		config := &civogo.LoadBalancerUpdateConfig{
+			Region:                       apiClient.Region,
+			Name:                         d.Get("name").(string),
+			Algorithm:                    d.Get("algorithm").(string),
+			Backends:                     expandLoadBalancerBackend(d.Get("backend").(*schema.Set).List()),
+			InstancePools:                expandLoadBalancerInstancePools(d.Get("instance_pool").(*schema.Set).List()),
+			ExternalTrafficPolicy:        d.Get("external_traffic_policy").(string),
+			SessionAffinity:              d.Get("session_affinity").(string),
+			SessionAffinityConfigTimeout: int32(d.Get("session_affinity_config_timeout").(int)),
+			EnableProxyProtocol:          d.Get("enable_proxy_protocol").(string),
+			FirewallID:                   d.Get("firewall_id").(string),
+			LoadBalancerOptions:          expandLoadBalancerOptions(d),
+		}
+
+		if attr, ok := d.GetOk("max_concurrent_requests"); ok {
+			maxConcurrentRequests := attr.(int)
+			config.MaxConcurrentRequests = &maxConcurrentRequests
+		}
+
+		// the new firewall has to be in the network of the load balancer, as on create
+		if d.HasChange("firewall_id") && config.FirewallID != "" {
+			if _, err := findLoadBalancerFirewall(apiClient, config.FirewallID, d.Get("network_id").(string)); err != nil {
+				return diag.Errorf("[ERR] %s", err)
+			}
+		}
+
+		log.Printf("[INFO] updating the load balancer %s", d.Id())
+		_, err := apiClient.UpdateVPCLoadBalancer(d.Id(), config)
+		if err != nil {
+			return diag.Errorf("[ERR] failed to update the load balancer %s: %s", d.Id(), err)
+		}
+
+		if err := waitForLoadBalancerAvailable(ctx, apiClient, d.Id(), d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return diag.Errorf("error waiting for load balancer (%s) to be updated: %s", d.Id(), err)
+		}
+	}
+
+	if d.HasChange("reserved_ip_id") {
+		oldReservedIP, newReservedIP := d.GetChange("reserved_ip_id")
+
+		if oldReservedIP.(string) != "" {
+			log.Printf("[INFO] unassigning the reserved ip %s from the load balancer %s", oldReservedIP.(string), d.Id())
+			if err := unassignLoadBalancerReservedIP(ctx, apiClient, oldReservedIP.(string), d.Timeout(schema.TimeoutUpdate)); err != nil {
+				return diag.Errorf("[ERR] %s", err)
+			}
+		}
+
+		if newReservedIP.(string) != "" {
+			if err := assignLoadBalancerReservedIP(ctx, apiClient, d.Id(), newReservedIP.(string), d.Timeout(schema.TimeoutUpdate)); err != nil {
+				return diag.Errorf("[ERR] %s", err)
+			}
+		}
+	}
+
+	return resourceLoadBalancerRead(ctx, d, m)
+}
+
+// function to delete a load balancer
+func resourceLoadBalancerDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*civogo.Client)
+
+	// overwrite the region if it's defined
+	if region, ok := d.GetOk("region"); ok {
+		apiClient = utils.RegionalClient(apiClient, region.(string))
+	}
+
+	if attr, ok := d.GetOk("reserved_ip_id"); ok {
+		log.Printf("[INFO] unassigning the reserved ip %s from the load balancer %s", attr.(string), d.Id())
+		if err := unassignLoadBalancerReservedIP(ctx, apiClient, attr.(string), d.Timeout(schema.TimeoutDelete)); err != nil {
+			return diag.Errorf("[ERR] %s", err)
+		}
+	}
+
+	log.Printf("[INFO] deleting the load balancer %s", d.Id())
+	_, err := apiClient.DeleteVPCLoadBalancer(d.Id())
+	if err != nil {
+		if errors.Is(err, civogo.DatabaseLoadBalancerNotFoundError) {
+			return nil
+		}
+		return diag.Errorf("[ERR] an error occurred while trying to delete the load balancer %s: %s", d.Id(), err)
+	}
+
+	// Wait for the load balancer to be completely deleted, otherwise
+	// the network and the firewall can't be removed right after it
+	deleteStateConf := &retry.StateChangeConf{
+		Pending: []string{"deleting"},
+		Target:  []string{"deleted"},
+		Refresh: func() (interface{}, string, error) {
+			resp, err := apiClient.GetVPCLoadBalancer(d.Id())
+			if err != nil {
+				if errors.Is(err, civogo.DatabaseLoadBalancerNotFoundError) {
+					return 0, "deleted", nil
+				}
+				return 0, "", err
+			}
+			return resp, "deleting", nil
+		},
+		Timeout:        d.Timeout(schema.TimeoutDelete),
+		Delay:          5 * time.Second,
+		MinTimeout:     3 * time.Second,
+		NotFoundChecks: 10,
+	}
+	_, err = deleteStateConf.WaitForStateContext(ctx)
+	if err != nil {
+		return diag.Errorf("error waiting for load balancer (%s) to be deleted: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+// waitForLoadBalancerAvailable waits until the load balancer reports the available state
+func waitForLoadBalancerAvailable(ctx context.Context, apiClient *civogo.Client, id string, timeout time.Duration) error {
+	stateConf := &retry.StateChangeConf{
+		Pending: []string{"pending"},
+		Target:  []string{"available"},
+		Refresh: func() (interface{}, string, error) {
+			resp, err := apiClient.GetVPCLoadBalancer(id)
+			if err != nil {
+				return 0, "", err
+			}
+			if resp.State != "available" {
+				return resp, "pending", nil
+			}
+			return resp, resp.State, nil
+		},
+		Timeout:        timeout,
+		Delay:          3 * time.Second,
+		MinTimeout:     3 * time.Second,
+		NotFoundChecks: 10,
+	}
+	_, err := stateConf.WaitForStateContext(ctx)
+	return err
+}
+
+// assignLoadBalancerReservedIP assigns the reserved ip to the load balancer and waits until it's done
+func assignLoadBalancerReservedIP(ctx context.Context, apiClient *civogo.Client, loadBalancerID, reservedIPID string, timeout time.Duration) error {
+	reservedIP, err := apiClient.FindVPCIP(reservedIPID)
+	if err != nil {
+		return fmt.Errorf("an error occurred while trying to get reserved ip %s: %s", reservedIPID, err)
+	}
+
+	if reservedIP.AssignedTo.ID != "" && reservedIP.AssignedTo.ID != loadBalancerID {
+		return fmt.Errorf("the reserved ip %s is already assigned to the %s %s", reservedIP.ID, reservedIP.AssignedTo.Type, reservedIP.AssignedTo.ID)
+	}
+
+	log.Printf("[INFO] assigning the reserved ip %s to the load balancer %s", reservedIP.ID, loadBalancerID)
+	_, err = apiClient.AssignVPCIP(reservedIP.ID, loadBalancerID, "loadbalancer", apiClient.Region)
+	if err != nil {
+		return fmt.Errorf("an error occurred while trying to assign reserved ip %s to load balancer %s: %s", reservedIP.ID, loadBalancerID, err)
+	}
+
+	stateConf := &retry.StateChangeConf{
+		Pending: []string{"PENDING"},
+		Target:  []string{"ASSIGNED"},
+		Refresh: func() (interface{}, string, error) {
+			resp, err := apiClient.GetVPCLoadBalancer(loadBalancerID)
+			if err != nil {
+				return 0, "", err
+			}
+			if resp.ReservedIPID != reservedIP.ID || resp.State != "available" {
+				return 0, "PENDING", nil
+			}
+			return resp, "ASSIGNED", nil
+		},
+		Timeout:        timeout,
+		Delay:          3 * time.Second,
+		MinTimeout:     3 * time.Second,
+		NotFoundChecks: 60,
+	}
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return fmt.Errorf("error waiting for reserved ip %s to be assigned to the load balancer %s: %s", reservedIP.ID, loadBalancerID, err)
+	}
+
+	return nil
+}
+
+// unassignLoadBalancerReservedIP unassigns the reserved ip and waits until it's free again
+func unassignLoadBalancerReservedIP(ctx context.Context, apiClient *civogo.Client, reservedIPID string, timeout time.Duration) error {
+	_, err := apiClient.UnassignVPCIP(reservedIPID, apiClient.Region)
+	if err != nil {
+		return fmt.Errorf("an error occurred while trying to unassign the reserved ip %s: %s", reservedIPID, err)
+	}
+
+	stateConf := &retry.StateChangeConf{
+		Pending: []string{"PENDING"},
+		Target:  []string{"DONE"},
+		Refresh: func() (interface{}, string, error) {
+			resp, err := apiClient.FindVPCIP(reservedIPID)
+			if err != nil {
+				return 0, "", err
+			}
+			if resp.AssignedTo.ID != "" {
+				return 0, "PENDING", nil
+			}
+			return resp, "DONE", nil
+		},
+		Timeout:        timeout,
+		Delay:          3 * time.Second,
+		MinTimeout:     3 * time.Second,
+		NotFoundChecks: 60,
+	}
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return fmt.Errorf("error waiting for reserved ip %s to be unassigned: %s", reservedIPID, err)
+	}
+
+	return nil
+}
+
+// expandLoadBalancerBackend expands the backends to the api config
+func expandLoadBalancerBackend(backends []interface{}) []civogo.LoadBalancerBackendConfig {
+	expandedBackends := make([]civogo.LoadBalancerBackendConfig, 0, len(backends))
+	for _, v := range backends {
+		backend := v.(map[string]interface{})
+		expandedBackends = append(expandedBackends, civogo.LoadBalancerBackendConfig{
+			IP:              backend["ip"].(string),
+			Protocol:        backend["protocol"].(string),
+			SourcePort:      int32(backend["source_port"].(int)),
+			TargetPort:      int32(backend["target_port"].(int)),
+			HealthCheckPort: int32(backend["health_check_port"].(int)),
+		})
+	}
+
+	return expandedBackends
+}
+
+func expandLoadBalancerInstancePools(pools []interface{}) []civogo.LoadBalancerInstancePoolConfig {
+	expandedPools := make([]civogo.LoadBalancerInstancePoolConfig, 0, len(pools))
+	for _, v := range pools {
+		pool := v.(map[string]interface{})
+		expandedPools = append(expandedPools, civogo.LoadBalancerInstancePoolConfig{
+			Tags:       expandStringSet(pool["tags"]),
+			Names:      expandStringSet(pool["names"]),
+			Protocol:   pool["protocol"].(string),
+			SourcePort: int32(pool["source_port"].(int)),
+			TargetPort: int32(pool["target_port"].(int)),
+			HealthCheck: civogo.HealthCheck{
+				Port: int32(pool["health_check_port"].(int)),
+				Path: pool["health_check_path"].(string),
+			},
+		})
+	}
+
+	return expandedPools
+}
+
+// function to flatten the instance pools of the load balancer when they are coming from the api
+func flattenLoadBalancerInstancePools(pools []civogo.InstancePool) []interface{} {
+	flattenedPools := make([]interface{}, 0, len(pools))
+	for _, pool := range pools {
+		flattenedPools = append(flattenedPools, map[string]interface{}{
+			"tags":              pool.Tags,
+			"names":             pool.Names,
+			"protocol":          pool.Protocol,
+			"source_port":       pool.SourcePort,
+			"target_port":       pool.TargetPort,
+			"health_check_port": pool.HealthCheck.Port,
+			"health_check_path": pool.HealthCheck.Path,
+		})
+	}
+
+	return flattenedPools
+}
+
+func expandStringSet(raw interface{}) []string {
+	result := []string{}
+	for _, v := range raw.(*schema.Set).List() {
+		result = append(result, v.(string))
+	}
+	return result
+}
+
+// findLoadBalancerFirewall returns the firewall, which has to be in the network of the load balancer
+func findLoadBalancerFirewall(apiClient *civogo.Client, firewallID, networkID string) (*civogo.Firewall, error) {
+	firewall, err := apiClient.FindVPCFirewall(firewallID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find firewall - %s", err)
+	}
+
+	if firewall.NetworkID != networkID {
+		return nil, fmt.Errorf("firewall %s is not part of network %s", firewall.ID, networkID)
+	}
+
+	return firewall, nil
+}
+
+// expandLoadBalancerOptions returns the options of the load balancer, or nil if none is set
+func expandLoadBalancerOptions(d *schema.ResourceData) *civogo.LoadBalancerOptions {
+	serverTimeout := d.Get("server_timeout").(string)
+	clientTimeout := d.Get("client_timeout").(string)
+	if serverTimeout == "" && clientTimeout == "" {
+		return nil
+	}
+
+	return &civogo.LoadBalancerOptions{
+		ServerTimeout: serverTimeout,
+		ClientTimeout: clientTimeout,
+	}
+}
@@ -0,0 +1,281 @@
+package loadbalancer_test
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/civo/civogo"
+	"github.com/civo/terraform-provider-civo/civo/acceptance"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccCivoLoadBalancer_basic(t *testing.T) {
+	var loadBalancer civogo.LoadBalancer
+
+	// generate a random name for each test run
+	resName := "civo_loadbalancer.foobar"
+	var lbName = acctest.RandomWithPrefix("tf-lb")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { acceptance.TestAccPreCheck(t) },
+		Providers:    acceptance.TestAccProviders,
+		CheckDestroy: CivoLoadBalancerDestroy,
+		Steps: []resource.TestStep{
+			{
+				// use a dynamic configuration with the random name from above
+				Config: CivoLoadBalancerConfigBasic(lbName),
+				// compose a basic test, checking both remote and local values
+				Check: resource.ComposeTestCheckFunc(
+					// query the API to retrieve the load balancer object
+					CivoLoadBalancerResourceExists(resName, &loadBalancer),
+					// verify remote values
+					CivoLoadBalancerValues(&loadBalancer, lbName),
+					// verify local values
+					resource.TestCheckResourceAttr(resName, "name", lbName),
+					resource.TestCheckResourceAttr(resName, "algorithm", "round_robin"),
+					resource.TestCheckResourceAttr(resName, "backend.#", "1"),
+					resource.TestCheckResourceAttrSet(resName, "public_ip"),
+					resource.TestCheckResourceAttrSet(resName, "firewall_id"),
+				),
+			},
+			{
+				ResourceName:      resName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				// firewall_rule is only used when the load balancer is created
+				ImportStateVerifyIgnore: []string{"firewall_rule"},
+			},
+		},
+	})
+}
+
+func TestAccCivoLoadBalancer_update(t *testing.T) {
+	var loadBalancer civogo.LoadBalancer
+
+	// generate a random name for each test run
+	resName := "civo_loadbalancer.foobar"
+	var lbName = acctest.RandomWithPrefix("tf-lb")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { acceptance.TestAccPreCheck(t) },
+		Providers:    acceptance.TestAccProviders,
+		CheckDestroy: CivoLoadBalancerDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: CivoLoadBalancerConfigBasic(lbName),
+				Check: resource.ComposeTestCheckFunc(
+					CivoLoadBalancerResourceExists(resName, &loadBalancer),
+					CivoLoadBalancerValues(&loadBalancer, lbName),
+					resource.TestCheckResourceAttr(resName, "name", lbName),
+				),
+			},
+			{
+				// use a dynamic configuration with the random name from above
+				Config: CivoLoadBalancerConfigUpdates(lbName),
+				Check: resource.ComposeTestCheckFunc(
+					CivoLoadBalancerResourceExists(resName, &loadBalancer),
+					CivoLoadBalancerUpdated(&loadBalancer, lbName),
+					resource.TestCheckResourceAttr(resName, "name", fmt.Sprintf("rename-%s", lbName)),
+					resource.TestCheckResourceAttr(resName, "algorithm", "least_connections"),
+					resource.TestCheckResourceAttr(resName, "session_affinity", "ClientIP"),
+					resource.TestCheckResourceAttr(resName, "backend.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+// TestAccCivoLoadBalancer_instancePool balances the traffic to instances selected by tag with an
+// HTTP health check
+func TestAccCivoLoadBalancer_instancePool(t *testing.T) {
+	var loadBalancer civogo.LoadBalancer
+
+	resName := "civo_loadbalancer.foobar"
+	var lbName = acctest.RandomWithPrefix("tf-lb")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { acceptance.TestAccPreCheck(t) },
+		Providers:    acceptance.TestAccProviders,
+		CheckDestroy: CivoLoadBalancerDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: CivoLoadBalancerConfigInstancePool(lbName),
+				Check: resource.ComposeTestCheckFunc(
+					CivoLoadBalancerResourceExists(resName, &loadBalancer),
+					resource.TestCheckResourceAttr(resName, "backend.#", "0"),
+					resource.TestCheckResourceAttr(resName, "instance_pool.#", "1"),
+					resource.TestCheckTypeSetElemNestedAttrs(resName, "instance_pool.*", map[string]string{
+						"source_port":       "80",
+						"target_port":       "8080",
+						"health_check_port": "8081",
+						"health_check_path": "/healthz",
+					}),
+					resource.TestCheckTypeSetElemAttr(resName, "instance_pool.*.tags.*", "web"),
+				),
+			},
+		},
+	})
+}
+
+// TestAccCivoLoadBalancer_firewallNetwork checks the firewall of a load balancer can't be changed
+// to a firewall of another network
+func TestAccCivoLoadBalancer_firewallNetwork(t *testing.T) {
+	var lbName = acctest.RandomWithPrefix("tf-lb")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { acceptance.TestAccPreCheck(t) },
+		Providers:    acceptance.TestAccProviders,
+		CheckDestroy: CivoLoadBalancerDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: CivoLoadBalancerConfigFirewall(lbName, "") + CivoLoadBalancerConfigOtherNetworkFirewall(lbName),
+			},
+			{
+				// firewall_id is computed, setting it is an in-place update
+				Config:      CivoLoadBalancerConfigFirewall(lbName, "firewall_id = civo_firewall.other.id") + CivoLoadBalancerConfigOtherNetworkFirewall(lbName),
+				ExpectError: regexp.MustCompile(`is not part of network`),
+			},
+		},
+	})
+}
+
+func CivoLoadBalancerValues(loadBalancer *civogo.LoadBalancer, name string) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		if loadBalancer.Name != name {
+			return fmt.Errorf("bad name, expected \"%s\", got: %#v", name, loadBalancer.Name)
+		}
+		return nil
+	}
+}
+
+// CivoLoadBalancerResourceExists queries the API and retrieves the matching load balancer.
+func CivoLoadBalancerResourceExists(n string, loadBalancer *civogo.LoadBalancer) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		// find the corresponding state object
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("not found: %s", n)
+		}
+
+		// retrieve the configured client from the test setup
+		client := acceptance.TestAccProvider.Meta().(*civogo.Client)
+		resp, err := client.GetVPCLoadBalancer(rs.Primary.ID)
+		if err != nil {
+			return fmt.Errorf("load balancer not found: (%s) %s", rs.Primary.ID, err)
+		}
+
+		*loadBalancer = *resp
+
+		return nil
+	}
+}
+
+func CivoLoadBalancerUpdated(loadBalancer *civogo.LoadBalancer, name string) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		if loadBalancer.Name != fmt.Sprintf("rename-%s", name) {
+			return fmt.Errorf("bad name, expected \"%s\", got: %#v", fmt.Sprintf("rename-%s", name), loadBalancer.Name)
+		}
+		return nil
+	}
+}
+
+func CivoLoadBalancerDestroy(s *terraform.State) error {
+	client := acceptance.TestAccProvider.Meta().(*civogo.Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "civo_loadbalancer" {
+			continue
+		}
+
+		_, err := client.GetVPCLoadBalancer(rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("load balancer still exists")
+		}
+	}
+
+	return nil
+}
+
+func CivoLoadBalancerConfigBasic(name string) string {
+	return fmt.Sprintf(`
+resource "civo_loadbalancer" "foobar" {
+	name          = "%s"
+	firewall_rule = "80"
+
+	backend {
+		ip          = "192.168.1.10"
+		source_port = 80
+		target_port = 8080
+	}
+}`, name)
+}
+
+func CivoLoadBalancerConfigUpdates(name string) string {
+	return fmt.Sprintf(`
+resource "civo_loadbalancer" "foobar" {
+	name                            = "rename-%s"
+	firewall_rule                   = "80"
+	algorithm                       = "least_connections"
+	session_affinity                = "ClientIP"
+	session_affinity_config_timeout = 10800
+
+	backend {
+		ip                = "192.168.1.10"
+		source_port       = 80
+		target_port       = 8080
+		health_check_port = 8081
+	}
+
+	backend {
+		ip          = "192.168.1.11"
+		source_port = 80
+		target_port = 8080
+	}
+}`, name)
+}
+
+func CivoLoadBalancerConfigInstancePool(name string) string {
+	return fmt.Sprintf(`
+resource "civo_loadbalancer" "foobar" {
+	name          = "%s"
+	firewall_rule = "80"
+
+	instance_pool {
+		tags              = ["web"]
+		source_port       = 80
+		target_port       = 8080
+		health_check_port = 8081
+		health_check_path = "/healthz"
+	}
+}`, name)
+}
+
+func CivoLoadBalancerConfigOtherNetworkFirewall(name string) string {
+	return fmt.Sprintf(`
+resource "civo_network" "other" {
+	label = "%[1]s-other"
+}
+
+resource "civo_firewall" "other" {
+	name                 = "%[1]s-other"
+	network_id           = civo_network.other.id
+	create_default_rules = true
+}`, name)
+}
+
+func CivoLoadBalancerConfigFirewall(name, firewall string) string {
+	return fmt.Sprintf(`
+resource "civo_loadbalancer" "foobar" {
+	name = "%s"
+	%s
+
+	backend {
+		ip          = "192.168.1.10"
+		source_port = 80
+		target_port = 8080
+	}
+}`, name, firewall)
+}
@@ -0,0 +1,216 @@
+package network
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/civo/civogo"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// privateRanges are the RFC 1918 ranges the CIDRs of a network have to fall inside
+var privateRanges = []netip.Prefix{
+	netip.MustParsePrefix("10.0.0.0/8"),
+	netip.MustParsePrefix("172.16.0.0/12"),
+	netip.MustParsePrefix("192.168.0.0/16"),
+}
+
+// parsePrivateCIDR parses an IPv4 CIDR and checks it's inside a private range. The CIDR
+// is masked, so 10.0.0.1/24 is the block 10.0.0.0/24
+func parsePrivateCIDR(cidr string) (netip.Prefix, error) {
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil || !prefix.Addr().Is4() {
+		return netip.Prefix{}, fmt.Errorf("%q is not a valid IPv4 CIDR, e.g. 10.0.0.0/24", cidr)
+	}
+	prefix = prefix.Masked()
+	for _, private := range privateRanges {
+		if private.Bits() <= prefix.Bits() && private.Contains(prefix.Addr()) {
+			return prefix, nil
+		}
+	}
+	return netip.Prefix{}, fmt.Errorf("%s is not inside a private range (10.0.0.0/8, 172.16.0.0/12 or 192.168.0.0/16)", cidr)
+}
+
+// validatePrivateCIDR is a ValidateFunc for the CIDR arguments of a network
+func validatePrivateCIDR(v interface{}, k string) (ws []string, es []error) {
+	if _, err := parsePrivateCIDR(v.(string)); err != nil {
+		es = append(es, fmt.Errorf("%s: %s", k, err))
+	}
+	return
+}
+
+// maskCIDR returns the block of a CIDR, e.g. 10.0.0.0/24 for 10.0.0.1/24, and the CIDR
+// as it is when it can't be parsed
+func maskCIDR(cidr string) string {
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return cidr
+	}
+	return prefix.Masked().String()
+}
+
+// suppressEquivalentCIDR is a DiffSuppressFunc for the CIDRs that are sent masked, the API
+// returns the block of 10.0.0.1/24 as 10.0.0.0/24
+func suppressEquivalentCIDR(_, old, new string, _ *schema.ResourceData) bool {
+	return old != "" && new != "" && maskCIDR(old) == maskCIDR(new)
+}
+
+// validateVLANConfig checks the VLAN arguments that are set are consistent: the gateway and
+// the allocation pool are usable hosts of the CIDR, and the pool doesn't contain the gateway.
+// Empty arguments are left to the API
+func validateVLANConfig(cidr, gateway, poolStart, poolEnd string) error {
+	var prefix netip.Prefix
+	if cidr != "" {
+		var err error
+		if prefix, err = parsePrivateCIDR(cidr); err != nil {
+			return fmt.Errorf("vlan_cidr_v4: %s", err)
+		}
+	}
+
+	hosts := map[string]string{
+		"vlan_gateway_ip_v4":            gateway,
+		"vlan_allocation_pool_v4_start": poolStart,
+		"vlan_allocation_pool_v4_end":   poolEnd,
+	}
+	addrs := map[string]netip.Addr{}
+	for _, k := range []string{"vlan_gateway_ip_v4", "vlan_allocation_pool_v4_start", "vlan_allocation_pool_v4_end"} {
+		if hosts[k] == "" {
+			continue
+		}
+		addr, err := netip.ParseAddr(hosts[k])
+		if err != nil || !addr.Is4() {
+			return fmt.Errorf("%s: %q is not a valid IPv4 address", k, hosts[k])
+		}
+		if prefix.IsValid() && !prefix.Contains(addr) {
+			return fmt.Errorf("%s: %s is not inside vlan_cidr_v4 %s", k, addr, prefix)
+		}
+		if prefix.IsValid() && prefix.Bits() < 31 && (addr == prefix.Addr() || addr == lastAddr(prefix)) {
+			return fmt.Errorf("%s: %s is the network or broadcast address of %s", k, addr, prefix)
+		}
+		addrs[k] = addr
+	}
+
+	start, hasStart := addrs["vlan_allocation_pool_v4_start"]
+	end, hasEnd := addrs["vlan_allocation_pool_v4_end"]
+	if !hasStart || !hasEnd {
+		return nil
+	}
+	if end.Less(start) {
+		return fmt.Errorf("the allocation pool ends at %s, before it starts at %s", end, start)
+	}
+	if gateway, ok := addrs["vlan_gateway_ip_v4"]; ok && !gateway.Less(start) && !end.Less(gateway) {
+		return fmt.Errorf("the gateway %s is inside the allocation pool %s - %s", gateway, start, end)
+	}
+	return nil
+}
+
+// overlappingNetworks returns the networks whose CIDR overlaps prefix, except the network
+// with the ID id
+func overlappingNetworks(prefix netip.Prefix, networks []civogo.Network, id string) []civogo.Network {
+	var result []civogo.Network
+	for _, n := range networks {
+		if n.ID == id {
+			continue
+		}
+		existing, err := netip.ParsePrefix(n.CIDR)
+		if err != nil {
+			continue
+		}
+		if existing.Overlaps(prefix) {
+			result = append(result, n)
+		}
+	}
+	return result
+}
+
+// describeNetworks lists the networks as label (CIDR) for the messages
+func describeNetworks(networks []civogo.Network) string {
+	names := make([]string, 0, len(networks))
+	for _, n := range networks {
+		names = append(names, fmt.Sprintf("%s (%s)", n.Label, n.CIDR))
+	}
+	return strings.Join(names, ", ")
+}
+
+// allocateCIDRs hands out a block of prefixLength bits inside base for each label. A label
+// keeps the CIDR of the existing network with that label when it's a block of base, so the
+// allocation is stable once the networks are created; the other labels get the lowest free
+// blocks, in the order of the labels, that overlap neither the networks nor exclude
+func allocateCIDRs(base netip.Prefix, prefixLength int, labels []string, networks []civogo.Network, exclude []netip.Prefix) (map[string]netip.Prefix, error) {
+	if prefixLength < base.Bits() || prefixLength > 32 {
+		return nil, fmt.Errorf("the prefix length %d doesn't fit in %s", prefixLength, base)
+	}
+
+	result := map[string]netip.Prefix{}
+	used := append([]netip.Prefix{}, exclude...)
+	for _, n := range networks {
+		existing, err := netip.ParsePrefix(n.CIDR)
+		if err != nil {
+			continue
+		}
+		if _, ok := result[n.Label]; !ok && containsString(labels, n.Label) && existing.Bits() == prefixLength && base.Contains(existing.Addr()) {
+			result[n.Label] = existing
+		}
+		used = append(used, existing)
+	}
+
+	size := uint64(1) << (32 - prefixLength)
+	next := addrToUint(base.Addr())
+	last := addrToUint(lastAddr(base))
+
+	for _, label := range labels {
+		if _, ok := result[label]; ok {
+			continue
+		}
+		for {
+			if next+size-1 > last {
+				return nil, fmt.Errorf("%s has no free /%d block left for %s", base, prefixLength, label)
+			}
+			candidate := netip.PrefixFrom(uintToAddr(next), prefixLength)
+			var blocked *netip.Prefix
+			for i := range used {
+				if used[i].Overlaps(candidate) {
+					blocked = &used[i]
+					break
+				}
+			}
+			if blocked == nil {
+				result[label] = candidate
+				used = append(used, candidate)
+				next += size
+				break
+			}
+			// jump to the first block after the used one instead of trying every block
+			end := addrToUint(lastAddr(blocked.Masked())) + 1
+			if end <= next {
+				end = next + 1
+			}
+			next = (end + size - 1) / size * size
+		}
+	}
+	return result, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// lastAddr returns the last address of an IPv4 block
+func lastAddr(prefix netip.Prefix) netip.Addr {
+	return uintToAddr(addrToUint(prefix.Masked().Addr()) | (uint64(1)<<(32-prefix.Bits()) - 1))
+}
+
+func addrToUint(addr netip.Addr) uint64 {
+	b := addr.As4()
+	return uint64(b[0])<<24 | uint64(b[1])<<16 | uint64(b[2])<<8 | uint64(b[3])
+}
+
+func uintToAddr(v uint64) netip.Addr {
+	return netip.AddrFrom4([4]byte{byte(v >> 24), byte(v >> 16), byte(v >> 8), byte(v)})
+}
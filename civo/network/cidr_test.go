@@ -0,0 +1,163 @@
+package network
+
+import (
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/civo/civogo"
+)
+
+func TestParsePrivateCIDR(t *testing.T) {
+	cases := []struct {
+		cidr    string
+		want    string
+		wantErr string
+	}{
+		{cidr: "10.0.0.0/24", want: "10.0.0.0/24"},
+		{cidr: "172.16.0.0/12", want: "172.16.0.0/12"},
+		{cidr: "192.168.100.0/22", want: "192.168.100.0/22"},
+		// an address inside the block is masked
+		{cidr: "10.0.0.1/24", want: "10.0.0.0/24"},
+		{cidr: "192.168.101.7/22", want: "192.168.100.0/22"},
+		{cidr: "172.32.0.0/16", wantErr: "not inside a private range"},
+		{cidr: "8.8.8.0/24", wantErr: "not inside a private range"},
+		{cidr: "10.0.0.0/7", wantErr: "not inside a private range"},
+		{cidr: "fd00::/64", wantErr: "not a valid IPv4 CIDR"},
+		{cidr: "10.0.0.0", wantErr: "not a valid IPv4 CIDR"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.cidr, func(t *testing.T) {
+			got, err := parsePrivateCIDR(tc.cidr)
+			if tc.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tc.wantErr == "" && got.String() != tc.want {
+				t.Errorf("expected %s, got %s", tc.want, got)
+			}
+			if tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)) {
+				t.Fatalf("expected an error containing %q, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateVLANConfig(t *testing.T) {
+	cases := []struct {
+		name                              string
+		cidr, gateway, poolStart, poolEnd string
+		wantErr                           string
+	}{
+		{name: "valid", cidr: "192.168.10.0/24", gateway: "192.168.10.1", poolStart: "192.168.10.10", poolEnd: "192.168.10.200"},
+		{name: "gateway outside", cidr: "192.168.10.0/24", gateway: "192.168.11.1", poolStart: "192.168.10.10", poolEnd: "192.168.10.200", wantErr: "vlan_gateway_ip_v4: 192.168.11.1 is not inside"},
+		{name: "pool outside", cidr: "192.168.10.0/24", gateway: "192.168.10.1", poolStart: "192.168.10.10", poolEnd: "192.168.11.20", wantErr: "vlan_allocation_pool_v4_end"},
+		{name: "broadcast", cidr: "192.168.10.0/24", gateway: "192.168.10.1", poolStart: "192.168.10.10", poolEnd: "192.168.10.255", wantErr: "broadcast"},
+		{name: "pool reversed", cidr: "192.168.10.0/24", gateway: "192.168.10.1", poolStart: "192.168.10.200", poolEnd: "192.168.10.10", wantErr: "before it starts"},
+		{name: "gateway in pool", cidr: "192.168.10.0/24", gateway: "192.168.10.50", poolStart: "192.168.10.10", poolEnd: "192.168.10.200", wantErr: "inside the allocation pool"},
+		{name: "invalid address", cidr: "192.168.10.0/24", gateway: "gateway", poolStart: "192.168.10.10", poolEnd: "192.168.10.200", wantErr: "not a valid IPv4 address"},
+		{name: "address inside the block", cidr: "192.168.10.1/24", gateway: "192.168.10.1", poolStart: "192.168.10.10", poolEnd: "192.168.10.200"},
+		{name: "without gateway", cidr: "192.168.10.0/24", poolStart: "192.168.10.10", poolEnd: "192.168.10.200"},
+		{name: "without pool", cidr: "192.168.10.0/24", gateway: "192.168.10.1"},
+		{name: "without pool end", cidr: "192.168.10.0/24", gateway: "192.168.10.50", poolStart: "192.168.10.10"},
+		{name: "without cidr", gateway: "192.168.10.1", poolStart: "192.168.10.200", poolEnd: "192.168.10.10", wantErr: "before it starts"},
+		{name: "only cidr", cidr: "192.168.10.0/24"},
+		{name: "nothing"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateVLANConfig(tc.cidr, tc.gateway, tc.poolStart, tc.poolEnd)
+			if tc.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)) {
+				t.Fatalf("expected an error containing %q, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSuppressEquivalentCIDR(t *testing.T) {
+	cases := []struct {
+		old, new string
+		want     bool
+	}{
+		{old: "10.0.0.0/24", new: "10.0.0.1/24", want: true},
+		{old: "10.0.0.0/24", new: "10.0.0.0/24", want: true},
+		{old: "10.0.0.0/24", new: "10.0.1.0/24"},
+		{old: "10.0.0.0/24", new: "10.0.0.1/25"},
+		{old: "", new: "10.0.0.0/24"},
+		{old: "10.0.0.0/24", new: ""},
+	}
+
+	for _, tc := range cases {
+		if got := suppressEquivalentCIDR("cidr_v4", tc.old, tc.new, nil); got != tc.want {
+			t.Errorf("expected %t for %s to %s, got %t", tc.want, tc.old, tc.new, got)
+		}
+	}
+}
+
+func TestOverlappingNetworks(t *testing.T) {
+	networks := []civogo.Network{
+		{ID: "default", Label: "Default", CIDR: "192.168.1.0/24"},
+		{ID: "large", Label: "large", CIDR: "10.0.0.0/16"},
+		{ID: "ipv6", Label: "ipv6", CIDR: "fd00::/64"},
+		{ID: "none", Label: "none"},
+	}
+
+	found := overlappingNetworks(netip.MustParsePrefix("10.0.4.0/24"), networks, "")
+	if len(found) != 1 || found[0].ID != "large" {
+		t.Errorf("expected the large network, got %v", found)
+	}
+	if found := overlappingNetworks(netip.MustParsePrefix("192.168.0.0/16"), networks, "default"); len(found) != 0 {
+		t.Errorf("expected the network itself to be skipped, got %v", found)
+	}
+	if found := overlappingNetworks(netip.MustParsePrefix("10.1.0.0/24"), networks, ""); len(found) != 0 {
+		t.Errorf("expected no overlap, got %v", found)
+	}
+}
+
+func TestAllocateCIDRs(t *testing.T) {
+	networks := []civogo.Network{
+		{ID: "default", Label: "Default", CIDR: "192.168.1.0/24"},
+		{ID: "prod", Label: "prod", CIDR: "10.0.1.0/24"},
+		{ID: "legacy", Label: "legacy", CIDR: "10.0.2.0/23"},
+	}
+	base := netip.MustParsePrefix("10.0.0.0/16")
+
+	got, err := allocateCIDRs(base, 24, []string{"staging", "prod", "dev", "test"}, networks, []netip.Prefix{netip.MustParsePrefix("10.0.4.0/22")})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := map[string]string{
+		// keeps the block of the existing network
+		"prod": "10.0.1.0/24",
+		// the lowest free block
+		"staging": "10.0.0.0/24",
+		// after the /23 of legacy and the excluded /22
+		"dev":  "10.0.8.0/24",
+		"test": "10.0.9.0/24",
+	}
+	for label, cidr := range expected {
+		if got[label].String() != cidr {
+			t.Errorf("expected %s for %s, got %s", cidr, label, got[label])
+		}
+	}
+
+	// a label whose network doesn't match the prefix length gets a new block
+	got, err = allocateCIDRs(base, 24, []string{"legacy"}, networks, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got["legacy"].String() != "10.0.0.0/24" {
+		t.Errorf("expected 10.0.0.0/24 for legacy, got %s", got["legacy"])
+	}
+
+	if _, err := allocateCIDRs(netip.MustParsePrefix("10.0.0.0/23"), 24, []string{"a", "b"}, networks, nil); err == nil || !strings.Contains(err.Error(), "no free /24 block left for b") {
+		t.Errorf("expected the range to run out, got: %v", err)
+	}
+	if _, err := allocateCIDRs(base, 8, []string{"a"}, nil, nil); err == nil {
+		t.Error("expected an error for a prefix length shorter than the base")
+	}
+}
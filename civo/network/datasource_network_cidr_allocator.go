@@ -0,0 +1,140 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/netip"
+
+	"github.com/civo/civogo"
+	"github.com/civo/terraform-provider-civo/internal/utils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// DataSourceNetworkCIDRAllocator function returns a schema.Resource that hands out free blocks
+// for new networks, one per label, that don't overlap the networks of the region
+func DataSourceNetworkCIDRAllocator() *schema.Resource {
+	return &schema.Resource{
+		Description: "Hands out free CIDR blocks of a prefix length for new networks, one per label, that don't overlap the networks of the region. A label keeps the block of the existing network with that label, so the blocks don't move once the networks are created.",
+		ReadContext: dataSourceNetworkCIDRAllocatorRead,
+		Schema: map[string]*schema.Schema{
+			"labels": {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.NoZeroValues,
+				},
+				Description: "The labels of the networks to hand out a block to, the same as the `label` of the `civo_network` resources",
+			},
+			"prefix_length": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IntBetween(8, 29),
+				Description:  "The prefix length of the blocks, e.g. `24` for a /24",
+			},
+			"base_cidr": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "10.0.0.0/8",
+				ValidateFunc: validatePrivateCIDR,
+				Description:  "The private range to hand out the blocks from",
+			},
+			"exclude": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.IsCIDR,
+				},
+				Description: "CIDRs the blocks can't overlap besides the networks of the region, e.g. the ranges of an on-premise network",
+			},
+			"region": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The region of the networks",
+			},
+			// Computed resource
+			"cidrs": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "The block of each label",
+			},
+			"used_cidrs": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "The CIDRs of the networks in the region",
+			},
+		},
+	}
+}
+
+func dataSourceNetworkCIDRAllocatorRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*civogo.Client)
+
+	// overwrite the region if is define in the datasource
+	if region, ok := d.GetOk("region"); ok {
+		apiClient = utils.RegionalClient(apiClient, region.(string))
+	}
+
+	base := netip.MustParsePrefix(d.Get("base_cidr").(string)).Masked()
+	prefixLength := d.Get("prefix_length").(int)
+
+	labels := []string{}
+	seen := map[string]bool{}
+	for _, label := range d.Get("labels").([]interface{}) {
+		if seen[label.(string)] {
+			return diag.Errorf("[ERR] the label %s is listed more than once", label)
+		}
+		seen[label.(string)] = true
+		labels = append(labels, label.(string))
+	}
+
+	exclude := []netip.Prefix{}
+	for _, cidr := range d.Get("exclude").([]interface{}) {
+		prefix, err := netip.ParsePrefix(cidr.(string))
+		if err != nil {
+			return diag.Errorf("[ERR] invalid CIDR %s in exclude: %s", cidr, err)
+		}
+		exclude = append(exclude, prefix)
+	}
+
+	log.Printf("[INFO] listing the networks to hand out /%d blocks of %s", prefixLength, base)
+	networks, err := apiClient.ListNetworks()
+	if err != nil {
+		return diag.Errorf("[ERR] failed to list the networks: %s", err)
+	}
+
+	allocated, err := allocateCIDRs(base, prefixLength, labels, networks, exclude)
+	if err != nil {
+		return diag.Errorf("[ERR] %s", err)
+	}
+
+	cidrs := map[string]string{}
+	for label, prefix := range allocated {
+		cidrs[label] = prefix.String()
+	}
+	used := []string{}
+	for _, n := range networks {
+		if n.CIDR != "" {
+			used = append(used, n.CIDR)
+		}
+	}
+
+	d.SetId(fmt.Sprintf("%s-%s-%d", apiClient.Region, base, prefixLength))
+	d.Set("region", apiClient.Region)
+	d.Set("cidrs", cidrs)
+	d.Set("used_cidrs", used)
+
+	return nil
+}
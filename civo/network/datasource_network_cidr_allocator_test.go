@@ -0,0 +1,55 @@
+package network_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/civo/terraform-provider-civo/civo/acceptance"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+// TestAccDataSourceCivoNetworkCIDRAllocator_basic hands out a block to a network and checks it
+// keeps it once the network exists
+func TestAccDataSourceCivoNetworkCIDRAllocator_basic(t *testing.T) {
+	datasourceName := "data.civo_network_cidr_allocator.foobar"
+	name := acctest.RandomWithPrefix("net-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { acceptance.TestAccPreCheck(t) },
+		Providers:    acceptance.TestAccProviders,
+		CheckDestroy: CivoNetworkDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: DataSourceCivoNetworkCIDRAllocatorConfig(name),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(datasourceName, "cidrs.%", "2"),
+					resource.TestCheckResourceAttrPair(datasourceName, fmt.Sprintf("cidrs.%s", name), "civo_network.foobar", "cidr_v4"),
+					resource.TestCheckResourceAttrSet(datasourceName, "cidrs.spare"),
+				),
+			},
+			{
+				// the network keeps its block, the plan is empty
+				Config:   DataSourceCivoNetworkCIDRAllocatorConfig(name),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func DataSourceCivoNetworkCIDRAllocatorConfig(name string) string {
+	return fmt.Sprintf(`
+data "civo_network_cidr_allocator" "foobar" {
+	labels = ["%[1]s", "spare"]
+	prefix_length = 24
+	base_cidr = "172.16.0.0/12"
+	region = "LON1"
+}
+
+resource "civo_network" "foobar" {
+	label = "%[1]s"
+	region = "LON1"
+	cidr_v4 = data.civo_network_cidr_allocator.foobar.cidrs["%[1]s"]
+}
+`, name)
+}
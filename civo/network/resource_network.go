@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net/netip"
 	"time"
 
 	"github.com/civo/civogo"
@@ -35,10 +36,18 @@ func ResourceNetwork() *schema.Resource {
 				DiffSuppressFunc: utils.IgnoreCaseDiff,
 			},
 			"cidr_v4": {
-				Type:        schema.TypeString,
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateFunc:     validatePrivateCIDR,
+				DiffSuppressFunc: suppressEquivalentCIDR,
+				Description:      "The CIDR block for the network, inside 10.0.0.0/8, 172.16.0.0/12 or 192.168.0.0/16, e.g. `10.0.0.0/24`. An address inside the block, e.g. `10.0.0.1/24`, is sent as its block. It can't overlap the other networks of the region unless `allow_cidr_overlap` is set, see `civo_network_cidr_allocator` to hand out free blocks",
+			},
+			"allow_cidr_overlap": {
+				Type:        schema.TypeBool,
 				Optional:    true,
-				Computed:    true,
-				Description: "The CIDR block for the network",
+				Default:     false,
+				Description: "Whether `cidr_v4` and `vlan_cidr_v4` may overlap the CIDR of another network in the region. The overlap is then a warning instead of an error",
 			},
 			"nameservers_v4": {
 				Type:     schema.TypeList,
@@ -67,14 +76,16 @@ func ResourceNetwork() *schema.Resource {
 				Description: "VLAN ID for the network",
 			},
 			"vlan_cidr_v4": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "CIDR for VLAN IPv4",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateFunc:     validatePrivateCIDR,
+				DiffSuppressFunc: suppressEquivalentCIDR,
+				Description:      "CIDR for VLAN IPv4, inside a private range. An address inside the block is sent as its block",
 			},
 			"vlan_gateway_ip_v4": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Description: "Gateway IP for VLAN IPv4",
+				Description: "Gateway IP for VLAN IPv4, inside `vlan_cidr_v4` and outside the allocation pool",
 			},
 			"vlan_physical_interface": {
 				Type:        schema.TypeString,
@@ -84,12 +95,12 @@ func ResourceNetwork() *schema.Resource {
 			"vlan_allocation_pool_v4_start": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Description: "Start of the IPv4 allocation pool for VLAN",
+				Description: "Start of the IPv4 allocation pool for VLAN, inside `vlan_cidr_v4`",
 			},
 			"vlan_allocation_pool_v4_end": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Description: "End of the IPv4 allocation pool for VLAN",
+				Description: "End of the IPv4 allocation pool for VLAN, inside `vlan_cidr_v4` and not before its start",
 			},
 		},
 		CreateContext: resourceNetworkCreate,
@@ -121,7 +132,7 @@ func resourceNetworkCreate(ctx context.Context, d *schema.ResourceData, m interf
 	vlanConfig := civogo.VLANConnectConfig{
 		VlanID:                d.Get("vlan_id").(int),
 		PhysicalInterface:     d.Get("vlan_physical_interface").(string),
-		CIDRv4:                d.Get("vlan_cidr_v4").(string),
+		CIDRv4:                maskCIDR(d.Get("vlan_cidr_v4").(string)),
 		GatewayIPv4:           d.Get("vlan_gateway_ip_v4").(string),
 		AllocationPoolV4Start: d.Get("vlan_allocation_pool_v4_start").(string),
 		AllocationPoolV4End:   d.Get("vlan_allocation_pool_v4_end").(string),
@@ -129,7 +140,7 @@ func resourceNetworkCreate(ctx context.Context, d *schema.ResourceData, m interf
 
 	configs := civogo.NetworkConfig{
 		Label:         d.Get("label").(string),
-		CIDRv4:        d.Get("cidr_v4").(string),
+		CIDRv4:        maskCIDR(d.Get("cidr_v4").(string)),
 		Region:        apiClient.Region,
 		NameserversV4: expandStringList(d.Get("nameservers_v4")),
 	}
@@ -155,7 +166,12 @@ func resourceNetworkCreate(ctx context.Context, d *schema.ResourceData, m interf
 	if err != nil {
 		return diag.Errorf("[ERR] failed to create a new firewall for the network %s: %s", d.Get("label").(string), err)
 	}
-	return resourceNetworkRead(ctx, d, m)
+
+	diags := resourceNetworkRead(ctx, d, m)
+	if d.Get("allow_cidr_overlap").(bool) {
+		diags = append(diags, networkOverlapWarnings(apiClient, d)...)
+	}
+	return diags
 }
 
 // function to read a network
@@ -292,12 +308,112 @@ func expandStringList(input interface{}) []string {
 }
 
 func customizeDiffNetwork(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
-	if d.Id() != "" && d.HasChange("cidr_v4") {
+	// the diff isn't suppressed yet, an address inside the block isn't a change
+	if oldCIDR, newCIDR := d.GetChange("cidr_v4"); d.Id() != "" && maskCIDR(oldCIDR.(string)) != maskCIDR(newCIDR.(string)) {
 		return fmt.Errorf("the 'cidr_v4' field is immutable")
 	}
+
+	if err := checkVLANConfig(d); err != nil {
+		return err
+	}
+
+	// only a new network is checked against the others, the existing ones are what they are
+	if apiClient, ok := meta.(*civogo.Client); ok && d.Id() == "" && !d.Get("allow_cidr_overlap").(bool) {
+		if region, ok := d.GetOk("region"); ok {
+			apiClient = utils.RegionalClient(apiClient, region.(string))
+		}
+
+		overlaps, err := newNetworkOverlaps(apiClient, d)
+		if err != nil {
+			return err
+		}
+		for _, k := range []string{"cidr_v4", "vlan_cidr_v4"} {
+			if networks, ok := overlaps[k]; ok {
+				return fmt.Errorf("%s %s overlaps the networks %s in the region, pick a free block with civo_network_cidr_allocator or set allow_cidr_overlap", k, d.Get(k), describeNetworks(networks))
+			}
+		}
+	}
 	return nil
 }
 
+// checkVLANConfig function to check the VLAN arguments are only set with vlan_id and that the
+// ones that are set are consistent, the values that are only known at apply time and the
+// arguments that aren't set are left to the API
+func checkVLANConfig(d *schema.ResourceDiff) error {
+	keys := []string{"vlan_cidr_v4", "vlan_gateway_ip_v4", "vlan_allocation_pool_v4_start", "vlan_allocation_pool_v4_end"}
+	values := []string{}
+	for _, k := range keys {
+		if !d.NewValueKnown(k) {
+			return nil
+		}
+		values = append(values, d.Get(k).(string))
+	}
+
+	if !d.NewValueKnown("vlan_id") || d.Get("vlan_id").(int) == 0 {
+		for i, k := range keys {
+			if values[i] != "" {
+				return fmt.Errorf("%s is only used with vlan_id", k)
+			}
+		}
+		return nil
+	}
+
+	return validateVLANConfig(values[0], values[1], values[2], values[3])
+}
+
+// newNetworkOverlaps function to find the networks of the region that overlap cidr_v4 and
+// vlan_cidr_v4 of the network, by argument
+func newNetworkOverlaps(apiClient *civogo.Client, d *schema.ResourceDiff) (map[string][]civogo.Network, error) {
+	cidrs := map[string]netip.Prefix{}
+	for _, k := range []string{"cidr_v4", "vlan_cidr_v4"} {
+		if prefix, err := parsePrivateCIDR(d.Get(k).(string)); err == nil {
+			cidrs[k] = prefix
+		}
+	}
+	if len(cidrs) == 0 {
+		return nil, nil
+	}
+
+	networks, err := apiClient.ListNetworks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the networks: %w", err)
+	}
+
+	overlaps := map[string][]civogo.Network{}
+	for k, prefix := range cidrs {
+		if found := overlappingNetworks(prefix, networks, ""); len(found) > 0 {
+			overlaps[k] = found
+		}
+	}
+	return overlaps, nil
+}
+
+// networkOverlapWarnings function to warn about the networks the new network overlaps when
+// allow_cidr_overlap is set
+func networkOverlapWarnings(apiClient *civogo.Client, d *schema.ResourceData) diag.Diagnostics {
+	networks, err := apiClient.ListNetworks()
+	if err != nil {
+		log.Printf("[WARN] failed to list the networks to check the overlaps of %s: %s", d.Id(), err)
+		return nil
+	}
+
+	var diags diag.Diagnostics
+	for _, k := range []string{"cidr_v4", "vlan_cidr_v4"} {
+		prefix, err := netip.ParsePrefix(d.Get(k).(string))
+		if err != nil {
+			continue
+		}
+		if found := overlappingNetworks(prefix, networks, d.Id()); len(found) > 0 {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("The network %s overlaps other networks", d.Get("label")),
+				Detail:   fmt.Sprintf("%s %s overlaps %s in the region", k, prefix, describeNetworks(found)),
+			})
+		}
+	}
+	return diags
+}
+
 // createDefaultFirewall function to create a default firewall
 func createDefaultFirewall(apiClient *civogo.Client, networkID string, networkName string) error {
 
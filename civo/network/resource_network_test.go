@@ -2,6 +2,7 @@ package network_test
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/civo/civogo"
@@ -75,6 +76,37 @@ func TestAccCivoNetwork_update(t *testing.T) {
 	})
 }
 
+// TestAccCivoNetwork_overlap checks a network can't overlap another network of the region
+// unless allow_cidr_overlap is set
+func TestAccCivoNetwork_overlap(t *testing.T) {
+	var network civogo.Network
+
+	resName := "civo_network.second"
+	var networkLabel = acctest.RandomWithPrefix("tf-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { acceptance.TestAccPreCheck(t) },
+		Providers:    acceptance.TestAccProviders,
+		CheckDestroy: CivoNetworkDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: CivoNetworkConfigBasic(networkLabel) + CivoNetworkConfigCIDR(networkLabel, "172.20.0.0/24"),
+			},
+			{
+				Config:      CivoNetworkConfigBasic(networkLabel) + CivoNetworkConfigCIDR(networkLabel, "172.20.0.0/24") + CivoNetworkConfigOverlap(networkLabel, false),
+				ExpectError: regexp.MustCompile(`cidr_v4 172.20.0.0/23 overlaps the networks`),
+			},
+			{
+				Config: CivoNetworkConfigBasic(networkLabel) + CivoNetworkConfigCIDR(networkLabel, "172.20.0.0/24") + CivoNetworkConfigOverlap(networkLabel, true),
+				Check: resource.ComposeTestCheckFunc(
+					CivoNetworkResourceExists(resName, &network),
+					resource.TestCheckResourceAttr(resName, "cidr_v4", "172.20.0.0/23"),
+				),
+			},
+		},
+	})
+}
+
 func CivoNetworkValues(network *civogo.Network, name string) resource.TestCheckFunc {
 	return func(_ *terraform.State) error {
 		if network.Label != name {
@@ -141,6 +173,24 @@ resource "civo_network" "foobar" {
 }`, label)
 }
 
+func CivoNetworkConfigCIDR(label, cidr string) string {
+	return fmt.Sprintf(`
+resource "civo_network" "first" {
+	label = "%s-first"
+	cidr_v4 = "%s"
+}`, label, cidr)
+}
+
+func CivoNetworkConfigOverlap(label string, allow bool) string {
+	return fmt.Sprintf(`
+resource "civo_network" "second" {
+	label = "%s-second"
+	cidr_v4 = "172.20.0.0/23"
+	allow_cidr_overlap = %t
+	depends_on = [civo_network.first]
+}`, label, allow)
+}
+
 func CivoNetworkConfigUpdates(label string) string {
 	return fmt.Sprintf(`
 resource "civo_network" "foobar" {
@@ -0,0 +1,117 @@
+package network
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/civo/civogo"
+	"github.com/civo/terraform-provider-civo/internal/mockapi"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// TestCustomizeDiffNetwork plans new networks against the fake API, which has the default
+// network 192.168.1.0/24 in every region
+func TestCustomizeDiffNetwork(t *testing.T) {
+	server := mockapi.NewServer()
+	defer server.Close()
+	client, err := server.Client(mockapi.DefaultRegion)
+	if err != nil {
+		t.Fatalf("failed to build the client: %s", err)
+	}
+	if _, err := client.CreateNetwork(civogo.NetworkConfig{Label: "existing", CIDRv4: "10.10.0.0/16", Region: mockapi.DefaultRegion}); err != nil {
+		t.Fatalf("failed to create the network: %s", err)
+	}
+
+	vlan := map[string]interface{}{
+		"label":                         "vlan",
+		"vlan_id":                       100,
+		"vlan_cidr_v4":                  "172.16.10.0/24",
+		"vlan_gateway_ip_v4":            "172.16.10.1",
+		"vlan_allocation_pool_v4_start": "172.16.10.10",
+		"vlan_allocation_pool_v4_end":   "172.16.10.100",
+	}
+	// withVLAN returns the VLAN config with the changes, a nil value removes the argument
+	withVLAN := func(changes map[string]interface{}) map[string]interface{} {
+		config := map[string]interface{}{}
+		for k, v := range vlan {
+			config[k] = v
+		}
+		for k, v := range changes {
+			if v == nil {
+				delete(config, k)
+			} else {
+				config[k] = v
+			}
+		}
+		return config
+	}
+
+	cases := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr string
+	}{
+		{name: "free", config: map[string]interface{}{"label": "free", "cidr_v4": "10.20.0.0/24"}},
+		{name: "no cidr", config: map[string]interface{}{"label": "auto"}},
+		{name: "overlap", config: map[string]interface{}{"label": "overlap", "cidr_v4": "10.10.4.0/24"}, wantErr: "cidr_v4 10.10.4.0/24 overlaps the networks existing (10.10.0.0/16)"},
+		{name: "overlap default", config: map[string]interface{}{"label": "overlap", "cidr_v4": "192.168.0.0/16"}, wantErr: "Default (192.168.1.0/24)"},
+		{name: "overlap allowed", config: map[string]interface{}{"label": "overlap", "cidr_v4": "10.10.4.0/24", "allow_cidr_overlap": true}},
+		{name: "vlan", config: vlan},
+		{name: "vlan overlap", config: withVLAN(map[string]interface{}{
+			"vlan_cidr_v4":                  "10.10.1.0/24",
+			"vlan_gateway_ip_v4":            "10.10.1.1",
+			"vlan_allocation_pool_v4_start": "10.10.1.10",
+			"vlan_allocation_pool_v4_end":   "10.10.1.100",
+		}), wantErr: "vlan_cidr_v4 10.10.1.0/24 overlaps the networks existing"},
+		{name: "vlan gateway outside", config: withVLAN(map[string]interface{}{"vlan_gateway_ip_v4": "172.16.11.1"}), wantErr: "vlan_gateway_ip_v4: 172.16.11.1 is not inside"},
+		{name: "address inside the block", config: map[string]interface{}{"label": "free", "cidr_v4": "10.20.0.1/24"}},
+		{name: "overlap of the block", config: map[string]interface{}{"label": "overlap", "cidr_v4": "10.10.4.1/24"}, wantErr: "overlaps the networks existing (10.10.0.0/16)"},
+		// the arguments that aren't set are left to the API
+		{name: "vlan without gateway", config: withVLAN(map[string]interface{}{"vlan_gateway_ip_v4": nil})},
+		{name: "vlan without pool", config: withVLAN(map[string]interface{}{"vlan_allocation_pool_v4_start": nil, "vlan_allocation_pool_v4_end": nil})},
+		{name: "vlan only id", config: map[string]interface{}{"label": "vlan", "vlan_id": 100}},
+		{name: "vlan without cidr", config: withVLAN(map[string]interface{}{"vlan_cidr_v4": nil, "vlan_gateway_ip_v4": "172.16.10.50"}), wantErr: "inside the allocation pool"},
+		{name: "vlan arguments without vlan_id", config: withVLAN(map[string]interface{}{"vlan_id": nil}), wantErr: "vlan_cidr_v4 is only used with vlan_id"},
+		{name: "vlan gateway in pool", config: withVLAN(map[string]interface{}{"vlan_gateway_ip_v4": "172.16.10.50"}), wantErr: "inside the allocation pool"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ResourceNetwork().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(tc.config), client)
+			if tc.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)) {
+				t.Fatalf("expected an error containing %q, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+// TestCustomizeDiffNetworkCIDR plans an existing network whose cidr_v4 is written as an
+// address inside its block
+func TestCustomizeDiffNetworkCIDR(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "b4ba0c7a-7e0e-4b8a-9a6f-1f1e1e1e1e1e",
+		Attributes: map[string]string{
+			"id":                 "b4ba0c7a-7e0e-4b8a-9a6f-1f1e1e1e1e1e",
+			"label":              "existing",
+			"cidr_v4":            "10.0.0.0/24",
+			"allow_cidr_overlap": "false",
+		},
+	}
+
+	diff, err := ResourceNetwork().Diff(context.Background(), state, terraform.NewResourceConfigRaw(map[string]interface{}{"label": "existing", "cidr_v4": "10.0.0.1/24"}), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff != nil && diff.Attributes["cidr_v4"] != nil {
+		t.Errorf("expected no change of cidr_v4, got %v", diff.Attributes["cidr_v4"])
+	}
+
+	_, err = ResourceNetwork().Diff(context.Background(), state, terraform.NewResourceConfigRaw(map[string]interface{}{"label": "existing", "cidr_v4": "10.0.1.0/24"}), nil)
+	if err == nil || !strings.Contains(err.Error(), "immutable") {
+		t.Errorf("expected the cidr_v4 field to be immutable, got: %v", err)
+	}
+}
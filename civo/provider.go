@@ -1,12 +1,16 @@
 package civo
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/civo/civogo"
 	"github.com/civo/terraform-provider-civo/civo/database"
@@ -23,10 +27,13 @@ import (
 	"github.com/civo/terraform-provider-civo/civo/size"
 	"github.com/civo/terraform-provider-civo/civo/ssh"
 	"github.com/civo/terraform-provider-civo/civo/volume"
+	"github.com/civo/terraform-provider-civo/internal/transport"
 	"github.com/civo/terraform-provider-civo/internal/utils"
 	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/mitchellh/go-homedir"
 )
 
@@ -40,7 +47,7 @@ var (
 
 // Provider Civo cloud provider
 func Provider() *schema.Provider {
-	return &schema.Provider{
+	return withRegionResolver(&schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"token": {
 				Type:             schema.TypeString,
@@ -56,6 +63,12 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("CIVO_CREDENTIAL_FILE", ""),
 				Description: "Path to the Civo credentials file. Can be specified using CIVO_CREDENTIAL_FILE environment variable.",
 			},
+			"profile": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CIVO_PROFILE", ""),
+				Description: "The name of the API key to use from the `apikeys` of the credentials file or the CLI configuration, instead of the current one. Can be specified using CIVO_PROFILE environment variable.",
+			},
 			"region": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -65,8 +78,99 @@ func Provider() *schema.Provider {
 			"api_endpoint": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				DefaultFunc: schema.EnvDefaultFunc("CIVO_API_URL", ProdAPI),
-				Description: "The Base URL to use for CIVO API.",
+				DefaultFunc: schema.EnvDefaultFunc("CIVO_API_URL", ""),
+				Description: "The Base URL to use for CIVO API. Defaults to the URL of the selected profile, or https://api.civo.com.",
+			},
+			"default_tags": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Tags added to every resource that supports tags, they are shown in the `tags_all` attribute of the resources",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"tags": {
+							Type:        schema.TypeSet,
+							Optional:    true,
+							Description: "The tags to add to every resource, e.g. `cost-center:1234`",
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validation.StringDoesNotContainAny(" "),
+							},
+						},
+					},
+				},
+			},
+			"skip_credentials_validation": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CIVO_SKIP_CREDENTIALS_VALIDATION", false),
+				Description: "Don't check the token when the provider is configured, it is checked by the first request a resource or data source sends instead. Useful when the configuration doesn't use the Civo API, e.g. in CI. Can be specified using CIVO_SKIP_CREDENTIALS_VALIDATION environment variable.",
+			},
+			"max_retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("CIVO_MAX_RETRIES", transport.DefaultMaxRetries),
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "How many times a request is retried when the API throttles it (429) or fails with a transient error, `0` disables retries. Defaults to 4. Can be specified using CIVO_MAX_RETRIES environment variable.",
+			},
+			"retry_wait_min": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("CIVO_RETRY_WAIT_MIN", int(transport.DefaultRetryWaitMin/time.Second)),
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The shortest wait, in seconds, before retrying a request. Defaults to 1. Can be specified using CIVO_RETRY_WAIT_MIN environment variable.",
+			},
+			"retry_wait_max": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("CIVO_RETRY_WAIT_MAX", int(transport.DefaultRetryWaitMax/time.Second)),
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The longest wait, in seconds, before retrying a request, unless the API asks for a longer one with `Retry-After`. Defaults to 30. Can be specified using CIVO_RETRY_WAIT_MAX environment variable.",
+			},
+			"max_concurrent_requests": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("CIVO_MAX_CONCURRENT_REQUESTS", transport.DefaultMaxConcurrentRequests),
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "How many requests can run at the same time in each region, `0` removes the limit. Defaults to 10. Can be specified using CIVO_MAX_CONCURRENT_REQUESTS environment variable.",
+			},
+			"ca_cert_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CIVO_CA_CERT_FILE", ""),
+				Description: "Path to a PEM bundle of CA certificates to trust in addition to the system ones, e.g. the CA of a proxy doing TLS interception. Can be specified using CIVO_CA_CERT_FILE environment variable.",
+			},
+			"client_cert_file": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("CIVO_CLIENT_CERT_FILE", ""),
+				RequiredWith: []string{"client_key_file"},
+				Description:  "Path to a PEM client certificate presented to the API or the proxy (mTLS), `client_key_file` must be set as well. Can be specified using CIVO_CLIENT_CERT_FILE environment variable.",
+			},
+			"client_key_file": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("CIVO_CLIENT_KEY_FILE", ""),
+				RequiredWith: []string{"client_cert_file"},
+				Description:  "Path to the PEM private key of `client_cert_file`. Can be specified using CIVO_CLIENT_KEY_FILE environment variable.",
+			},
+			"http_proxy": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CIVO_HTTP_PROXY", ""),
+				Description: "The URL of the proxy used to reach the API, e.g. `http://proxy.example.com:3128`. Defaults to the `HTTPS_PROXY` and `HTTP_PROXY` environment variables. Can be specified using CIVO_HTTP_PROXY environment variable.",
+			},
+			"no_proxy": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CIVO_NO_PROXY", ""),
+				Description: "A comma-separated list of hosts, domains and CIDRs reached without the proxy. Defaults to the `NO_PROXY` environment variable. Can be specified using CIVO_NO_PROXY environment variable.",
+			},
+			"insecure_skip_verify": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("CIVO_INSECURE_SKIP_VERIFY", false),
+				Description: "Disables the verification of the API certificate. Only use it for testing, prefer `ca_cert_file` to trust a proxy. Can be specified using CIVO_INSECURE_SKIP_VERIFY environment variable.",
 			},
 		},
 		DataSourcesMap: map[string]*schema.Resource{
@@ -74,19 +178,24 @@ func Provider() *schema.Provider {
 			"civo_disk_image":              disk.DataSourceDiskImage(),
 			"civo_kubernetes_version":      kubernetes.DataSourceKubernetesVersion(),
 			"civo_kubernetes_cluster":      kubernetes.DataSourceKubernetesCluster(),
+			"civo_kubernetes_marketplace":  kubernetes.DataSourceKubernetesMarketplace(),
 			"civo_size":                    size.DataSourceSize(),
 			"civo_instances":               instances.DataSourceInstances(),
 			"civo_instance":                instances.DataSourceInstance(),
 			"civo_dns_domain_name":         dns.DataSourceDNSDomainName(),
 			"civo_dns_domain_record":       dns.DataSourceDNSDomainRecord(),
 			"civo_volume":                  volume.DataSourceVolume(),
+			"civo_volume_snapshot":         volume.DataSourceVolumeSnapshot(),
 			"civo_ssh_key":                 ssh.DataSourceSSHKey(),
 			"civo_object_store":            objectstorage.DataSourceObjectStore(),
 			"civo_object_store_credential": objectstorage.DataSourceObjectStoreCredential(),
 			"civo_region":                  region.DataSourceRegion(),
 			"civo_database":                database.DataSourceDatabase(),
 			"civo_database_version":        database.DataDatabaseVersion(),
+			"civo_database_backup":         database.DataSourceDatabaseBackup(),
+			"civo_database_connection":     database.DataSourceDatabaseConnection(),
 			"civo_network":                 network.DataSourceNetwork(),
+			"civo_network_cidr_allocator":  network.DataSourceNetworkCIDRAllocator(),
 			"civo_firewall":                firewall.DataSourceFirewall(),
 			"civo_loadbalancer":            loadbalancer.DataSourceLoadBalancer(),
 			"civo_reserved_ip":             ip.DataSourceReservedIP(),
@@ -101,55 +210,163 @@ func Provider() *schema.Provider {
 			"civo_instance":                        instances.ResourceInstance(),
 			"civo_volume":                          volume.ResourceVolume(),
 			"civo_volume_attachment":               volume.ResourceVolumeAttachment(),
+			"civo_volume_snapshot":                 volume.ResourceVolumeSnapshot(),
 			"civo_dns_domain_name":                 dns.ResourceDNSDomainName(),
 			"civo_dns_domain_record":               dns.ResourceDNSDomainRecord(),
 			"civo_ssh_key":                         ssh.ResourceSSHKey(),
 			"civo_kubernetes_cluster":              kubernetes.ResourceKubernetesCluster(),
 			"civo_kubernetes_node_pool":            kubernetes.ResourceKubernetesClusterNodePool(),
+			"civo_kubernetes_application":          kubernetes.ResourceKubernetesApplication(),
 			"civo_object_store":                    objectstorage.ResourceObjectStore(),
 			"civo_object_store_credential":         objectstorage.ResourceObjectStoreCredential(),
 			"civo_database":                        database.ResourceDatabase(),
+			"civo_database_backup":                 database.ResourceDatabaseBackup(),
+			"civo_database_user":                   database.ResourceDatabaseUser(),
+			"civo_database_schema":                 database.ResourceDatabaseSchema(),
 			"civo_network":                         network.ResourceNetwork(),
 			"civo_firewall":                        firewall.ResourceFirewall(),
+			"civo_firewall_rule":                   firewall.ResourceFirewallRule(),
 			"civo_reserved_ip":                     ip.ResourceReservedIP(),
 			"civo_instance_reserved_ip_assignment": instances.ResourceInstanceReservedIPAssignment(),
+			"civo_instance_snapshot":               instances.ResourceInstanceSnapshot(),
 			"civo_vpc_subnet":                      network.ResourceVPCSubnet(),
+			"civo_loadbalancer":                    loadbalancer.ResourceLoadBalancer(),
 			// VPC-prefixed aliases (same resources, alternative names)
 			"civo_vpc_network":                network.ResourceNetwork(),
 			"civo_vpc_firewall":               firewall.ResourceFirewall(),
 			"civo_vpc_reserved_ip":            ip.ResourceReservedIP(),
 			"civo_vpc_reserved_ip_assignment": instances.ResourceInstanceReservedIPAssignment(),
+			"civo_vpc_loadbalancer":           loadbalancer.ResourceLoadBalancer(),
 		},
-		ConfigureFunc: providerConfigure,
+		ConfigureContextFunc: providerConfigure,
+	})
+}
+
+// withRegionResolver checks the region of every resource and data source against the regions
+// listed by the API, and stores the region in the state as the API lists it
+func withRegionResolver(p *schema.Provider) *schema.Provider {
+	for _, r := range p.ResourcesMap {
+		if _, ok := r.Schema["region"]; !ok {
+			continue
+		}
+
+		if r.CustomizeDiff == nil {
+			r.CustomizeDiff = utils.CustomizeDiffRegion
+		} else {
+			r.CustomizeDiff = customdiff.Sequence(utils.CustomizeDiffRegion, r.CustomizeDiff)
+		}
+		r.CreateContext = normalizeRegionAfter(r.CreateContext)
+		r.ReadContext = normalizeRegionAfter(r.ReadContext)
+		r.UpdateContext = normalizeRegionAfter(r.UpdateContext)
+	}
+
+	for _, r := range p.DataSourcesMap {
+		if s, ok := r.Schema["region"]; !ok || !s.Optional {
+			continue
+		}
+
+		read := normalizeRegionAfter(r.ReadContext)
+		r.ReadContext = func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			if region, ok := d.GetOk("region"); ok {
+				if _, err := utils.ResolveRegion(m.(*civogo.Client), region.(string)); err != nil {
+					return diag.Diagnostics{{Severity: diag.Error, Summary: "Invalid region", Detail: err.Error(), AttributePath: cty.GetAttrPath("region")}}
+				}
+			}
+			return read(ctx, d, m)
+		}
 	}
+
+	return p
 }
 
-// Provider configuration
-func providerConfigure(d *schema.ResourceData) (interface{}, error) {
-	var regionValue, tokenValue, apiURL string
-	var client *civogo.Client
-	var err error
-	var tokenSource string
+// normalizeRegionAfter wraps a CRUD function so the region is normalized once it succeeded
+func normalizeRegionAfter(f func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	if f == nil {
+		return nil
+	}
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		diags := f(ctx, d, m)
+		if !diags.HasError() && d.Id() != "" {
+			utils.NormalizeRegion(d, m)
+		}
+		return diags
+	}
+}
 
-	if region, ok := d.GetOk("region"); ok {
-		regionValue = region.(string)
+// Provider configuration, unless skip_credentials_validation is set the token is checked
+// here, otherwise it is only checked by the first request a resource or data source sends
+func providerConfigure(_ context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+	var regionValue, apiURL string
+	skipValidation := d.Get("skip_credentials_validation").(bool)
+
+	creds, tokenSource, credsErr := getCredentials(d)
+	if credsErr != nil {
+		if !skipValidation {
+			return nil, diag.Diagnostics{{
+				Severity: diag.Error,
+				Summary:  "No Civo token found",
+				Detail:   fmt.Sprintf("No token was found in the CIVO_TOKEN environment variable, the credentials_file or ~/.civo.json: %s. Please go to https://dashboard.civo.com/security to fetch one, or set skip_credentials_validation if the configuration doesn't use the Civo API.", credsErr),
+			}}
+		}
+		log.Printf("[WARN] no Civo token found, the requests to the API will fail: %s", credsErr)
+		// the requests never reach the API, the token is only needed to build the client
+		creds, tokenSource = &credentials{token: "unconfigured"}, "no source"
 	}
 
-	if token, source, err := getToken(d); err == nil {
-		tokenValue = token.(string)
-		tokenSource = source
+	if region, ok := d.GetOk("region"); ok {
+		regionValue = region.(string)
 	} else {
-		return nil, fmt.Errorf("[ERR] No token configuration found in $CIVO_TOKEN or credentials_file or ~/.civo.json. Please go to https://dashboard.civo.com/security to fetch one: %v", err)
+		regionValue = creds.region
 	}
 
 	if apiEndpoint, ok := d.GetOk("api_endpoint"); ok {
 		apiURL = apiEndpoint.(string)
+	} else if creds.apiURL != "" {
+		apiURL = creds.apiURL
 	} else {
 		apiURL = ProdAPI
 	}
-	client, err = civogo.NewClientWithURL(tokenValue, apiURL, regionValue)
+
+	// every request goes through the shared transport, which retries throttled
+	// and failed requests and limits the concurrency per region
+	retryWaitMin := d.Get("retry_wait_min").(int)
+	retryWaitMax := d.Get("retry_wait_max").(int)
+	if retryWaitMin > retryWaitMax {
+		return nil, diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       "Invalid retry configuration",
+			Detail:        fmt.Sprintf("retry_wait_min (%d) must not be greater than retry_wait_max (%d).", retryWaitMin, retryWaitMax),
+			AttributePath: cty.GetAttrPath("retry_wait_min"),
+		}}
+	}
+	httpOptions, err := getHTTPOptions(d)
 	if err != nil {
-		return nil, err
+		return nil, diag.Diagnostics{{Severity: diag.Error, Summary: "Invalid HTTP configuration", Detail: err.Error()}}
+	}
+	baseTransport, err := transport.NewHTTPTransport(httpOptions)
+	if err != nil {
+		return nil, diag.Diagnostics{{Severity: diag.Error, Summary: "Invalid HTTP configuration", Detail: err.Error()}}
+	}
+
+	httpTransport := transport.New(baseTransport, transport.Config{
+		MaxRetries:            d.Get("max_retries").(int),
+		RetryWaitMin:          time.Duration(retryWaitMin) * time.Second,
+		RetryWaitMax:          time.Duration(retryWaitMax) * time.Second,
+		MaxConcurrentRequests: d.Get("max_concurrent_requests").(int),
+	})
+	forwarder, err := transport.NewForwarder(apiURL, &credentialsTransport{
+		next:   httpTransport,
+		source: tokenSource,
+		err:    credsErr,
+	})
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+
+	client, err := civogo.NewClientWithURL(creds.token, forwarder.URL(), regionValue)
+	if err != nil {
+		forwarder.Close()
+		return nil, diag.FromErr(err)
 	}
 
 	userAgent := &civogo.Component{
@@ -158,27 +375,106 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	}
 	client.SetUserAgent(userAgent)
 
+	utils.SetDefaultTags(client, expandDefaultTags(d.Get("default_tags").([]interface{})))
+
+	log.Printf("[DEBUG] Civo API URL: %s, token from %s\n", apiURL, tokenSource)
+
+	if skipValidation {
+		return client, nil
+	}
+
 	// Validate token by making a simple API request
-	_, err = client.ListRegions()
+	regions, err := client.ListRegions()
 	if err != nil {
+		forwarder.Close()
 
 		// Check if the error is DatabaseAccountNotFoundError
-		if errors.Is(err, civogo.DatabaseAccountNotFoundError) {
-			return nil, fmt.Errorf("the Civo token from %s is invalid. Please go to https://dashboard.civo.com/security to generate one", tokenSource)
+		if errors.Is(err, civogo.DatabaseAccountNotFoundError) || errors.Is(err, civogo.AuthenticationFailedError) {
+			return nil, diag.Diagnostics{{
+				Severity: diag.Error,
+				Summary:  "Invalid Civo token",
+				Detail:   fmt.Sprintf("The Civo token from %s was rejected by the API. Please go to https://dashboard.civo.com/security to generate one.", tokenSource),
+			}}
 		}
 
-		return nil, fmt.Errorf("an error occoured while connecting to Civo's API: %s", err)
+		return nil, diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  "Failed to connect to the Civo API",
+			Detail:   fmt.Sprintf("An error occurred while checking the token from %s against %s: %s. Set skip_credentials_validation to defer the check until a resource needs the API.", tokenSource, apiURL, err),
+		}}
+	}
+
+	// the regions are kept for the resources, and the default region is checked against them
+	utils.SetRegions(client, regions)
+	if client.Region != "" {
+		code, err := utils.ResolveRegion(client, client.Region)
+		if err != nil {
+			forwarder.Close()
+			return nil, diag.Diagnostics{{Severity: diag.Error, Summary: "Invalid region", Detail: err.Error(), AttributePath: cty.GetAttrPath("region")}}
+		}
+		client.Region = code
 	}
 
-	log.Printf("[DEBUG] Civo API URL: %s\n", apiURL)
 	return client, nil
 }
 
-func getToken(d *schema.ResourceData) (interface{}, string, error) {
+// expandDefaultTags returns the tags of the default_tags block
+func expandDefaultTags(defaultTags []interface{}) []string {
+	if len(defaultTags) == 0 || defaultTags[0] == nil {
+		return nil
+	}
+	return utils.ExpandTags(defaultTags[0].(map[string]interface{})["tags"])
+}
+
+// getHTTPOptions returns the TLS and proxy settings of the provider, with the paths expanded
+func getHTTPOptions(d *schema.ResourceData) (transport.HTTPOptions, error) {
+	options := transport.HTTPOptions{
+		HTTPProxy:          d.Get("http_proxy").(string),
+		NoProxy:            d.Get("no_proxy").(string),
+		InsecureSkipVerify: d.Get("insecure_skip_verify").(bool),
+	}
+
+	files := map[string]*string{
+		"ca_cert_file":     &options.CACertFile,
+		"client_cert_file": &options.ClientCertFile,
+		"client_key_file":  &options.ClientKeyFile,
+	}
+	for key, value := range files {
+		path := d.Get(key).(string)
+		if path == "" {
+			continue
+		}
+		expanded, err := homedir.Expand(path)
+		if err != nil {
+			return options, fmt.Errorf("[ERR] error expanding %s %v: %w", key, path, err)
+		}
+		if err := utils.CheckFileSize(expanded); err != nil {
+			return options, fmt.Errorf("[ERR] invalid %s: %s", key, err)
+		}
+		*value = expanded
+	}
+
+	return options, nil
+}
+
+// credentials holds the token to use and, when they come from a profile
+// that sets them, its default region and API URL
+type credentials struct {
+	token  string
+	region string
+	apiURL string
+}
+
+func getCredentials(d *schema.ResourceData) (*credentials, string, error) {
+	profile := d.Get("profile").(string)
 
-	// Gets you the token atrribute value or falls back to reading CIVO_TOKEN environment variable
-	if token, ok := d.GetOk("token"); ok {
-		return token, "environment variable", nil
+	// Gets you the token atrribute value or falls back to reading CIVO_TOKEN environment variable.
+	// A profile selects a key from the config files, so it takes precedence.
+	if token, ok := d.GetOk("token"); ok && profile == "" {
+		if os.Getenv("CIVO_TOKEN") == token.(string) {
+			return &credentials{token: token.(string)}, "the CIVO_TOKEN environment variable", nil
+		}
+		return &credentials{token: token.(string)}, "the token argument", nil
 	}
 
 	// Check for credentials file specified in provider config
@@ -187,9 +483,9 @@ func getToken(d *schema.ResourceData) (interface{}, string, error) {
 		if err != nil {
 			return nil, "", fmt.Errorf("error expanding %v: %w", credFile, err)
 		}
-		token, err := readTokenFromFile(path)
+		creds, err := readCredentialsFromFile(path, profile)
 		if err == nil {
-			return token, "credentials file", nil
+			return creds, credentialSource(fmt.Sprintf("the credentials file %s", path), profile), nil
 		}
 		return nil, "", fmt.Errorf("error reading from credentials_file: %v", err)
 	}
@@ -197,9 +493,9 @@ func getToken(d *schema.ResourceData) (interface{}, string, error) {
 	// Check for default CLI config file
 	homeDir, err := homedir.Dir()
 	if err == nil {
-		token, err := readTokenFromFile(filepath.Join(homeDir, ".civo.json"))
+		creds, err := readCredentialsFromFile(filepath.Join(homeDir, ".civo.json"), profile)
 		if err == nil {
-			return token, "CLI config file", nil
+			return creds, credentialSource("the CLI config file ~/.civo.json", profile), nil
 		}
 		return nil, "", fmt.Errorf("error reading from ~/.civo.json: %v", err)
 	}
@@ -208,22 +504,34 @@ func getToken(d *schema.ResourceData) (interface{}, string, error) {
 
 }
 
-func readTokenFromFile(path string) (string, error) {
+func credentialSource(source, profile string) string {
+	if profile == "" {
+		return source
+	}
+	return fmt.Sprintf("%s (profile %q)", source, profile)
+}
+
+// readCredentialsFromFile returns the credentials of the named profile, or of
+// the current API key when profile is empty. The CLI only stores one region and
+// URL, in meta, so they are only used for the current API key.
+func readCredentialsFromFile(path, profile string) (*credentials, error) {
 	// Check file size: 20 MB limit
 	if err := utils.CheckFileSize(path); err != nil {
-		return "", err
+		return nil, err
 	}
 
 	// read the file
 	data, err := os.ReadFile(path)
 	if err != nil {
-		return "", fmt.Errorf("error reading file (%s): %w", path, err)
+		return nil, fmt.Errorf("error reading file (%s): %w", path, err)
 	}
 
 	var config struct {
 		APIKeys map[string]string `json:"apikeys"`
 		Meta    struct {
 			CurrentAPIKey string `json:"current_apikey"`
+			DefaultRegion string `json:"default_region"`
+			URL           string `json:"url"`
 		} `json:"meta"`
 	}
 
@@ -238,22 +546,36 @@ func readTokenFromFile(path string) (string, error) {
 }`
 
 	if err := json.Unmarshal(data, &config); err != nil {
-		return "", fmt.Errorf("failed to parse JSON from '%s': %w. Please ensure the input JSON file is correctly formatted and all required fields are present. Expected format:\n%s", path, err, exampleJSON)
+		return nil, fmt.Errorf("failed to parse JSON from '%s': %w. Please ensure the input JSON file is correctly formatted and all required fields are present. Expected format:\n%s", path, err, exampleJSON)
 	}
 
-	if config.APIKeys == nil || config.Meta.CurrentAPIKey == "" {
-		return "", fmt.Errorf("invalid structure in '%s', missing required fields. Expected format:\n%s", path, exampleJSON)
+	if config.APIKeys == nil || (config.Meta.CurrentAPIKey == "" && profile == "") {
+		return nil, fmt.Errorf("invalid structure in '%s', missing required fields. Expected format:\n%s", path, exampleJSON)
 	}
 
-	// Get the current API key name
-	currentKeyName := config.Meta.CurrentAPIKey
+	// Use the requested profile, or the current API key
+	keyName := profile
+	if keyName == "" {
+		keyName = config.Meta.CurrentAPIKey
+	}
 
 	// Fetch the corresponding token
-	token, ok := config.APIKeys[currentKeyName]
+	token, ok := config.APIKeys[keyName]
 	if !ok {
-		return "", fmt.Errorf("API key '%s' not found in '%s'", currentKeyName, path)
+		names := make([]string, 0, len(config.APIKeys))
+		for name := range config.APIKeys {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("API key '%s' not found in '%s', available keys: %s", keyName, path, strings.Join(names, ", "))
+	}
+
+	creds := &credentials{token: token}
+	if keyName == config.Meta.CurrentAPIKey {
+		creds.region = config.Meta.DefaultRegion
+		creds.apiURL = config.Meta.URL
 	}
-	return token, nil
+	return creds, nil
 }
 
 func validateTokenUsage(v interface{}, path cty.Path) diag.Diagnostics {
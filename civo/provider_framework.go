@@ -0,0 +1,127 @@
+package civo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/civo/terraform-provider-civo/civo/kubernetes"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	fwschema "github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// ProviderServer returns the server of the provider, which serves the resources and data sources
+// of the SDK provider together with the ephemeral resources, only available in the plugin framework
+func ProviderServer(ctx context.Context) (func() tfprotov5.ProviderServer, error) {
+	sdkProvider := Provider()
+
+	muxServer, err := tf5muxserver.NewMuxServer(ctx,
+		// the SDK provider is configured first, the framework provider reuses its client
+		sdkProvider.GRPCProvider,
+		providerserver.NewProtocol5(&frameworkProvider{sdkProvider: sdkProvider}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return muxServer.ProviderServer, nil
+}
+
+// frameworkProvider is the part of the provider written with the plugin framework, it shares the
+// configuration and the API client of the SDK provider
+type frameworkProvider struct {
+	sdkProvider *schema.Provider
+}
+
+var _ provider.ProviderWithEphemeralResources = &frameworkProvider{}
+
+func (p *frameworkProvider) Metadata(_ context.Context, _ provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "civo"
+	resp.Version = ProviderVersion
+}
+
+// Schema function to return the schema of the SDK provider, both servers must have the same one
+func (p *frameworkProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp *provider.SchemaResponse) {
+	attributes, blocks, err := frameworkProviderSchema(p.sdkProvider.Schema)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid provider schema", err.Error())
+		return
+	}
+	resp.Schema = fwschema.Schema{
+		Attributes: attributes,
+		Blocks:     blocks,
+	}
+}
+
+// Configure function to pass the API client of the SDK provider, which is configured before
+func (p *frameworkProvider) Configure(_ context.Context, _ provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	resp.EphemeralResourceData = p.sdkProvider.Meta()
+}
+
+func (p *frameworkProvider) EphemeralResources(_ context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		kubernetes.NewEphemeralKubernetesClusterKubeconfig,
+	}
+}
+
+func (p *frameworkProvider) DataSources(_ context.Context) []func() datasource.DataSource {
+	return nil
+}
+
+func (p *frameworkProvider) Resources(_ context.Context) []func() resource.Resource {
+	return nil
+}
+
+// frameworkProviderSchema function to convert the schema of the SDK provider, the validation and
+// the defaults of the arguments are left to the SDK provider
+func frameworkProviderSchema(s map[string]*schema.Schema) (map[string]fwschema.Attribute, map[string]fwschema.Block, error) {
+	attributes := map[string]fwschema.Attribute{}
+	blocks := map[string]fwschema.Block{}
+
+	for name, attr := range s {
+		switch attr.Type {
+		case schema.TypeString:
+			attributes[name] = fwschema.StringAttribute{Optional: attr.Optional, Required: attr.Required, Sensitive: attr.Sensitive, Description: attr.Description, DeprecationMessage: attr.Deprecated}
+		case schema.TypeBool:
+			attributes[name] = fwschema.BoolAttribute{Optional: attr.Optional, Required: attr.Required, Sensitive: attr.Sensitive, Description: attr.Description, DeprecationMessage: attr.Deprecated}
+		case schema.TypeInt:
+			attributes[name] = fwschema.Int64Attribute{Optional: attr.Optional, Required: attr.Required, Sensitive: attr.Sensitive, Description: attr.Description, DeprecationMessage: attr.Deprecated}
+		case schema.TypeList, schema.TypeSet:
+			switch elem := attr.Elem.(type) {
+			case *schema.Resource:
+				nestedAttributes, nestedBlocks, err := frameworkProviderSchema(elem.Schema)
+				if err != nil {
+					return nil, nil, err
+				}
+				nested := fwschema.NestedBlockObject{Attributes: nestedAttributes, Blocks: nestedBlocks}
+				if attr.Type == schema.TypeSet {
+					blocks[name] = fwschema.SetNestedBlock{NestedObject: nested, Description: attr.Description, DeprecationMessage: attr.Deprecated}
+				} else {
+					blocks[name] = fwschema.ListNestedBlock{NestedObject: nested, Description: attr.Description, DeprecationMessage: attr.Deprecated}
+				}
+			case *schema.Schema:
+				if elem.Type != schema.TypeString {
+					return nil, nil, fmt.Errorf("unsupported element type %s of %s", elem.Type, name)
+				}
+				if attr.Type == schema.TypeSet {
+					attributes[name] = fwschema.SetAttribute{ElementType: types.StringType, Optional: attr.Optional, Required: attr.Required, Sensitive: attr.Sensitive, Description: attr.Description, DeprecationMessage: attr.Deprecated}
+				} else {
+					attributes[name] = fwschema.ListAttribute{ElementType: types.StringType, Optional: attr.Optional, Required: attr.Required, Sensitive: attr.Sensitive, Description: attr.Description, DeprecationMessage: attr.Deprecated}
+				}
+			default:
+				return nil, nil, fmt.Errorf("unsupported element of %s", name)
+			}
+		default:
+			return nil, nil, fmt.Errorf("unsupported type %s of %s", attr.Type, name)
+		}
+	}
+
+	return attributes, blocks, nil
+}
@@ -2,10 +2,16 @@ package civo
 
 import (
 	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"testing"
 
+	"github.com/civo/civogo"
+	"github.com/civo/terraform-provider-civo/internal/mockapi"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
@@ -107,6 +113,90 @@ func TestProvider_impl(t *testing.T) {
 //
 //}
 
+func writeCredentialsFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "civo.json")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write credentials file: %v", err)
+	}
+	return path
+}
+
+// TestReadCredentialsFromFile tests selecting API keys by profile name
+func TestReadCredentialsFromFile(t *testing.T) {
+	path := writeCredentialsFile(t, `
+		{
+			"apikeys": {
+				"personal": "personal-token",
+				"work": "work-token"
+			},
+			"meta": {
+				"current_apikey": "personal",
+				"default_region": "LON1",
+				"url": "https://api.civo.com"
+			}
+		}`)
+
+	tests := []struct {
+		name    string
+		profile string
+		want    credentials
+	}{
+		{
+			name: "current API key",
+			want: credentials{token: "personal-token", region: "LON1", apiURL: "https://api.civo.com"},
+		},
+		{
+			name:    "named profile",
+			profile: "work",
+			want:    credentials{token: "work-token"},
+		},
+		{
+			name:    "named profile of the current API key",
+			profile: "personal",
+			want:    credentials{token: "personal-token", region: "LON1", apiURL: "https://api.civo.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readCredentialsFromFile(path, tt.profile)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if *got != tt.want {
+				t.Errorf("got %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+
+	t.Run("unknown profile", func(t *testing.T) {
+		_, err := readCredentialsFromFile(path, "missing")
+		if err == nil || !strings.Contains(err.Error(), "personal, work") {
+			t.Errorf("expected an error listing the available keys, got %v", err)
+		}
+	})
+}
+
+// TestReadCredentialsFromFile_profileWithoutCurrentKey tests that a profile
+// can be selected from a file that doesn't set a current API key
+func TestReadCredentialsFromFile_profileWithoutCurrentKey(t *testing.T) {
+	path := writeCredentialsFile(t, `{"apikeys": {"ci": "ci-token"}}`)
+
+	got, err := readCredentialsFromFile(path, "ci")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.token != "ci-token" || got.region != "" || got.apiURL != "" {
+		t.Errorf("got %+v, want only the ci token", *got)
+	}
+
+	if _, err := readCredentialsFromFile(path, ""); err == nil {
+		t.Error("expected an error when no profile and no current API key are set")
+	}
+}
+
 func diagnosticsToString(diags diag.Diagnostics) string {
 	diagsAsStrings := make([]string, len(diags))
 	for i, diag := range diags {
@@ -125,3 +215,127 @@ func configureProvider(t testing.TB, raw map[string]interface{}) {
 		t.Fatalf("provider configure failed: %s", diagnosticsToString(diags))
 	}
 }
+
+// configureMockProvider configures the provider against a fake API, without the
+// environment of the machine running the tests
+func configureMockProvider(t *testing.T, s *mockapi.Server, raw map[string]interface{}) (*schema.Provider, diag.Diagnostics) {
+	t.Helper()
+	t.Setenv("CIVO_TOKEN", "")
+	t.Setenv("CIVO_SKIP_CREDENTIALS_VALIDATION", "")
+	t.Setenv("HOME", t.TempDir())
+
+	raw["api_endpoint"] = s.URL
+	if _, ok := raw["region"]; !ok {
+		raw["region"] = mockapi.DefaultRegion
+	}
+	p := Provider()
+	diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(raw))
+	return p, diags
+}
+
+// TestProviderConfigure_credentialsValidation tests the token check of the provider
+func TestProviderConfigure_credentialsValidation(t *testing.T) {
+	s := mockapi.NewServer()
+	defer s.Close()
+
+	t.Run("valid token", func(t *testing.T) {
+		_, diags := configureMockProvider(t, s, map[string]interface{}{"token": mockapi.Token})
+		if diags.HasError() {
+			t.Fatalf("provider configure failed: %s", diagnosticsToString(diags))
+		}
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		_, diags := configureMockProvider(t, s, map[string]interface{}{"token": "wrong"})
+		if !diags.HasError() || diags[0].Summary != "Invalid Civo token" {
+			t.Fatalf("diags = %s, want an invalid token error", diagnosticsToString(diags))
+		}
+		if !strings.Contains(diags[0].Detail, "the token argument") {
+			t.Errorf("detail = %q, want it to name the token source", diags[0].Detail)
+		}
+	})
+
+	t.Run("no token", func(t *testing.T) {
+		_, diags := configureMockProvider(t, s, map[string]interface{}{})
+		if !diags.HasError() || diags[0].Summary != "No Civo token found" {
+			t.Fatalf("diags = %s, want a missing token error", diagnosticsToString(diags))
+		}
+	})
+}
+
+// TestProviderConfigure_skipCredentialsValidation tests that the token is only checked
+// by the first request when skip_credentials_validation is set
+func TestProviderConfigure_skipCredentialsValidation(t *testing.T) {
+	s := mockapi.NewServer()
+	defer s.Close()
+
+	t.Run("invalid token", func(t *testing.T) {
+		before := s.RequestCount(http.MethodGet, "/v2/regions")
+		p, diags := configureMockProvider(t, s, map[string]interface{}{"token": "wrong", "skip_credentials_validation": true})
+		if diags.HasError() {
+			t.Fatalf("provider configure failed: %s", diagnosticsToString(diags))
+		}
+		if got := s.RequestCount(http.MethodGet, "/v2/regions"); got != before {
+			t.Errorf("the token was checked while configuring the provider")
+		}
+
+		_, err := p.Meta().(*civogo.Client).ListRegions()
+		if !errors.Is(err, civogo.DatabaseAccountNotFoundError) {
+			t.Fatalf("err = %v, want DatabaseAccountNotFoundError", err)
+		}
+		if !strings.Contains(err.Error(), "the token argument") {
+			t.Errorf("err = %q, want it to name the token source", err)
+		}
+	})
+
+	t.Run("no token", func(t *testing.T) {
+		before := len(s.Requests())
+		p, diags := configureMockProvider(t, s, map[string]interface{}{"skip_credentials_validation": true})
+		if diags.HasError() {
+			t.Fatalf("provider configure failed: %s", diagnosticsToString(diags))
+		}
+
+		_, err := p.Meta().(*civogo.Client).ListRegions()
+		if !errors.Is(err, civogo.AuthenticationFailedError) {
+			t.Fatalf("err = %v, want AuthenticationFailedError", err)
+		}
+		if got := len(s.Requests()); got != before {
+			t.Errorf("%d requests reached the API without a token", got-before)
+		}
+	})
+}
+
+// TestProviderConfigure_region tests the default region is checked and normalized
+func TestProviderConfigure_region(t *testing.T) {
+	s := mockapi.NewServer()
+	defer s.Close()
+
+	t.Run("normalized", func(t *testing.T) {
+		p, diags := configureMockProvider(t, s, map[string]interface{}{"token": mockapi.Token, "region": "lon1"})
+		if diags.HasError() {
+			t.Fatalf("provider configure failed: %s", diagnosticsToString(diags))
+		}
+		if region := p.Meta().(*civogo.Client).Region; region != mockapi.DefaultRegion {
+			t.Errorf("region = %q, want %q", region, mockapi.DefaultRegion)
+		}
+	})
+
+	t.Run("typo", func(t *testing.T) {
+		_, diags := configureMockProvider(t, s, map[string]interface{}{"token": mockapi.Token, "region": "lon2"})
+		if !diags.HasError() || diags[0].Summary != "Invalid region" {
+			t.Fatalf("diags = %s, want an invalid region error", diagnosticsToString(diags))
+		}
+		if !strings.Contains(diags[0].Detail, `did you mean "LON1"?`) {
+			t.Errorf("detail = %q, want a suggestion", diags[0].Detail)
+		}
+	})
+}
+
+// TestProvider_regionResolver tests every resource with a region checks it at plan time
+func TestProvider_regionResolver(t *testing.T) {
+	for name, r := range Provider().ResourcesMap {
+		if _, ok := r.Schema["region"]; ok && r.CustomizeDiff == nil {
+			t.Errorf("%s has a region but doesn't check it", name)
+		}
+	}
+}
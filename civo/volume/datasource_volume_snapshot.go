@@ -0,0 +1,114 @@
+package volume
+
+import (
+	"fmt"
+
+	"github.com/civo/civogo"
+	"github.com/civo/terraform-provider-civo/internal/datalist"
+	"github.com/civo/terraform-provider-civo/internal/utils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// DataSourceVolumeSnapshot Data source to get and filter all volume snapshots
+func DataSourceVolumeSnapshot() *schema.Resource {
+	dataListConfig := &datalist.ResourceConfig{
+		Description:  "Get information on volume snapshots for use in other resources (e.g. restoring a volume) with the ability to filter and sort the results. If no filters are specified, all snapshots will be returned.",
+		RecordSchema: volumeSnapshotSchema(),
+		ExtraQuerySchema: map[string]*schema.Schema{
+			"region": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "If used, all snapshots will be from the provided region",
+			},
+		},
+		ResultAttributeName: "snapshots",
+		FlattenRecord:       flattenVolumeSnapshot,
+		GetRecords:          getVolumeSnapshots,
+	}
+
+	return datalist.NewResource(dataListConfig)
+}
+
+func getVolumeSnapshots(m interface{}, extra map[string]interface{}) ([]interface{}, error) {
+	apiClient := m.(*civogo.Client)
+
+	// overwrite the region if is define in the datasource
+	region, ok := extra["region"].(string)
+	if !ok {
+		return nil, fmt.Errorf("unable to find `region` key from query data")
+	}
+
+	if region != "" {
+		apiClient = utils.RegionalClient(apiClient, region)
+	}
+
+	snapshots, err := apiClient.ListVolumeSnapshots()
+	if err != nil {
+		return nil, fmt.Errorf("[ERR] error retrieving volume snapshots: %s", err)
+	}
+
+	var records []interface{}
+	for _, snapshot := range snapshots {
+		records = append(records, snapshot)
+	}
+
+	return records, nil
+}
+
+func flattenVolumeSnapshot(snapshot, _ interface{}, _ map[string]interface{}) (map[string]interface{}, error) {
+	s := snapshot.(civogo.VolumeSnapshot)
+
+	flattenedSnapshot := map[string]interface{}{}
+	flattenedSnapshot["id"] = s.SnapshotID
+	flattenedSnapshot["name"] = s.Name
+	flattenedSnapshot["description"] = s.SnapshotDescription
+	flattenedSnapshot["volume_id"] = s.VolumeID
+	flattenedSnapshot["instance_id"] = s.InstanceID
+	flattenedSnapshot["source_volume_name"] = s.SourceVolumeName
+	flattenedSnapshot["restore_size"] = s.RestoreSize
+	flattenedSnapshot["state"] = s.State
+	flattenedSnapshot["creation_time"] = s.CreationTime
+
+	return flattenedSnapshot, nil
+}
+
+func volumeSnapshotSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"id": {
+			Type:        schema.TypeString,
+			Description: "ID of the snapshot",
+		},
+		"name": {
+			Type:        schema.TypeString,
+			Description: "Name of the snapshot",
+		},
+		"description": {
+			Type:        schema.TypeString,
+			Description: "Description of the snapshot",
+		},
+		"volume_id": {
+			Type:        schema.TypeString,
+			Description: "ID of the volume the snapshot was taken of",
+		},
+		"instance_id": {
+			Type:        schema.TypeString,
+			Description: "ID of the instance the volume was attached to",
+		},
+		"source_volume_name": {
+			Type:        schema.TypeString,
+			Description: "Name of the volume the snapshot was taken of",
+		},
+		"restore_size": {
+			Type:        schema.TypeInt,
+			Description: "Minimum size in gigabytes of a volume restored from the snapshot",
+		},
+		"state": {
+			Type:        schema.TypeString,
+			Description: "State of the snapshot",
+		},
+		"creation_time": {
+			Type:        schema.TypeString,
+			Description: "Creation time of the snapshot",
+		},
+	}
+}
@@ -52,6 +52,12 @@ func ResourceVolume() *schema.Resource {
 				Optional:    true,
 				Description: "The type of the volume",
 			},
+			"snapshot_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The ID of a volume snapshot to restore into the new volume, `size_gb` must be at least the snapshot's `restore_size`",
+			},
 		},
 		CreateContext: resourceVolumeCreate,
 		ReadContext:   resourceVolumeRead,
@@ -60,6 +66,7 @@ func ResourceVolume() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: resourceVolumeImport,
 		},
+		CustomizeDiff: customizeDiffVolume,
 	}
 }
 
@@ -85,6 +92,17 @@ func resourceVolumeCreate(ctx context.Context, d *schema.ResourceData, m interfa
 		config.VolumeType = v.(string)
 	}
 
+	if v, ok := d.GetOk("snapshot_id"); ok {
+		snapshot, err := apiClient.GetVolumeSnapshot(v.(string))
+		if err != nil {
+			return diag.Errorf("[ERR] failed to get the volume snapshot %s: %s", v.(string), err)
+		}
+		if err := validateRestoreSize(config.SizeGigabytes, snapshot); err != nil {
+			return diag.Errorf("[ERR] %s", err)
+		}
+		config.SnapshotID = snapshot.SnapshotID
+	}
+
 	_, err := apiClient.FindNetwork(config.NetworkID)
 	if err != nil {
 		return diag.Errorf("[ERR] Unable to find network ID %q in %q region", config.NetworkID, config.Region)
@@ -231,6 +249,37 @@ func resourceVolumeDelete(_ context.Context, d *schema.ResourceData, m interface
 	return nil
 }
 
+// customizeDiffVolume checks at plan time that a volume restored from a
+// snapshot is big enough to hold it
+func customizeDiffVolume(_ context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() != "" || !d.NewValueKnown("snapshot_id") || !d.NewValueKnown("size_gb") {
+		return nil
+	}
+	snapshotID, ok := d.GetOk("snapshot_id")
+	if !ok {
+		return nil
+	}
+
+	apiClient := m.(*civogo.Client)
+	if region, ok := d.GetOk("region"); ok {
+		apiClient = utils.RegionalClient(apiClient, region.(string))
+	}
+
+	snapshot, err := apiClient.GetVolumeSnapshot(snapshotID.(string))
+	if err != nil {
+		return fmt.Errorf("failed to get the volume snapshot %s: %s", snapshotID.(string), err)
+	}
+	return validateRestoreSize(d.Get("size_gb").(int), snapshot)
+}
+
+// validateRestoreSize checks a volume of sizeGB can hold the snapshot
+func validateRestoreSize(sizeGB int, snapshot *civogo.VolumeSnapshot) error {
+	if sizeGB < snapshot.RestoreSize {
+		return fmt.Errorf("size_gb (%d) must be at least %d to restore the snapshot %s", sizeGB, snapshot.RestoreSize, snapshot.Name)
+	}
+	return nil
+}
+
 // custom import to able to import a volume
 func resourceVolumeImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
 	apiClient := m.(*civogo.Client)
@@ -0,0 +1,214 @@
+package volume
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/civo/civogo"
+	"github.com/civo/terraform-provider-civo/internal/utils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// ResourceVolumeSnapshot function returns a schema.Resource that represents a point in time
+// copy of a Volume, which can be used as the source of new volumes
+func ResourceVolumeSnapshot() *schema.Resource {
+	return &schema.Resource{
+		Description: "Provides a Civo volume snapshot, a point in time copy of a volume which can be used to create new volumes.",
+		Schema: map[string]*schema.Schema{
+			"volume_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: utils.ValidateUUID,
+				Description:  "The ID of the volume to snapshot",
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: utils.ValidateName,
+				Description:  "A name that you wish to use to refer to this snapshot",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "A description for the snapshot",
+			},
+			"region": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				Description:      "The region for the snapshot, if not declare we use the region in declared in the provider.",
+				DiffSuppressFunc: utils.IgnoreCaseDiff,
+			},
+			// Computed resource
+			"restore_size": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The minimum size in gigabytes of a volume restored from the snapshot",
+			},
+			"source_volume_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the volume the snapshot was taken of",
+			},
+			"instance_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The ID of the instance the volume was attached to when the snapshot was taken",
+			},
+			"state": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The state of the snapshot",
+			},
+			"creation_time": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The time the snapshot was created",
+			},
+		},
+		CreateContext: resourceVolumeSnapshotCreate,
+		ReadContext:   resourceVolumeSnapshotRead,
+		DeleteContext: resourceVolumeSnapshotDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
+	}
+}
+
+// function to create the new volume snapshot
+func resourceVolumeSnapshotCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*civogo.Client)
+
+	// overwrite the region if is defined in the datasource
+	if region, ok := d.GetOk("region"); ok {
+		apiClient = utils.RegionalClient(apiClient, region.(string))
+	}
+
+	volumeID := d.Get("volume_id").(string)
+	config := &civogo.VolumeSnapshotConfig{
+		Name:        d.Get("name").(string),
+		Description: d.Get("description").(string),
+		Region:      apiClient.Region,
+	}
+
+	log.Printf("[INFO] creating the snapshot %s of the volume %s", config.Name, volumeID)
+	snapshot, err := apiClient.CreateVolumeSnapshot(volumeID, config)
+	if err != nil {
+		return diag.Errorf("[ERR] failed to create the snapshot of the volume %s: %s", volumeID, err)
+	}
+
+	d.SetId(snapshot.SnapshotID)
+
+	createStateConf := &retry.StateChangeConf{
+		Pending: []string{"pending", "creating"},
+		Target:  []string{"ready", "available"},
+		Refresh: func() (interface{}, string, error) {
+			resp, err := apiClient.GetVolumeSnapshot(d.Id())
+			if err != nil {
+				return 0, "", err
+			}
+			state := strings.ToLower(resp.State)
+			if state == "failed" || state == "error" {
+				return resp, state, fmt.Errorf("the snapshot failed")
+			}
+			return resp, state, nil
+		},
+		Timeout:        d.Timeout(schema.TimeoutCreate),
+		Delay:          3 * time.Second,
+		MinTimeout:     3 * time.Second,
+		NotFoundChecks: 10,
+	}
+	if _, err = createStateConf.WaitForStateContext(ctx); err != nil {
+		return diag.Errorf("error waiting for volume snapshot (%s) to be created: %s", d.Id(), err)
+	}
+
+	return resourceVolumeSnapshotRead(ctx, d, m)
+}
+
+// function to read the volume snapshot
+func resourceVolumeSnapshotRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*civogo.Client)
+
+	// overwrite the region if is define in the datasource
+	if region, ok := d.GetOk("region"); ok {
+		apiClient = utils.RegionalClient(apiClient, region.(string))
+	}
+
+	log.Printf("[INFO] retrieving the volume snapshot %s", d.Id())
+	resp, err := apiClient.GetVolumeSnapshot(d.Id())
+	if err != nil {
+		if errors.Is(err, civogo.DatabaseSnapshotNotFoundError) {
+			log.Printf("[INFO] volume snapshot %s not found", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("[ERR] failed retrieving the volume snapshot: %s", err)
+	}
+
+	d.Set("name", resp.Name)
+	d.Set("description", resp.SnapshotDescription)
+	d.Set("volume_id", resp.VolumeID)
+	d.Set("region", apiClient.Region)
+	d.Set("restore_size", resp.RestoreSize)
+	d.Set("source_volume_name", resp.SourceVolumeName)
+	d.Set("instance_id", resp.InstanceID)
+	d.Set("state", resp.State)
+	d.Set("creation_time", resp.CreationTime)
+
+	return nil
+}
+
+// function to delete the volume snapshot
+func resourceVolumeSnapshotDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := m.(*civogo.Client)
+
+	// overwrite the region if is define in the datasource
+	if region, ok := d.GetOk("region"); ok {
+		apiClient = utils.RegionalClient(apiClient, region.(string))
+	}
+
+	log.Printf("[INFO] deleting the volume snapshot %s", d.Id())
+	if _, err := apiClient.DeleteVolumeSnapshot(d.Id()); err != nil {
+		if errors.Is(err, civogo.DatabaseSnapshotNotFoundError) {
+			return nil
+		}
+		return diag.Errorf("[ERR] an error occurred while trying to delete the volume snapshot %s: %s", d.Id(), err)
+	}
+
+	deleteStateConf := &retry.StateChangeConf{
+		Pending: []string{"deleting"},
+		Target:  []string{"deleted"},
+		Refresh: func() (interface{}, string, error) {
+			resp, err := apiClient.GetVolumeSnapshot(d.Id())
+			if err != nil {
+				if errors.Is(err, civogo.DatabaseSnapshotNotFoundError) {
+					return 0, "deleted", nil
+				}
+				return 0, "", err
+			}
+			return resp, "deleting", nil
+		},
+		Timeout:    d.Timeout(schema.TimeoutDelete),
+		Delay:      3 * time.Second,
+		MinTimeout: 3 * time.Second,
+	}
+	if _, err := deleteStateConf.WaitForStateContext(ctx); err != nil {
+		return diag.Errorf("error waiting for volume snapshot (%s) to be deleted: %s", d.Id(), err)
+	}
+
+	return nil
+}
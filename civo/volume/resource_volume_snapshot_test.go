@@ -0,0 +1,141 @@
+package volume_test
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/civo/civogo"
+	"github.com/civo/terraform-provider-civo/civo/acceptance"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// TestAccCivoVolumeSnapshot_basic tests a snapshot can be taken and restored into a new volume
+func TestAccCivoVolumeSnapshot_basic(t *testing.T) {
+	resName := "civo_volume_snapshot.foobar"
+	var name = acctest.RandomWithPrefix("tf-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { acceptance.TestAccPreCheck(t) },
+		Providers:    acceptance.TestAccProviders,
+		CheckDestroy: CivoVolumeSnapshotDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: CivoVolumeSnapshotConfigBasic(name, 10),
+				Check: resource.ComposeTestCheckFunc(
+					CivoVolumeSnapshotResourceExists(resName),
+					resource.TestCheckResourceAttr(resName, "name", name),
+					resource.TestCheckResourceAttr(resName, "restore_size", "10"),
+					resource.TestCheckResourceAttrPair("civo_volume.restored", "snapshot_id", resName, "id"),
+					resource.TestCheckResourceAttr("data.civo_volume_snapshot.foobar", "snapshots.#", "1"),
+				),
+			},
+			{
+				ResourceName:      resName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// TestAccCivoVolumeSnapshot_restoreTooSmall tests restoring into a smaller volume fails at plan time
+func TestAccCivoVolumeSnapshot_restoreTooSmall(t *testing.T) {
+	var name = acctest.RandomWithPrefix("tf-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { acceptance.TestAccPreCheck(t) },
+		Providers:    acceptance.TestAccProviders,
+		CheckDestroy: CivoVolumeSnapshotDestroy,
+		Steps: []resource.TestStep{
+			{
+				// without the restored volume, so the snapshot is known when it's planned
+				Config: CivoVolumeSnapshotConfigBasic(name, 0),
+			},
+			{
+				Config:      CivoVolumeSnapshotConfigBasic(name, 5),
+				ExpectError: regexp.MustCompile(`size_gb \(5\) must be at least 10`),
+			},
+		},
+	})
+}
+
+// CivoVolumeSnapshotResourceExists queries the API for the snapshot
+func CivoVolumeSnapshotResourceExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("not found: %s", n)
+		}
+
+		client := acceptance.TestAccProvider.Meta().(*civogo.Client)
+		if _, err := client.GetVolumeSnapshot(rs.Primary.ID); err != nil {
+			return fmt.Errorf("volume snapshot not found: (%s) %s", rs.Primary.ID, err)
+		}
+		return nil
+	}
+}
+
+// CivoVolumeSnapshotDestroy checks the snapshots created during the test are gone
+func CivoVolumeSnapshotDestroy(s *terraform.State) error {
+	client := acceptance.TestAccProvider.Meta().(*civogo.Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "civo_volume_snapshot" {
+			continue
+		}
+
+		_, err := client.GetVolumeSnapshot(rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("volume snapshot still exists")
+		}
+	}
+
+	return nil
+}
+
+// CivoVolumeSnapshotConfigBasic snapshots a volume and restores it into a volume
+// of restoredSize, the restored volume is left out when restoredSize is 0
+func CivoVolumeSnapshotConfigBasic(name string, restoredSize int) string {
+	config := fmt.Sprintf(`
+data "civo_network" "default" {
+	label = "default"
+	region = "LON1"
+}
+
+resource "civo_volume" "foobar" {
+	name = "%[1]s"
+	size_gb = 10
+	network_id = data.civo_network.default.id
+	region = "LON1"
+}
+
+resource "civo_volume_snapshot" "foobar" {
+	volume_id = civo_volume.foobar.id
+	name = "%[1]s"
+	region = "LON1"
+}
+
+data "civo_volume_snapshot" "foobar" {
+	region = "LON1"
+	filter {
+		key = "id"
+		values = [civo_volume_snapshot.foobar.id]
+	}
+}`, name)
+
+	if restoredSize == 0 {
+		return config
+	}
+	return config + fmt.Sprintf(`
+
+resource "civo_volume" "restored" {
+	name = "%[1]s-restored"
+	size_gb = %[2]d
+	network_id = data.civo_network.default.id
+	region = "LON1"
+	snapshot_id = civo_volume_snapshot.foobar.id
+}`, name, restoredSize)
+}
@@ -0,0 +1,25 @@
+data "civo_database_backup" "production" {
+  database_id = civo_database.production.id
+  filter {
+    key    = "status"
+    values = ["COMPLETED"]
+  }
+  sort {
+    key       = "created_at"
+    direction = "desc"
+  }
+}
+
+# Build a new database from the latest completed backup
+resource "civo_database" "staging" {
+  name    = "staging"
+  size    = "g3.db.small"
+  nodes   = 1
+  engine  = "PostgreSQL"
+  version = "16"
+
+  restore_from {
+    source_database_id = civo_database.production.id
+    backup             = element(data.civo_database_backup.production.backups, 0).name
+  }
+}
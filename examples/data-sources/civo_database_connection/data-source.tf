@@ -0,0 +1,32 @@
+resource "civo_database_user" "app" {
+  database_id = civo_database.orders.id
+  username    = "app"
+
+  grant {
+    database   = "orders"
+    privileges = ["SELECT", "INSERT", "UPDATE", "DELETE"]
+  }
+}
+
+data "civo_database_connection" "app" {
+  database_id = civo_database.orders.id
+  database    = "orders"
+  username    = civo_database_user.app.username
+  password    = civo_database_user.app.password
+  private     = true
+  sslmode     = "verify-full"
+
+  fetch_ca_certificate = true
+}
+
+resource "kubernetes_secret" "orders_database" {
+  metadata {
+    name = "orders-database"
+  }
+
+  data = {
+    DATABASE_URL = data.civo_database_connection.app.uri
+    JDBC_URL     = data.civo_database_connection.app.jdbc_url
+    "ca.crt"     = data.civo_database_connection.app.ca_certificate
+  }
+}
@@ -0,0 +1,14 @@
+data "civo_kubernetes_marketplace" "monitoring" {
+    filter {
+        key = "category"
+        values = ["monitoring"]
+    }
+    sort {
+        key = "name"
+        direction = "asc"
+    }
+}
+
+output "monitoring_applications" {
+    value = data.civo_kubernetes_marketplace.monitoring.applications[*].name
+}
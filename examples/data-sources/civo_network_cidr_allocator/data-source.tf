@@ -0,0 +1,15 @@
+data "civo_network_cidr_allocator" "vpcs" {
+  labels        = ["prod", "staging", "dev"]
+  prefix_length = 22
+  base_cidr     = "10.0.0.0/16"
+  exclude       = ["10.0.0.0/20"]
+  region        = "LON1"
+}
+
+resource "civo_network" "vpc" {
+  for_each = data.civo_network_cidr_allocator.vpcs.cidrs
+
+  label   = each.key
+  cidr_v4 = each.value
+  region  = "LON1"
+}
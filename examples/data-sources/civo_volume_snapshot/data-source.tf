@@ -0,0 +1,17 @@
+data "civo_volume_snapshot" "db" {
+   filter {
+        key = "source_volume_name"
+        values = ["backup-data"]
+   }
+   sort {
+        key = "creation_time"
+        direction = "desc"
+   }
+}
+
+resource "civo_volume" "restored" {
+    name        = "backup-data-restored"
+    size_gb     = element(data.civo_volume_snapshot.db.snapshots, 0).restore_size
+    network_id  = data.civo_network.default_network.id
+    snapshot_id = element(data.civo_volume_snapshot.db.snapshots, 0).id
+}
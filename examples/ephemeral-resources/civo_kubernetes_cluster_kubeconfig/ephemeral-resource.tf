@@ -0,0 +1,20 @@
+ephemeral "civo_kubernetes_cluster_kubeconfig" "my-cluster" {
+    cluster_id   = civo_kubernetes_cluster.my-cluster.id
+    context_name = "production"
+}
+
+provider "kubernetes" {
+  host                   = ephemeral.civo_kubernetes_cluster_kubeconfig.my-cluster.host
+  cluster_ca_certificate = ephemeral.civo_kubernetes_cluster_kubeconfig.my-cluster.cluster_ca_certificate
+  client_certificate     = ephemeral.civo_kubernetes_cluster_kubeconfig.my-cluster.client_certificate
+  client_key             = ephemeral.civo_kubernetes_cluster_kubeconfig.my-cluster.client_key
+}
+
+provider "helm" {
+  kubernetes {
+    host                   = ephemeral.civo_kubernetes_cluster_kubeconfig.my-cluster.host
+    cluster_ca_certificate = ephemeral.civo_kubernetes_cluster_kubeconfig.my-cluster.cluster_ca_certificate
+    client_certificate     = ephemeral.civo_kubernetes_cluster_kubeconfig.my-cluster.client_certificate
+    client_key             = ephemeral.civo_kubernetes_cluster_kubeconfig.my-cluster.client_key
+  }
+}
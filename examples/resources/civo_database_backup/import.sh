@@ -0,0 +1,2 @@
+# using the ID of the database and the ID of the backup
+terraform import civo_database_backup.nightly 29fcd1c4-fb61-44c7-b49c-dc7b98e9927e:6b0fbd3b-7ea5-4d0f-9a0c-6c8d0b7b6f2e
@@ -0,0 +1,13 @@
+# Take a backup of the database once
+resource "civo_database_backup" "before_migration" {
+  database_id = civo_database.custom_database.id
+  name        = "before-migration"
+}
+
+# Back up the database every night at 2am and keep the last 7 backups
+resource "civo_database_backup" "nightly" {
+  database_id = civo_database.custom_database.id
+  name        = "nightly"
+  schedule    = "0 2 * * *"
+  retention   = 7
+}
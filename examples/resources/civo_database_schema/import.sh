@@ -0,0 +1,2 @@
+# using the ID of the database and the name of the logical database
+terraform import civo_database_schema.orders 29fcd1c4-fb61-44c7-b49c-dc7b98e9927e:orders
@@ -0,0 +1,5 @@
+# A database of its own for the orders app
+resource "civo_database_schema" "orders" {
+  database_id = civo_database.custom_database.id
+  name        = "orders"
+}
@@ -0,0 +1,2 @@
+# using the ID of the database and the name of the user, the password and the grants can't be read back
+terraform import civo_database_user.orders 29fcd1c4-fb61-44c7-b49c-dc7b98e9927e:orders
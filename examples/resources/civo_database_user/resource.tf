@@ -0,0 +1,23 @@
+# The orders app reads and writes its own database, with a generated password
+resource "civo_database_user" "orders" {
+  database_id = civo_database.custom_database.id
+  username    = "orders"
+
+  grant {
+    database   = civo_database_schema.orders.name
+    privileges = ["SELECT", "INSERT", "UPDATE", "DELETE"]
+  }
+}
+
+# The reporting job only reads it, the password is never stored in the state
+resource "civo_database_user" "reporting" {
+  database_id         = civo_database.custom_database.id
+  username            = "reporting"
+  password_wo         = var.reporting_password
+  password_wo_version = 1
+
+  grant {
+    database   = civo_database_schema.orders.name
+    privileges = ["SELECT"]
+  }
+}
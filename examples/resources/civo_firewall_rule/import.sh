@@ -1,2 +1,2 @@
-# using firewall_id:firewall_rule_id
-terraform import civo_firewall_rule.http b8ecd2ab-2267-4a5e-8692-cbf1d32583e3:4b0022ee-00b2-4f81-a40d-b4f8728923a7
+# using firewall_id:rule_id
+terraform import civo_firewall_rule.app b8ecd2ab-2267-4a5e-8692-cbf1d32583e3:4f5c2a1e-6d3b-4c8e-9a7f-2b1d0e9c8a76
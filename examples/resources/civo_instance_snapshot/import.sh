@@ -0,0 +1,2 @@
+# using instance_id:snapshot_id
+terraform import civo_instance_snapshot.golden 18bd98ad-1b6e-4f87-b48f-e690b4fd7413:4cc87851-e1d0-4270-822a-b36d28c7a77f
@@ -0,0 +1,16 @@
+# Take a snapshot of an instance, and keep taking one every night
+resource "civo_instance_snapshot" "golden" {
+  instance_id   = civo_instance.example.id
+  name          = "golden-image"
+  description   = "Base image for the web servers"
+  schedule      = "0 2 * * *"
+  max_snapshots = 7
+}
+
+# Build a new instance from the snapshot
+resource "civo_instance" "web" {
+  hostname    = "web-1"
+  size        = "g3.small"
+  firewall_id = civo_firewall.example.id
+  snapshot_id = civo_instance_snapshot.golden.id
+}
@@ -0,0 +1,2 @@
+# using cluster_id:name
+terraform import civo_kubernetes_application.cert_manager 1b8b2100-0e9f-4e8f-ad78-9eb578c2a0af:cert-manager
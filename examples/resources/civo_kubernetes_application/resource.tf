@@ -0,0 +1,12 @@
+# Install cert-manager in an existing cluster
+resource "civo_kubernetes_application" "cert_manager" {
+    cluster_id = civo_kubernetes_cluster.my-cluster.id
+    name       = "cert-manager"
+}
+
+# Applications with plans take the plan as a separate argument
+resource "civo_kubernetes_application" "redis" {
+    cluster_id = civo_kubernetes_cluster.my-cluster.id
+    name       = "Redis"
+    plan       = "5GB"
+}
@@ -0,0 +1,23 @@
+# Create a cluster with several node pools, each pool is identified by its label
+resource "civo_kubernetes_cluster" "my-cluster" {
+    name = "my-cluster"
+    firewall_id = civo_firewall.my-firewall.id
+
+    pools {
+        label = "workers"
+        size = "g4s.kube.medium"
+        node_count = 3
+    }
+
+    pools {
+        label = "batch"
+        size = "g4s.kube.large"
+        node_count = 2
+
+        taint {
+          key    = "workloadKind"
+          value  = "batch"
+          effect = "NoSchedule"
+        }
+    }
+}
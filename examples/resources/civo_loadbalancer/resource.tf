@@ -1 +1,25 @@
-# TODO
+resource "civo_reserved_ip" "www" {
+  name = "lb-www"
+}
+
+resource "civo_loadbalancer" "www" {
+  name                    = "lb-www"
+  algorithm               = "round_robin"
+  external_traffic_policy = "Cluster"
+  session_affinity        = "ClientIP"
+  firewall_rule           = "80,443"
+  reserved_ip_id          = civo_reserved_ip.www.id
+
+  backend {
+    ip                = civo_instance.web1.private_ip
+    source_port       = 80
+    target_port       = 8080
+    health_check_port = 8081
+  }
+
+  backend {
+    ip          = civo_instance.web2.private_ip
+    source_port = 80
+    target_port = 8080
+  }
+}
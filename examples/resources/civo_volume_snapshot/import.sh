@@ -0,0 +1,2 @@
+# using ID
+terraform import civo_volume_snapshot.db 6b0fbd3b-7ea5-4d0f-9a0c-6c8d0b7b6f2e
@@ -0,0 +1,14 @@
+# Snapshot an existing volume
+resource "civo_volume_snapshot" "db" {
+    volume_id   = civo_volume.db.id
+    name        = "backup-data-snapshot"
+    description = "Nightly copy of the backup data"
+}
+
+# Create a new volume from the snapshot
+resource "civo_volume" "restored" {
+    name        = "backup-data-restored"
+    size_gb     = civo_volume_snapshot.db.restore_size
+    network_id  = data.civo_network.default_network.id
+    snapshot_id = civo_volume_snapshot.db.id
+}
@@ -0,0 +1,111 @@
+package mockapi
+
+import (
+	"net/http"
+
+	"github.com/civo/civogo"
+)
+
+// loadCatalog fills the read-only data the fake API serves: regions, sizes,
+// disk images and the supported Kubernetes and database versions
+func (s *Server) loadCatalog() {
+	features := civogo.Feature{
+		Iaas:         true,
+		Kubernetes:   true,
+		ObjectStore:  true,
+		LoadBalancer: true,
+		DBaaS:        true,
+		Volume:       true,
+	}
+	s.regions = []civogo.Region{
+		{Code: "LON1", Name: "London 1", Type: "civostack", Country: "GB", CountryName: "United Kingdom", Features: features, Default: true},
+		{Code: "FRA1", Name: "Frankfurt 1", Type: "civostack", Country: "DE", CountryName: "Germany", Features: features},
+		{Code: "NYC1", Name: "New York 1", Type: "civostack", Country: "US", CountryName: "United States", Features: features},
+		{Code: "PHX1", Name: "Phoenix 1", Type: "civostack", Country: "US", CountryName: "United States", Features: features},
+		// the regions used by the acceptance tests, as the provider checks every region against this list
+		{Code: "FAKE", Name: "Fake", Type: "civostack", Country: "GB", CountryName: "United Kingdom", Features: features},
+		{Code: "LOCAL", Name: "Local", Type: "civostack", Country: "GB", CountryName: "United Kingdom", Features: features},
+	}
+
+	s.sizes = []civogo.InstanceSize{
+		{Type: "Instance", Name: "g3.xsmall", NiceName: "Extra Small", CPUCores: 1, RAMMegabytes: 1024, DiskGigabytes: 25, Selectable: true},
+		{Type: "Instance", Name: "g3.small", NiceName: "Small", CPUCores: 1, RAMMegabytes: 2048, DiskGigabytes: 25, Selectable: true},
+		{Type: "Instance", Name: "g3.medium", NiceName: "Medium", CPUCores: 2, RAMMegabytes: 4096, DiskGigabytes: 50, Selectable: true},
+		{Type: "Instance", Name: "g3.large", NiceName: "Large", CPUCores: 4, RAMMegabytes: 8192, DiskGigabytes: 100, Selectable: true},
+		{Type: "Kubernetes", Name: "g4s.kube.xsmall", NiceName: "Extra Small", CPUCores: 1, RAMMegabytes: 1024, DiskGigabytes: 30, Selectable: true},
+		{Type: "Kubernetes", Name: "g4s.kube.small", NiceName: "Small", CPUCores: 1, RAMMegabytes: 2048, DiskGigabytes: 40, Selectable: true},
+		{Type: "Kubernetes", Name: "g4s.kube.medium", NiceName: "Medium", CPUCores: 2, RAMMegabytes: 4096, DiskGigabytes: 50, Selectable: true},
+		{Type: "Database", Name: "g3.db.small", NiceName: "Small", CPUCores: 2, RAMMegabytes: 4096, DiskGigabytes: 40, Selectable: true},
+		{Type: "Database", Name: "g3.db.medium", NiceName: "Medium", CPUCores: 4, RAMMegabytes: 8192, DiskGigabytes: 80, Selectable: true},
+		{Type: "Database", Name: "g3.db.large", NiceName: "Large", CPUCores: 6, RAMMegabytes: 16384, DiskGigabytes: 160, Selectable: true},
+	}
+
+	s.diskImages = []civogo.DiskImage{
+		{ID: "b0d30599-898a-4072-86a1-6ed2965320d9", Name: "ubuntu-jammy", Version: "22.04", State: "available", Distribution: "ubuntu", Label: "jammy", DistributionDefault: true},
+		{ID: "ec4b0cc1-c2a6-4bd5-91e2-1d3a0b3e8f35", Name: "ubuntu-noble", Version: "24.04", State: "available", Distribution: "ubuntu", Label: "noble"},
+		{ID: "9b5a0a3a-0bc4-4c83-a56a-0b1c0b7a84e4", Name: "debian-12", Version: "12", State: "available", Distribution: "debian", Label: "bookworm", DistributionDefault: true},
+		{ID: "d927ad2f-5073-4ed6-b2eb-b8e61aef29a8", Name: "rocky-9-1", Version: "9.1", State: "available", Distribution: "rocky", Label: "rocky", DistributionDefault: true},
+	}
+
+	s.kubeVersions = []civogo.KubernetesVersion{
+		{Label: "1.30.5-k3s1", Version: "1.30.5-k3s1", Type: "stable", ClusterType: "k3s"},
+		{Label: "1.31.2-k3s1", Version: "1.31.2-k3s1", Type: "stable", ClusterType: "k3s", Default: true},
+		{Label: "1.32.5-k3s1", Version: "1.32.5-k3s1", Type: "stable", ClusterType: "k3s"},
+		{Label: "talos-v1.5.0", Version: "talos-v1.5.0", Type: "stable", ClusterType: "talos"},
+	}
+
+	s.marketplace = []civogo.KubernetesMarketplaceApplication{
+		{Name: "Traefik-v2-nodeport", Title: "Traefik v2 (NodePort)", Version: "2.10.4", Default: true, Maintainer: "@civo", Description: "Cloud native edge router", Category: "architecture"},
+		{Name: "metrics-server", Title: "Metrics Server", Version: "0.7.0", Default: true, Maintainer: "@civo", Description: "Cluster wide resource usage data", Category: "architecture"},
+		{Name: "cert-manager", Title: "cert-manager", Version: "1.14.4", Maintainer: "@civo", Description: "Certificate management for Kubernetes", Category: "security"},
+		{Name: "Redis", Title: "Redis", Version: "7.2", Maintainer: "@civo", Description: "In-memory data store", Category: "database", Dependencies: []string{"Longhorn"},
+			Plans: []civogo.KubernetesMarketplacePlan{{Label: "5GB"}, {Label: "10GB"}}},
+		{Name: "Longhorn", Title: "Longhorn", Version: "1.6.0", Maintainer: "@civo", Description: "Distributed block storage", Category: "storage"},
+		{Name: "civo-cluster-autoscaler", Title: "Civo Cluster Autoscaler", Version: "1.29.0", Maintainer: "@civo", Description: "Scales node pools with the load of the cluster", Category: "management"},
+	}
+
+	s.databaseEngine = map[string][]civogo.SupportedSoftwareVersion{
+		"mysql":      {{SoftwareVersion: "8.0", Default: true}},
+		"postgresql": {{SoftwareVersion: "14", Default: false}, {SoftwareVersion: "16", Default: true}},
+	}
+}
+
+func (s *Server) registerCatalog(mux *http.ServeMux) {
+	mux.HandleFunc("GET /v2/regions", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, s.regions)
+	})
+	mux.HandleFunc("GET /v2/sizes", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, s.sizes)
+	})
+	mux.HandleFunc("GET /v2/disk_images", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, s.diskImages)
+	})
+	mux.HandleFunc("GET /v2/disk_images/{id}", func(w http.ResponseWriter, r *http.Request) {
+		for _, image := range s.diskImages {
+			if image.ID == r.PathValue("id") || image.Name == r.PathValue("id") {
+				writeJSON(w, http.StatusOK, image)
+				return
+			}
+		}
+		notFound(w, "database_disk_image_not_found", "disk image", r.PathValue("id"))
+	})
+	mux.HandleFunc("GET /v2/kubernetes/versions", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, s.kubeVersions)
+	})
+	mux.HandleFunc("GET /v2/kubernetes/applications", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, s.marketplace)
+	})
+	mux.HandleFunc("GET /v2/databases/versions", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, s.databaseEngine)
+	})
+}
+
+// findSize returns the size with the given name, if it exists
+func (s *Server) findSize(name string) (civogo.InstanceSize, bool) {
+	for _, size := range s.sizes {
+		if size.Name == name {
+			return size, true
+		}
+	}
+	return civogo.InstanceSize{}, false
+}
@@ -0,0 +1,452 @@
+package mockapi
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/civo/civogo"
+)
+
+type database struct {
+	civogo.Database
+	region  string
+	backups []*civogo.DatabaseBackup
+	// backupCount is the number of scheduled backups to keep
+	backupCount int
+}
+
+// databaseBackup is a backup with the number of scheduled backups to keep, which civogo
+// doesn't send nor decode
+type databaseBackup struct {
+	civogo.DatabaseBackup
+	Count int `json:"count,omitempty"`
+}
+
+// backupView returns the backup as the API shows it
+func (s *Server) backupView(db *database, b *civogo.DatabaseBackup) databaseBackup {
+	backup := databaseBackup{DatabaseBackup: *b}
+	backup.Status = s.status(b.ID, b.Status)
+	if b.IsScheduled {
+		backup.Count = db.backupCount
+	}
+	return backup
+}
+
+func (s *Server) registerDatabases(mux *http.ServeMux) {
+	mux.HandleFunc("GET /v2/databases", s.listDatabases)
+	mux.HandleFunc("POST /v2/databases", s.createDatabase)
+	mux.HandleFunc("GET /v2/databases/{id}", s.getDatabase)
+	mux.HandleFunc("PUT /v2/databases/{id}", s.updateDatabase)
+	mux.HandleFunc("DELETE /v2/databases/{id}", s.deleteDatabase)
+	mux.HandleFunc("POST /v2/databases/{id}/restore", s.restoreDatabase)
+	mux.HandleFunc("GET /v2/databases/{id}/backups", s.listDatabaseBackups)
+	mux.HandleFunc("POST /v2/databases/{id}/backups", s.createDatabaseBackup)
+	mux.HandleFunc("PUT /v2/databases/{id}/backups", s.updateDatabaseBackup)
+	mux.HandleFunc("GET /v2/databases/{id}/backups/{backup}", s.getDatabaseBackup)
+	mux.HandleFunc("DELETE /v2/databases/{id}/backups/{backup}", s.deleteDatabaseBackup)
+}
+
+// SetDatabaseConnection points the database at a real server, e.g. a local MySQL or
+// PostgreSQL container, so the resources connecting to its endpoint can be tested
+func (s *Server) SetDatabaseConnection(id, address string, port int, username, password string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	db, ok := s.databases[id]
+	if !ok {
+		return fmt.Errorf("the database %s could not be found", id)
+	}
+	db.PublicIPv4 = address
+	db.Port = port
+	db.Username = username
+	db.Password = password
+	db.DatabaseUserInfo = []civogo.DatabaseUserInfo{{Username: username, Password: password, Port: port}}
+	return nil
+}
+
+// database returns the database visible to the request
+func (s *Server) database(r *http.Request, id string) (*database, bool) {
+	db, ok := s.databases[id]
+	if !ok || !inRegion(r, db.region) {
+		return nil, false
+	}
+	return db, true
+}
+
+func (s *Server) databaseView(db *database) civogo.Database {
+	result := db.Database
+	result.Status = s.status(db.ID, db.Status)
+	return result
+}
+
+func (s *Server) listDatabases(w http.ResponseWriter, r *http.Request) {
+	all := []civogo.Database{}
+	for _, db := range s.databases {
+		if inRegion(r, db.region) {
+			all = append(all, s.databaseView(db))
+		}
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Name < all[j].Name })
+
+	items, current, perPage, pages := page(r, all)
+	writeJSON(w, http.StatusOK, civogo.PaginatedDatabases{Page: current, PerPage: perPage, Pages: pages, Items: items})
+}
+
+func (s *Server) getDatabase(w http.ResponseWriter, r *http.Request) {
+	db, ok := s.database(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_database_not_found", "database", r.PathValue("id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.databaseView(db))
+}
+
+func (s *Server) createDatabase(w http.ResponseWriter, r *http.Request) {
+	var req civogo.CreateDatabaseRequest
+	if err := decode(r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+
+	size, ok := s.findSize(req.Size)
+	if !ok || size.Type != "Database" {
+		writeError(w, http.StatusBadRequest, "database_size_not_found", fmt.Sprintf("the size %s could not be found", req.Size))
+		return
+	}
+
+	software := strings.ToLower(req.Software)
+	versions, ok := s.databaseEngine[software]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "parameter_invalid", fmt.Sprintf("the software %s is not supported", req.Software))
+		return
+	}
+	version := req.SoftwareVersion
+	if version == "" {
+		for _, v := range versions {
+			if v.Default {
+				version = v.SoftwareVersion
+			}
+		}
+	}
+	validVersion := false
+	for _, v := range versions {
+		if v.SoftwareVersion == version {
+			validVersion = true
+		}
+	}
+	if !validVersion {
+		writeError(w, http.StatusBadRequest, "parameter_invalid", fmt.Sprintf("the version %s of %s is not supported", version, req.Software))
+		return
+	}
+
+	rg := region(r)
+	for _, db := range s.databases {
+		if db.region == rg && db.Name == req.Name {
+			writeError(w, http.StatusConflict, "database_database_duplicate_name", fmt.Sprintf("a database named %s already exists", req.Name))
+			return
+		}
+	}
+
+	networkID := req.NetworkID
+	if networkID == "" {
+		networkID = s.defaultNetwork(rg).ID
+	} else if _, ok := s.network(r, networkID); !ok {
+		notFound(w, "database_network_not_found", "network", networkID)
+		return
+	}
+
+	port, username := 3306, "root"
+	if software == "postgresql" {
+		port, username = 5432, "postgres"
+	}
+
+	firewallID := req.FirewallID
+	if firewallID == "" {
+		f := s.newFirewall(rg, fmt.Sprintf("%s-database", req.Name), networkID, false)
+		rule := req.FirewallRules
+		if rule == "" {
+			rule = fmt.Sprint(port)
+		}
+		f.addRule(civogo.FirewallRule{Protocol: "tcp", Ports: rule, Cidr: []string{"0.0.0.0/0"}, Label: "Database access"})
+		firewallID = f.ID
+	} else if _, ok := s.firewall(r, firewallID); !ok {
+		notFound(w, "database_firewall_not_found", "firewall", firewallID)
+		return
+	}
+
+	nodes := req.Nodes
+	if nodes == 0 {
+		nodes = 1
+	}
+	password := strings.ReplaceAll(newID(), "-", "")[:24]
+
+	db := &database{
+		Database: civogo.Database{
+			ID:              newID(),
+			Name:            req.Name,
+			Nodes:           nodes,
+			Size:            size.Name,
+			Software:        req.Software,
+			SoftwareVersion: version,
+			PublicIPv4:      s.publicAddress(),
+			PrivateIPv4:     s.privateAddress(networkID),
+			NetworkID:       networkID,
+			FirewallID:      firewallID,
+			Port:            port,
+			Username:        username,
+			Password:        password,
+			DatabaseUserInfo: []civogo.DatabaseUserInfo{
+				{Username: username, Password: password, Port: port},
+			},
+			Status: "Ready",
+		},
+		region: rg,
+	}
+	db.DNSEntry = fmt.Sprintf("%s.db.civo.com", db.ID)
+	s.databases[db.ID] = db
+
+	s.markPending(db.ID, "Pending")
+	writeJSON(w, http.StatusOK, s.databaseView(db))
+}
+
+func (s *Server) updateDatabase(w http.ResponseWriter, r *http.Request) {
+	db, ok := s.database(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_database_not_found", "database", r.PathValue("id"))
+		return
+	}
+
+	// the size and the version are sent by the provider, civogo doesn't have them
+	var req struct {
+		civogo.UpdateDatabaseRequest
+		Size            string `json:"size"`
+		SoftwareVersion string `json:"software_version"`
+	}
+	if err := decode(r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if req.Size != "" {
+		size, ok := s.findSize(req.Size)
+		if !ok || size.Type != "Database" {
+			writeError(w, http.StatusBadRequest, "database_size_not_found", fmt.Sprintf("the size %s could not be found", req.Size))
+			return
+		}
+		if current, _ := s.findSize(db.Size); size.DiskGigabytes < current.DiskGigabytes {
+			writeError(w, http.StatusBadRequest, "parameter_invalid", "the disk of a database cannot be shrunk")
+			return
+		}
+		db.Size = size.Name
+	}
+	if req.SoftwareVersion != "" {
+		valid := false
+		for _, v := range s.databaseEngine[strings.ToLower(db.Software)] {
+			valid = valid || v.SoftwareVersion == req.SoftwareVersion
+		}
+		if !valid {
+			writeError(w, http.StatusBadRequest, "parameter_invalid", fmt.Sprintf("the version %s of %s is not supported", req.SoftwareVersion, db.Software))
+			return
+		}
+		db.SoftwareVersion = req.SoftwareVersion
+	}
+	if req.Name != "" {
+		db.Name = req.Name
+	}
+	if req.Nodes != nil {
+		if *req.Nodes < 1 {
+			writeError(w, http.StatusBadRequest, "parameter_invalid", "a database needs at least one node")
+			return
+		}
+		db.Nodes = *req.Nodes
+	}
+	if req.FirewallID != "" {
+		if _, ok := s.firewall(r, req.FirewallID); !ok {
+			notFound(w, "database_firewall_not_found", "firewall", req.FirewallID)
+			return
+		}
+		db.FirewallID = req.FirewallID
+	}
+
+	s.markPending(db.ID, "Pending")
+	writeJSON(w, http.StatusOK, s.databaseView(db))
+}
+
+func (s *Server) deleteDatabase(w http.ResponseWriter, r *http.Request) {
+	db, ok := s.database(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_database_not_found", "database", r.PathValue("id"))
+		return
+	}
+	delete(s.databases, db.ID)
+	delete(s.pending, db.ID)
+	writeSuccess(w, db.ID)
+}
+
+func (s *Server) restoreDatabase(w http.ResponseWriter, r *http.Request) {
+	db, ok := s.database(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_database_not_found", "database", r.PathValue("id"))
+		return
+	}
+
+	var req civogo.RestoreDatabaseRequest
+	if err := decode(r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	// the backup can also be one of another database of the region with the same software
+	backup := db.backup(req.Backup)
+	for _, other := range s.databases {
+		if backup == nil && other.region == db.region {
+			backup = other.backup(req.Backup)
+		}
+	}
+	if backup == nil {
+		notFound(w, "database_backup_not_found", "backup", req.Backup)
+		return
+	}
+	if !strings.EqualFold(backup.Software, db.Software) {
+		writeError(w, http.StatusBadRequest, "parameter_invalid", fmt.Sprintf("a %s backup can't be restored into a %s database", backup.Software, db.Software))
+		return
+	}
+
+	s.markPending(db.ID, "Restoring")
+	writeSuccess(w, db.ID)
+}
+
+// backup returns the backup matching the ID or name, or nil
+func (db *database) backup(search string) *civogo.DatabaseBackup {
+	for _, b := range db.backups {
+		if b.ID == search || b.Name == search {
+			return b
+		}
+	}
+	return nil
+}
+
+func (s *Server) listDatabaseBackups(w http.ResponseWriter, r *http.Request) {
+	db, ok := s.database(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_database_not_found", "database", r.PathValue("id"))
+		return
+	}
+
+	all := []civogo.DatabaseBackup{}
+	for _, b := range db.backups {
+		backup := *b
+		backup.Status = s.status(b.ID, b.Status)
+		all = append(all, backup)
+	}
+
+	items, current, perPage, pages := page(r, all)
+	writeJSON(w, http.StatusOK, civogo.PaginatedDatabaseBackup{Page: current, PerPage: perPage, Pages: pages, Items: items})
+}
+
+func (s *Server) getDatabaseBackup(w http.ResponseWriter, r *http.Request) {
+	db, ok := s.database(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_database_not_found", "database", r.PathValue("id"))
+		return
+	}
+	b := db.backup(r.PathValue("backup"))
+	if b == nil {
+		notFound(w, "database_backup_not_found", "backup", r.PathValue("backup"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.backupView(db, b))
+}
+
+func (s *Server) createDatabaseBackup(w http.ResponseWriter, r *http.Request) {
+	db, ok := s.database(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_database_not_found", "database", r.PathValue("id"))
+		return
+	}
+
+	var req struct {
+		civogo.DatabaseBackupCreateRequest
+		Count int `json:"count"`
+	}
+	if err := decode(r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if db.backup(req.Name) != nil {
+		writeError(w, http.StatusConflict, "database_backup_duplicate_name", fmt.Sprintf("a backup named %s already exists", req.Name))
+		return
+	}
+
+	b := &civogo.DatabaseBackup{
+		ID:           newID(),
+		Name:         req.Name,
+		Software:     db.Software,
+		Status:       "COMPLETED",
+		Schedule:     req.Schedule,
+		DatabaseName: db.Name,
+		DatabaseID:   db.ID,
+		IsScheduled:  req.Schedule != "",
+		CreatedAt:    time.Now().UTC(),
+	}
+	db.backups = append(db.backups, b)
+	if b.IsScheduled {
+		db.backupCount = req.Count
+	}
+
+	view := s.backupView(db, b)
+	s.markPending(b.ID, "PENDING")
+	writeJSON(w, http.StatusOK, view)
+}
+
+func (s *Server) updateDatabaseBackup(w http.ResponseWriter, r *http.Request) {
+	db, ok := s.database(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_database_not_found", "database", r.PathValue("id"))
+		return
+	}
+
+	var req struct {
+		civogo.DatabaseBackupUpdateRequest
+		Count int `json:"count"`
+	}
+	if err := decode(r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+
+	for _, b := range db.backups {
+		if b.IsScheduled {
+			if req.Name != "" {
+				b.Name = req.Name
+			}
+			if req.Schedule != "" {
+				b.Schedule = req.Schedule
+			}
+			if req.Count != 0 {
+				db.backupCount = req.Count
+			}
+			writeJSON(w, http.StatusOK, s.backupView(db, b))
+			return
+		}
+	}
+	notFound(w, "database_backup_not_found", "scheduled backup", db.ID)
+}
+
+func (s *Server) deleteDatabaseBackup(w http.ResponseWriter, r *http.Request) {
+	db, ok := s.database(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_database_not_found", "database", r.PathValue("id"))
+		return
+	}
+	for i, b := range db.backups {
+		if b.ID == r.PathValue("backup") {
+			db.backups = append(db.backups[:i], db.backups[i+1:]...)
+			writeSuccess(w, b.ID)
+			return
+		}
+	}
+	notFound(w, "database_backup_not_found", "backup", r.PathValue("backup"))
+}
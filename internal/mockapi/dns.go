@@ -0,0 +1,192 @@
+package mockapi
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/civo/civogo"
+)
+
+const accountID = "mock-account"
+
+func (s *Server) registerDNS(mux *http.ServeMux) {
+	mux.HandleFunc("GET /v2/dns", s.listDomains)
+	mux.HandleFunc("POST /v2/dns", s.createDomain)
+	mux.HandleFunc("PUT /v2/dns/{id}", s.updateDomain)
+	mux.HandleFunc("DELETE /v2/dns/{id}", s.deleteDomain)
+	mux.HandleFunc("GET /v2/dns/{id}/records", s.listRecords)
+	mux.HandleFunc("POST /v2/dns/{id}/records", s.createRecord)
+	mux.HandleFunc("PUT /v2/dns/{id}/records/{record}", s.updateRecord)
+	mux.HandleFunc("DELETE /v2/dns/{id}/records/{record}", s.deleteRecord)
+}
+
+func (s *Server) listDomains(w http.ResponseWriter, _ *http.Request) {
+	result := []civogo.DNSDomain{}
+	for _, d := range s.domains {
+		result = append(result, *d)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) createDomain(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := decode(r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	for _, d := range s.domains {
+		if d.Name == req.Name {
+			writeError(w, http.StatusConflict, "database_dns_domain_duplicate_name", fmt.Sprintf("the domain %s already exists", req.Name))
+			return
+		}
+	}
+
+	d := &civogo.DNSDomain{ID: newID(), AccountID: accountID, Name: req.Name}
+	s.domains[d.ID] = d
+	writeJSON(w, http.StatusOK, d)
+}
+
+func (s *Server) updateDomain(w http.ResponseWriter, r *http.Request) {
+	d, ok := s.domains[r.PathValue("id")]
+	if !ok {
+		notFound(w, "database_dns_domain_not_found", "domain", r.PathValue("id"))
+		return
+	}
+
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := decode(r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if req.Name != "" {
+		d.Name = req.Name
+	}
+	writeJSON(w, http.StatusOK, d)
+}
+
+func (s *Server) deleteDomain(w http.ResponseWriter, r *http.Request) {
+	d, ok := s.domains[r.PathValue("id")]
+	if !ok {
+		notFound(w, "database_dns_domain_not_found", "domain", r.PathValue("id"))
+		return
+	}
+	for id, record := range s.records {
+		if record.DNSDomainID == d.ID {
+			delete(s.records, id)
+		}
+	}
+	delete(s.domains, d.ID)
+	writeSuccess(w, d.ID)
+}
+
+func (s *Server) listRecords(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.domains[r.PathValue("id")]; !ok {
+		notFound(w, "database_dns_domain_not_found", "domain", r.PathValue("id"))
+		return
+	}
+
+	result := []civogo.DNSRecord{}
+	for _, record := range s.records {
+		if record.DNSDomainID == r.PathValue("id") {
+			result = append(result, *record)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	writeJSON(w, http.StatusOK, result)
+}
+
+// validRecordType reports whether the API accepts the record type
+func validRecordType(t civogo.DNSRecordType) bool {
+	switch t {
+	case civogo.DNSRecordTypeA, civogo.DNSRecordTypeCName, civogo.DNSRecordTypeMX,
+		civogo.DNSRecordTypeSRV, civogo.DNSRecordTypeTXT, civogo.DNSRecordTypeNS, "AAAA":
+		return true
+	}
+	return false
+}
+
+func (s *Server) createRecord(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.domains[r.PathValue("id")]; !ok {
+		notFound(w, "database_dns_domain_not_found", "domain", r.PathValue("id"))
+		return
+	}
+
+	var req civogo.DNSRecordConfig
+	if err := decode(r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if !validRecordType(req.Type) {
+		writeError(w, http.StatusBadRequest, "parameter_dns_record_type", fmt.Sprintf("the record type %s is not valid", req.Type))
+		return
+	}
+	if req.TTL == 0 {
+		req.TTL = 600
+	}
+
+	now := time.Now().UTC()
+	record := &civogo.DNSRecord{
+		ID:          newID(),
+		AccountID:   accountID,
+		DNSDomainID: r.PathValue("id"),
+		Name:        req.Name,
+		Value:       req.Value,
+		Type:        req.Type,
+		Priority:    req.Priority,
+		TTL:         req.TTL,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	s.records[record.ID] = record
+	writeJSON(w, http.StatusOK, record)
+}
+
+func (s *Server) updateRecord(w http.ResponseWriter, r *http.Request) {
+	record, ok := s.records[r.PathValue("record")]
+	if !ok || record.DNSDomainID != r.PathValue("id") {
+		notFound(w, "database_dns_record_not_found", "record", r.PathValue("record"))
+		return
+	}
+
+	var req civogo.DNSRecordConfig
+	if err := decode(r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if req.Type != "" {
+		if !validRecordType(req.Type) {
+			writeError(w, http.StatusBadRequest, "parameter_dns_record_type", fmt.Sprintf("the record type %s is not valid", req.Type))
+			return
+		}
+		record.Type = req.Type
+	}
+	if req.Name != "" {
+		record.Name = req.Name
+	}
+	if req.Value != "" {
+		record.Value = req.Value
+	}
+	if req.TTL != 0 {
+		record.TTL = req.TTL
+	}
+	record.Priority = req.Priority
+	record.UpdatedAt = time.Now().UTC()
+	writeJSON(w, http.StatusOK, record)
+}
+
+func (s *Server) deleteRecord(w http.ResponseWriter, r *http.Request) {
+	record, ok := s.records[r.PathValue("record")]
+	if !ok || record.DNSDomainID != r.PathValue("id") {
+		notFound(w, "database_dns_record_not_found", "record", r.PathValue("record"))
+		return
+	}
+	delete(s.records, record.ID)
+	writeSuccess(w, record.ID)
+}
@@ -0,0 +1,108 @@
+package mockapi
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Fault describes a delay or an error response injected into matching requests
+type Fault struct {
+	// Method matches the request method, empty matches every method
+	Method string
+	// Path matches requests whose path starts with it, empty matches every path
+	Path string
+
+	// Delay is slept before the request is answered
+	Delay time.Duration
+	// Status is the HTTP status returned instead of calling the handler, when
+	// zero the request is passed to the handler after the delay
+	Status int
+	// Code is the Civo error code put in the response body, e.g.
+	// database_instance_not_found, it defaults to one matching Status
+	Code string
+	// RetryAfter sets the Retry-After header in seconds when non-zero
+	RetryAfter int
+
+	// Count is how many requests the fault applies to, zero means every request
+	Count int
+
+	hits int
+}
+
+// Inject adds a fault, faults are matched in the order they were injected
+func (s *Server) Inject(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes every injected fault
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// matchFault returns the first active fault matching the request and counts
+// the hit, the caller must hold the lock
+func (s *Server) matchFault(r *http.Request) *Fault {
+	for i, f := range s.faults {
+		if f.Method != "" && !strings.EqualFold(f.Method, r.Method) {
+			continue
+		}
+		if f.Path != "" && !strings.HasPrefix(r.URL.Path, f.Path) {
+			continue
+		}
+
+		f.hits++
+		if f.Count > 0 && f.hits >= f.Count {
+			s.faults = append(s.faults[:i], s.faults[i+1:]...)
+		}
+		return f
+	}
+	return nil
+}
+
+// serve applies the fault and reports whether it wrote the response
+func (f *Fault) serve(w http.ResponseWriter, r *http.Request) bool {
+	if f.Delay > 0 {
+		select {
+		case <-time.After(f.Delay):
+		case <-r.Context().Done():
+			return true
+		}
+	}
+
+	if f.Status == 0 {
+		return false
+	}
+
+	if f.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(f.RetryAfter))
+	}
+
+	code := f.Code
+	if code == "" {
+		code = defaultCode(f.Status)
+	}
+	writeError(w, f.Status, code, http.StatusText(f.Status))
+	return true
+}
+
+// defaultCode returns the Civo error code the API uses for a status
+func defaultCode(status int) string {
+	switch {
+	case status == http.StatusUnauthorized:
+		return "database_account_not_found"
+	case status == http.StatusNotFound:
+		return "database_instance_not_found"
+	case status == http.StatusTooManyRequests:
+		return "too_many_requests"
+	case status >= 500:
+		return "internal_server_error"
+	default:
+		return "parameter_invalid"
+	}
+}
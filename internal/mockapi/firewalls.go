@@ -0,0 +1,263 @@
+package mockapi
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/civo/civogo"
+)
+
+func (s *Server) registerFirewalls(mux *http.ServeMux) {
+	for _, prefix := range []string{"/v2/firewalls", "/v2/vpc/firewalls"} {
+		mux.HandleFunc("GET "+prefix, s.listFirewalls)
+		mux.HandleFunc("POST "+prefix, s.createFirewall)
+		mux.HandleFunc("GET "+prefix+"/{id}", s.getFirewall)
+		mux.HandleFunc("PUT "+prefix+"/{id}", s.updateFirewall)
+		mux.HandleFunc("DELETE "+prefix+"/{id}", s.deleteFirewall)
+		mux.HandleFunc("GET "+prefix+"/{id}/rules", s.listFirewallRules)
+		mux.HandleFunc("POST "+prefix+"/{id}/rules", s.createFirewallRule)
+		mux.HandleFunc("DELETE "+prefix+"/{id}/rules/{rule}", s.deleteFirewallRule)
+	}
+}
+
+// newFirewall stores a firewall, optionally with the rules the API adds by default
+func (s *Server) newFirewall(region, name, networkID string, defaultRules bool) *firewall {
+	f := &firewall{
+		Firewall: civogo.Firewall{
+			ID:        newID(),
+			Name:      name,
+			NetworkID: networkID,
+			Rules:     []civogo.FirewallRule{},
+		},
+		region: region,
+	}
+	if defaultRules {
+		for _, rule := range []civogo.FirewallRule{
+			{Protocol: "tcp", StartPort: "1", EndPort: "65535", Direction: "ingress", Label: "All TCP ports open"},
+			{Protocol: "udp", StartPort: "1", EndPort: "65535", Direction: "ingress", Label: "All UDP ports open"},
+			{Protocol: "icmp", Direction: "ingress", Label: "Ping/traceroute"},
+			{Protocol: "tcp", StartPort: "1", EndPort: "65535", Direction: "egress", Label: "All TCP ports open"},
+			{Protocol: "udp", StartPort: "1", EndPort: "65535", Direction: "egress", Label: "All UDP ports open"},
+			{Protocol: "icmp", Direction: "egress", Label: "Ping/traceroute"},
+		} {
+			rule.Cidr = []string{"0.0.0.0/0"}
+			rule.Action = "allow"
+			f.addRule(rule)
+		}
+	}
+	s.firewalls[f.ID] = f
+	return f
+}
+
+// addRule normalises the ports of the rule and appends it to the firewall
+func (f *firewall) addRule(rule civogo.FirewallRule) civogo.FirewallRule {
+	rule.ID = newID()
+	rule.FirewallID = f.ID
+	if rule.Action == "" {
+		rule.Action = "allow"
+	}
+	if rule.Direction == "" {
+		rule.Direction = "ingress"
+	}
+
+	switch {
+	case rule.Ports != "":
+		parts := strings.SplitN(rule.Ports, "-", 2)
+		rule.StartPort = parts[0]
+		rule.EndPort = parts[len(parts)-1]
+	case rule.StartPort != "" && rule.EndPort != "" && rule.StartPort != rule.EndPort:
+		rule.Ports = rule.StartPort + "-" + rule.EndPort
+	case rule.StartPort != "":
+		rule.EndPort = rule.StartPort
+		rule.Ports = rule.StartPort
+	}
+
+	f.Rules = append(f.Rules, rule)
+	f.RulesCount = len(f.Rules)
+	return rule
+}
+
+// firewall returns the firewall visible to the request
+func (s *Server) firewall(r *http.Request, id string) (*firewall, bool) {
+	f, ok := s.firewalls[id]
+	if !ok || !inRegion(r, f.region) {
+		return nil, false
+	}
+	return f, true
+}
+
+// withCounts returns the firewall along with how many objects use it
+func (s *Server) withCounts(f *firewall) civogo.Firewall {
+	result := f.Firewall
+	result.InstanceCount, result.ClusterCount, result.LoadBalancerCount = 0, 0, 0
+	for _, i := range s.instances {
+		if i.FirewallID == f.ID {
+			result.InstanceCount++
+		}
+	}
+	for _, c := range s.clusters {
+		if c.FirewallID == f.ID {
+			result.ClusterCount++
+		}
+	}
+	for _, lb := range s.loadBalancers {
+		if lb.FirewallID == f.ID {
+			result.LoadBalancerCount++
+		}
+	}
+	return result
+}
+
+func (s *Server) listFirewalls(w http.ResponseWriter, r *http.Request) {
+	s.defaultNetwork(region(r))
+
+	result := []civogo.Firewall{}
+	for _, f := range s.firewalls {
+		if inRegion(r, f.region) {
+			result = append(result, s.withCounts(f))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) getFirewall(w http.ResponseWriter, r *http.Request) {
+	f, ok := s.firewall(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_firewall_not_found", "firewall", r.PathValue("id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.withCounts(f))
+}
+
+func (s *Server) createFirewall(w http.ResponseWriter, r *http.Request) {
+	var req civogo.FirewallConfig
+	if err := decode(r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, "parameter_name_invalid", "the name of the firewall is required")
+		return
+	}
+
+	rg := region(r)
+	networkID := req.NetworkID
+	if networkID == "" {
+		networkID = s.defaultNetwork(rg).ID
+	} else if _, ok := s.network(r, networkID); !ok {
+		notFound(w, "database_network_not_found", "network", networkID)
+		return
+	}
+
+	for _, f := range s.firewalls {
+		if f.region == rg && f.Name == req.Name {
+			writeError(w, http.StatusConflict, "database_firewall_duplicate_name", fmt.Sprintf("a firewall named %s already exists", req.Name))
+			return
+		}
+	}
+
+	f := s.newFirewall(rg, req.Name, networkID, req.CreateRules == nil || *req.CreateRules)
+	for _, rule := range req.Rules {
+		f.addRule(rule)
+	}
+
+	writeJSON(w, http.StatusOK, civogo.FirewallResult{ID: f.ID, Name: f.Name, Result: civogo.ResultSuccess})
+}
+
+func (s *Server) updateFirewall(w http.ResponseWriter, r *http.Request) {
+	f, ok := s.firewall(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_firewall_not_found", "firewall", r.PathValue("id"))
+		return
+	}
+
+	var req civogo.FirewallConfig
+	if err := decode(r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if req.Name != "" {
+		f.Name = req.Name
+	}
+	writeSuccess(w, f.ID)
+}
+
+func (s *Server) deleteFirewall(w http.ResponseWriter, r *http.Request) {
+	f, ok := s.firewall(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_firewall_not_found", "firewall", r.PathValue("id"))
+		return
+	}
+
+	counts := s.withCounts(f)
+	if counts.InstanceCount+counts.ClusterCount+counts.LoadBalancerCount > 0 {
+		writeError(w, http.StatusConflict, "database_firewall_delete_failed", "the firewall is still in use")
+		return
+	}
+
+	delete(s.firewalls, f.ID)
+	writeSuccess(w, f.ID)
+}
+
+func (s *Server) listFirewallRules(w http.ResponseWriter, r *http.Request) {
+	f, ok := s.firewall(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_firewall_not_found", "firewall", r.PathValue("id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, f.Rules)
+}
+
+func (s *Server) createFirewallRule(w http.ResponseWriter, r *http.Request) {
+	f, ok := s.firewall(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_firewall_not_found", "firewall", r.PathValue("id"))
+		return
+	}
+
+	var req civogo.FirewallRuleConfig
+	if err := decode(r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if req.Protocol == "" {
+		req.Protocol = "tcp"
+	}
+	if req.Protocol != "icmp" && req.StartPort == "" && req.Ports == "" {
+		writeError(w, http.StatusBadRequest, "parameter_start_port_missing", "the start port of the rule is required")
+		return
+	}
+
+	rule := f.addRule(civogo.FirewallRule{
+		Protocol:  req.Protocol,
+		StartPort: req.StartPort,
+		EndPort:   req.EndPort,
+		Ports:     req.Ports,
+		Cidr:      req.Cidr,
+		Direction: req.Direction,
+		Action:    req.Action,
+		Label:     req.Label,
+	})
+	writeJSON(w, http.StatusOK, rule)
+}
+
+func (s *Server) deleteFirewallRule(w http.ResponseWriter, r *http.Request) {
+	f, ok := s.firewall(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_firewall_not_found", "firewall", r.PathValue("id"))
+		return
+	}
+
+	for i, rule := range f.Rules {
+		if rule.ID == r.PathValue("rule") {
+			f.Rules = append(f.Rules[:i], f.Rules[i+1:]...)
+			f.RulesCount = len(f.Rules)
+			writeSuccess(w, rule.ID)
+			return
+		}
+	}
+	notFound(w, "database_firewall_rules_find", "firewall rule", r.PathValue("rule"))
+}
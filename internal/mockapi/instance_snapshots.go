@@ -0,0 +1,241 @@
+package mockapi
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/civo/civogo"
+)
+
+type instanceSnapshot struct {
+	civogo.InstanceSnapshot
+	instanceID string
+	region     string
+
+	// diskGigabytes is the disk size of the instance the snapshot was taken
+	// of, instances built from it can't be smaller
+	diskGigabytes int
+}
+
+type snapshotSchedule struct {
+	civogo.SnapshotSchedule
+	region string
+}
+
+func (s *Server) registerInstanceSnapshots(mux *http.ServeMux) {
+	mux.HandleFunc("GET /v2/instances/{id}/snapshots", s.listInstanceSnapshots)
+	mux.HandleFunc("POST /v2/instances/{id}/snapshots", s.createInstanceSnapshot)
+	mux.HandleFunc("GET /v2/instances/{id}/snapshots/{snapshot}", s.getInstanceSnapshot)
+	mux.HandleFunc("PUT /v2/instances/{id}/snapshots/{snapshot}", s.updateInstanceSnapshot)
+	mux.HandleFunc("DELETE /v2/instances/{id}/snapshots/{snapshot}", s.deleteInstanceSnapshot)
+	mux.HandleFunc("GET /v2/resourcesnapshotschedules", s.listSnapshotSchedules)
+	mux.HandleFunc("POST /v2/resourcesnapshotschedules", s.createSnapshotSchedule)
+	mux.HandleFunc("GET /v2/resourcesnapshotschedules/{id}", s.getSnapshotSchedule)
+	mux.HandleFunc("PUT /v2/resourcesnapshotschedules/{id}", s.updateSnapshotSchedule)
+	mux.HandleFunc("DELETE /v2/resourcesnapshotschedules/{id}", s.deleteSnapshotSchedule)
+}
+
+// instanceSnapshot returns the snapshot of the instance visible to the request,
+// snapshots outlive their instance
+func (s *Server) instanceSnapshot(r *http.Request, instanceID, id string) (*instanceSnapshot, bool) {
+	snap, ok := s.instanceSnaps[id]
+	if !ok || snap.instanceID != instanceID || !inRegion(r, snap.region) {
+		return nil, false
+	}
+	return snap, true
+}
+
+func (s *Server) instanceSnapshotView(snap *instanceSnapshot) civogo.InstanceSnapshot {
+	result := snap.InstanceSnapshot
+	result.Status.State = s.status(snap.ID, snap.Status.State)
+	return result
+}
+
+func (s *Server) listInstanceSnapshots(w http.ResponseWriter, r *http.Request) {
+	result := []civogo.InstanceSnapshot{}
+	for _, snap := range s.instanceSnaps {
+		if snap.instanceID == r.PathValue("id") && inRegion(r, snap.region) {
+			result = append(result, s.instanceSnapshotView(snap))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) getInstanceSnapshot(w http.ResponseWriter, r *http.Request) {
+	snap, ok := s.instanceSnapshot(r, r.PathValue("id"), r.PathValue("snapshot"))
+	if !ok {
+		notFound(w, "database_snapshot_not_found", "snapshot", r.PathValue("snapshot"))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.instanceSnapshotView(snap))
+}
+
+func (s *Server) createInstanceSnapshot(w http.ResponseWriter, r *http.Request) {
+	i, ok := s.instance(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_instance_find", "instance", r.PathValue("id"))
+		return
+	}
+
+	var req civogo.CreateInstanceSnapshotParams
+	if err := decode(r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	for _, snap := range s.instanceSnaps {
+		if snap.instanceID == i.ID && snap.Name == req.Name {
+			writeError(w, http.StatusConflict, "database_snapshot_duplicate_name", fmt.Sprintf("a snapshot named %s already exists", req.Name))
+			return
+		}
+	}
+
+	snap := s.newInstanceSnapshot(i, req.Name, req.Description)
+	writeJSON(w, http.StatusOK, s.instanceSnapshotView(snap))
+}
+
+// newInstanceSnapshot stores a snapshot of the instance, including its attached volumes
+func (s *Server) newInstanceSnapshot(i *civogo.Instance, name, description string) *instanceSnapshot {
+	snap := &instanceSnapshot{
+		InstanceSnapshot: civogo.InstanceSnapshot{
+			ID:          newID(),
+			Name:        name,
+			Description: description,
+			Status:      civogo.InstanceSnapshotStatus{State: "ready"},
+			CreatedAt:   time.Now().UTC(),
+		},
+		instanceID:    i.ID,
+		region:        i.Region,
+		diskGigabytes: i.DiskGigabytes,
+	}
+	for _, v := range s.volumes {
+		if v.InstanceID == i.ID {
+			snap.IncludedVolumes = append(snap.IncludedVolumes, v.ID)
+		}
+	}
+	s.instanceSnaps[snap.ID] = snap
+
+	s.markPending(snap.ID, "pending")
+	return snap
+}
+
+func (s *Server) updateInstanceSnapshot(w http.ResponseWriter, r *http.Request) {
+	snap, ok := s.instanceSnapshot(r, r.PathValue("id"), r.PathValue("snapshot"))
+	if !ok {
+		notFound(w, "database_snapshot_not_found", "snapshot", r.PathValue("snapshot"))
+		return
+	}
+
+	var req civogo.UpdateInstanceSnapshotParams
+	if err := decode(r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if req.Name != "" {
+		snap.Name = req.Name
+	}
+	snap.Description = req.Description
+	writeJSON(w, http.StatusOK, s.instanceSnapshotView(snap))
+}
+
+func (s *Server) deleteInstanceSnapshot(w http.ResponseWriter, r *http.Request) {
+	snap, ok := s.instanceSnapshot(r, r.PathValue("id"), r.PathValue("snapshot"))
+	if !ok {
+		notFound(w, "database_snapshot_not_found", "snapshot", r.PathValue("snapshot"))
+		return
+	}
+	delete(s.instanceSnaps, snap.ID)
+	delete(s.pending, snap.ID)
+	writeSuccess(w, snap.ID)
+}
+
+func (s *Server) listSnapshotSchedules(w http.ResponseWriter, r *http.Request) {
+	result := []civogo.SnapshotSchedule{}
+	for _, schedule := range s.schedules {
+		if inRegion(r, schedule.region) {
+			result = append(result, schedule.SnapshotSchedule)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) getSnapshotSchedule(w http.ResponseWriter, r *http.Request) {
+	schedule, ok := s.schedules[r.PathValue("id")]
+	if !ok || !inRegion(r, schedule.region) {
+		notFound(w, "database_snapshot_not_found", "snapshot schedule", r.PathValue("id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, schedule.SnapshotSchedule)
+}
+
+func (s *Server) createSnapshotSchedule(w http.ResponseWriter, r *http.Request) {
+	var req civogo.CreateSnapshotScheduleRequest
+	if err := decode(r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if req.CronExpression == "" {
+		writeError(w, http.StatusBadRequest, "parameter_invalid", "the cron expression is required")
+		return
+	}
+
+	schedule := &snapshotSchedule{
+		SnapshotSchedule: civogo.SnapshotSchedule{
+			ID:             newID(),
+			Name:           req.Name,
+			Description:    req.Description,
+			CronExpression: req.CronExpression,
+			Retention:      req.Retention,
+			Status:         civogo.SnapshotScheduleStatus{State: "active"},
+			CreatedAt:      time.Now().UTC(),
+		},
+		region: region(r),
+	}
+	for _, target := range req.Instances {
+		i, ok := s.instance(r, target.InstanceID)
+		if !ok {
+			notFound(w, "database_instance_find", "instance", target.InstanceID)
+			return
+		}
+		schedule.Instances = append(schedule.Instances, civogo.SnapshotInstance{ID: i.ID, Size: i.Size})
+	}
+	s.schedules[schedule.ID] = schedule
+	writeJSON(w, http.StatusOK, schedule.SnapshotSchedule)
+}
+
+func (s *Server) updateSnapshotSchedule(w http.ResponseWriter, r *http.Request) {
+	schedule, ok := s.schedules[r.PathValue("id")]
+	if !ok || !inRegion(r, schedule.region) {
+		notFound(w, "database_snapshot_not_found", "snapshot schedule", r.PathValue("id"))
+		return
+	}
+
+	var req civogo.UpdateSnapshotScheduleRequest
+	if err := decode(r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if req.Name != "" {
+		schedule.Name = req.Name
+	}
+	if req.Description != "" {
+		schedule.Description = req.Description
+	}
+	if req.Paused != nil {
+		schedule.Paused = *req.Paused
+	}
+	writeJSON(w, http.StatusOK, schedule.SnapshotSchedule)
+}
+
+func (s *Server) deleteSnapshotSchedule(w http.ResponseWriter, r *http.Request) {
+	schedule, ok := s.schedules[r.PathValue("id")]
+	if !ok || !inRegion(r, schedule.region) {
+		notFound(w, "database_snapshot_not_found", "snapshot schedule", r.PathValue("id"))
+		return
+	}
+	delete(s.schedules, schedule.ID)
+	writeSuccess(w, schedule.ID)
+}
@@ -0,0 +1,314 @@
+package mockapi
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/civo/civogo"
+)
+
+func (s *Server) registerInstances(mux *http.ServeMux) {
+	mux.HandleFunc("GET /v2/instances", s.listInstances)
+	mux.HandleFunc("POST /v2/instances", s.createInstance)
+	mux.HandleFunc("GET /v2/instances/{id}", s.getInstance)
+	mux.HandleFunc("PUT /v2/instances/{id}", s.updateInstance)
+	mux.HandleFunc("DELETE /v2/instances/{id}", s.deleteInstance)
+	mux.HandleFunc("PUT /v2/instances/{id}/tags", s.tagInstance)
+	mux.HandleFunc("PUT /v2/instances/{id}/firewall", s.setInstanceFirewall)
+	mux.HandleFunc("PUT /v2/instances/{id}/resize", s.resizeInstance)
+	mux.HandleFunc("PUT /v2/instances/{id}/stop", s.instanceAction("STOPPING", "SHUTOFF"))
+	mux.HandleFunc("PUT /v2/instances/{id}/start", s.instanceAction("STARTING", "ACTIVE"))
+	mux.HandleFunc("POST /v2/instances/{id}/hard_reboots", s.instanceAction("REBOOTING", "ACTIVE"))
+	mux.HandleFunc("POST /v2/instances/{id}/soft_reboots", s.instanceAction("REBOOTING", "ACTIVE"))
+}
+
+// instance returns the instance visible to the request
+func (s *Server) instance(r *http.Request, id string) (*civogo.Instance, bool) {
+	i, ok := s.instances[id]
+	if !ok || !inRegion(r, i.Region) {
+		return nil, false
+	}
+	return i, true
+}
+
+// instanceView returns a copy of the instance with its current status
+func (s *Server) instanceView(i *civogo.Instance) civogo.Instance {
+	result := *i
+	result.Status = s.status(i.ID, i.Status)
+	return result
+}
+
+func (s *Server) listInstances(w http.ResponseWriter, r *http.Request) {
+	all := []civogo.Instance{}
+	for _, i := range s.instances {
+		if inRegion(r, i.Region) {
+			all = append(all, s.instanceView(i))
+		}
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Hostname < all[j].Hostname })
+
+	items, current, perPage, pages := page(r, all)
+	writeJSON(w, http.StatusOK, civogo.PaginatedInstanceList{Page: current, PerPage: perPage, Pages: pages, Items: items})
+}
+
+func (s *Server) getInstance(w http.ResponseWriter, r *http.Request) {
+	i, ok := s.instance(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_instance_find", "instance", r.PathValue("id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.instanceView(i))
+}
+
+func (s *Server) createInstance(w http.ResponseWriter, r *http.Request) {
+	var req civogo.InstanceConfig
+	if err := decode(r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+
+	size, ok := s.findSize(req.Size)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "database_size_not_found", fmt.Sprintf("the size %s could not be found", req.Size))
+		return
+	}
+
+	sourceType, sourceID, snapshotID := "diskimage", "", ""
+	if req.SnapshotID != "" {
+		snap, ok := s.instanceSnaps[req.SnapshotID]
+		if !ok || !inRegion(r, snap.region) {
+			notFound(w, "database_snapshot_not_found", "snapshot", req.SnapshotID)
+			return
+		}
+		if size.DiskGigabytes < snap.diskGigabytes {
+			writeError(w, http.StatusBadRequest, "database_instance_snapshot_too_big", fmt.Sprintf("the snapshot needs a disk of at least %dGB", snap.diskGigabytes))
+			return
+		}
+		sourceType, sourceID, snapshotID = "snapshot", snap.ID, snap.ID
+	} else {
+		var image *civogo.DiskImage
+		for k := range s.diskImages {
+			if s.diskImages[k].ID == req.TemplateID || s.diskImages[k].Name == req.TemplateID {
+				image = &s.diskImages[k]
+			}
+		}
+		if image == nil {
+			writeError(w, http.StatusBadRequest, "database_image_id_invalid", fmt.Sprintf("the disk image %s could not be found", req.TemplateID))
+			return
+		}
+		sourceID = image.Name
+	}
+
+	rg := region(r)
+	networkID := req.NetworkID
+	if networkID == "" {
+		networkID = s.defaultNetwork(rg).ID
+	} else if _, ok := s.network(r, networkID); !ok {
+		notFound(w, "database_network_not_found", "network", networkID)
+		return
+	}
+
+	firewallID := req.FirewallID
+	if firewallID == "" {
+		for _, f := range s.firewalls {
+			if f.NetworkID == networkID && f.region == rg {
+				firewallID = f.ID
+				break
+			}
+		}
+	} else if _, ok := s.firewall(r, firewallID); !ok {
+		notFound(w, "database_firewall_not_found", "firewall", firewallID)
+		return
+	}
+
+	for _, i := range s.instances {
+		if i.Region == rg && i.Hostname == req.Hostname {
+			writeError(w, http.StatusConflict, "database_instance_duplicate_name", fmt.Sprintf("an instance named %s already exists", req.Hostname))
+			return
+		}
+	}
+
+	user := req.InitialUser
+	if user == "" {
+		user = civogo.DefaultInstanceUser
+	}
+
+	i := &civogo.Instance{
+		ID:              newID(),
+		Hostname:        req.Hostname,
+		ReverseDNS:      req.ReverseDNS,
+		Size:            size.Name,
+		Region:          rg,
+		NetworkID:       networkID,
+		PrivateIP:       req.PrivateIPv4,
+		SourceType:      sourceType,
+		SourceID:        sourceID,
+		SnapshotID:      snapshotID,
+		InitialUser:     user,
+		InitialPassword: "mock-password",
+		SSHKeyID:        req.SSHKeyID,
+		Status:          "ACTIVE",
+		FirewallID:      firewallID,
+		Tags:            splitTags(req.TagsList),
+		Script:          req.Script,
+		CPUCores:        size.CPUCores,
+		RAMMegabytes:    size.RAMMegabytes,
+		DiskGigabytes:   size.DiskGigabytes,
+		VolumeType:      req.VolumeType,
+		CreatedAt:       time.Now().UTC(),
+	}
+	if i.PrivateIP == "" {
+		i.PrivateIP = s.privateAddress(networkID)
+	}
+	if req.PublicIPRequired != "none" && req.ReservedIPv4 == "" {
+		i.PublicIP = s.publicAddress()
+	}
+	s.instances[i.ID] = i
+
+	if req.ReservedIPv4 != "" {
+		for _, ip := range s.ips {
+			if ip.ID == req.ReservedIPv4 || ip.IP.IP == req.ReservedIPv4 {
+				s.assignIP(ip, i.ID, "instance")
+			}
+		}
+	}
+
+	s.markPending(i.ID, "BUILDING")
+	writeJSON(w, http.StatusOK, s.instanceView(i))
+}
+
+func (s *Server) updateInstance(w http.ResponseWriter, r *http.Request) {
+	i, ok := s.instance(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_instance_find", "instance", r.PathValue("id"))
+		return
+	}
+
+	var req struct {
+		Hostname    string `json:"hostname"`
+		ReverseDNS  string `json:"reverse_dns"`
+		Notes       string `json:"notes"`
+		NotesDelete string `json:"notes_delete"`
+	}
+	if err := decode(r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+
+	if req.Hostname != "" {
+		i.Hostname = req.Hostname
+	}
+	i.ReverseDNS = req.ReverseDNS
+	i.Notes = req.Notes
+	if req.NotesDelete == "true" {
+		i.Notes = ""
+	}
+	writeSuccess(w, i.ID)
+}
+
+func (s *Server) deleteInstance(w http.ResponseWriter, r *http.Request) {
+	i, ok := s.instance(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_instance_find", "instance", r.PathValue("id"))
+		return
+	}
+
+	for _, ip := range s.ips {
+		if ip.AssignedTo.ID == i.ID {
+			s.unassignIP(ip)
+		}
+	}
+	for _, v := range s.volumes {
+		if v.InstanceID == i.ID {
+			v.InstanceID = ""
+			v.Status = "available"
+		}
+	}
+
+	delete(s.instances, i.ID)
+	delete(s.pending, i.ID)
+	writeSuccess(w, i.ID)
+}
+
+func (s *Server) tagInstance(w http.ResponseWriter, r *http.Request) {
+	i, ok := s.instance(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_instance_find", "instance", r.PathValue("id"))
+		return
+	}
+
+	var req map[string]string
+	if err := decode(r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	i.Tags = splitTags(req["tags"])
+	writeSuccess(w, i.ID)
+}
+
+func (s *Server) setInstanceFirewall(w http.ResponseWriter, r *http.Request) {
+	i, ok := s.instance(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_instance_find", "instance", r.PathValue("id"))
+		return
+	}
+
+	var req map[string]string
+	if err := decode(r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if _, ok := s.firewall(r, req["firewall_id"]); !ok {
+		notFound(w, "database_firewall_not_found", "firewall", req["firewall_id"])
+		return
+	}
+	i.FirewallID = req["firewall_id"]
+	writeSuccess(w, i.ID)
+}
+
+func (s *Server) resizeInstance(w http.ResponseWriter, r *http.Request) {
+	i, ok := s.instance(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_instance_find", "instance", r.PathValue("id"))
+		return
+	}
+
+	var req map[string]string
+	if err := decode(r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	size, ok := s.findSize(req["size"])
+	if !ok {
+		writeError(w, http.StatusBadRequest, "database_size_not_found", fmt.Sprintf("the size %s could not be found", req["size"]))
+		return
+	}
+	if size.DiskGigabytes < i.DiskGigabytes {
+		writeError(w, http.StatusBadRequest, "openstack_instance_resize", "the disk of an instance cannot be shrunk")
+		return
+	}
+
+	i.Size = size.Name
+	i.CPUCores = size.CPUCores
+	i.RAMMegabytes = size.RAMMegabytes
+	i.DiskGigabytes = size.DiskGigabytes
+	s.markPending(i.ID, "REBOOTING")
+	writeSuccess(w, i.ID)
+}
+
+// instanceAction returns a handler moving the instance to finalStatus through
+// pendingStatus, used for start, stop and reboots
+func (s *Server) instanceAction(pendingStatus, finalStatus string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		i, ok := s.instance(r, r.PathValue("id"))
+		if !ok {
+			notFound(w, "database_instance_find", "instance", r.PathValue("id"))
+			return
+		}
+		i.Status = finalStatus
+		s.markPending(i.ID, pendingStatus)
+		writeSuccess(w, i.ID)
+	}
+}
@@ -0,0 +1,195 @@
+package mockapi
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/civo/civogo"
+)
+
+type reservedIP struct {
+	civogo.IP
+	region string
+}
+
+func (s *Server) registerIPs(mux *http.ServeMux) {
+	for _, prefix := range []string{"/v2/ips", "/v2/vpc/ips"} {
+		mux.HandleFunc("GET "+prefix, s.listIPs)
+		mux.HandleFunc("POST "+prefix, s.createIP)
+		mux.HandleFunc("GET "+prefix+"/{id}", s.getIP)
+		mux.HandleFunc("PUT "+prefix+"/{id}", s.updateIP)
+		mux.HandleFunc("DELETE "+prefix+"/{id}", s.deleteIP)
+		mux.HandleFunc("POST "+prefix+"/{id}/actions", s.ipAction)
+	}
+}
+
+// reservedIP returns the reserved IP visible to the request
+func (s *Server) reservedIP(r *http.Request, id string) (*reservedIP, bool) {
+	ip, ok := s.ips[id]
+	if !ok || !inRegion(r, ip.region) {
+		return nil, false
+	}
+	return ip, true
+}
+
+// assignIP points the reserved IP at an instance or load balancer
+func (s *Server) assignIP(ip *reservedIP, resourceID, resourceType string) bool {
+	switch resourceType {
+	case "instance":
+		i, ok := s.instances[resourceID]
+		if !ok {
+			return false
+		}
+		i.PublicIP = ip.IP.IP
+		i.ReservedIPID = ip.ID
+		i.ReservedIPName = ip.Name
+		i.ReservedIP = ip.IP.IP
+		ip.AssignedTo = civogo.AssignedTo{ID: i.ID, Type: resourceType, Name: i.Hostname}
+	case "loadbalancer":
+		lb, ok := s.loadBalancers[resourceID]
+		if !ok {
+			return false
+		}
+		lb.PublicIP = ip.IP.IP
+		lb.ReservedIPID = ip.ID
+		lb.ReservedIPName = ip.Name
+		lb.ReservedIP = ip.IP.IP
+		ip.AssignedTo = civogo.AssignedTo{ID: lb.ID, Type: resourceType, Name: lb.Name}
+	default:
+		return false
+	}
+	return true
+}
+
+// unassignIP detaches the reserved IP, the previous owner gets a new public IP
+func (s *Server) unassignIP(ip *reservedIP) {
+	if i, ok := s.instances[ip.AssignedTo.ID]; ok {
+		i.PublicIP = s.publicAddress()
+		i.ReservedIPID, i.ReservedIPName, i.ReservedIP = "", "", ""
+	}
+	if lb, ok := s.loadBalancers[ip.AssignedTo.ID]; ok {
+		lb.PublicIP = s.publicAddress()
+		lb.ReservedIPID, lb.ReservedIPName, lb.ReservedIP = "", "", ""
+	}
+	ip.AssignedTo = civogo.AssignedTo{}
+}
+
+// ipView returns the reserved IP, without an address while it is pending
+func (s *Server) ipView(ip *reservedIP) civogo.IP {
+	result := ip.IP
+	if s.status(ip.ID, "ACTIVE") != "ACTIVE" {
+		result.IP = ""
+	}
+	return result
+}
+
+func (s *Server) listIPs(w http.ResponseWriter, r *http.Request) {
+	all := []civogo.IP{}
+	for _, ip := range s.ips {
+		if inRegion(r, ip.region) {
+			all = append(all, s.ipView(ip))
+		}
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Name < all[j].Name })
+
+	items, current, perPage, pages := page(r, all)
+	writeJSON(w, http.StatusOK, civogo.PaginatedIPs{Page: current, PerPage: perPage, Pages: pages, Items: items})
+}
+
+func (s *Server) getIP(w http.ResponseWriter, r *http.Request) {
+	ip, ok := s.reservedIP(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_ip_find", "IP", r.PathValue("id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.ipView(ip))
+}
+
+func (s *Server) createIP(w http.ResponseWriter, r *http.Request) {
+	var req civogo.CreateIPRequest
+	if err := decode(r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+
+	ip := &reservedIP{
+		IP: civogo.IP{
+			ID:   newID(),
+			Name: req.Name,
+			IP:   s.publicAddress(),
+		},
+		region: region(r),
+	}
+	if ip.Name == "" {
+		ip.Name = ip.IP.IP
+	}
+	s.ips[ip.ID] = ip
+
+	s.markPending(ip.ID, "BUILDING")
+	writeJSON(w, http.StatusOK, s.ipView(ip))
+}
+
+func (s *Server) updateIP(w http.ResponseWriter, r *http.Request) {
+	ip, ok := s.reservedIP(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_ip_find", "IP", r.PathValue("id"))
+		return
+	}
+
+	var req civogo.UpdateIPRequest
+	if err := decode(r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if req.Name != "" {
+		ip.Name = req.Name
+	}
+	writeJSON(w, http.StatusOK, ip.IP)
+}
+
+func (s *Server) deleteIP(w http.ResponseWriter, r *http.Request) {
+	ip, ok := s.reservedIP(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_ip_find", "IP", r.PathValue("id"))
+		return
+	}
+	if ip.AssignedTo.ID != "" {
+		s.unassignIP(ip)
+	}
+	delete(s.ips, ip.ID)
+	writeSuccess(w, ip.ID)
+}
+
+func (s *Server) ipAction(w http.ResponseWriter, r *http.Request) {
+	ip, ok := s.reservedIP(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_ip_find", "IP", r.PathValue("id"))
+		return
+	}
+
+	var req civogo.Actions
+	if err := decode(r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+
+	switch req.Action {
+	case "assign":
+		if ip.AssignedTo.ID != "" && ip.AssignedTo.ID != req.AssignToID {
+			writeError(w, http.StatusConflict, "database_cannot_move_ip", "the IP is already assigned to another resource")
+			return
+		}
+		if !s.assignIP(ip, req.AssignToID, req.AssignToType) {
+			notFound(w, "database_instance_find", req.AssignToType, req.AssignToID)
+			return
+		}
+	case "unassign":
+		if ip.AssignedTo.ID != "" {
+			s.unassignIP(ip)
+		}
+	default:
+		writeError(w, http.StatusBadRequest, "parameter_invalid", "the action must be assign or unassign")
+		return
+	}
+	writeSuccess(w, ip.ID)
+}
@@ -0,0 +1,247 @@
+package mockapi
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/civo/civogo"
+)
+
+type loadBalancer struct {
+	civogo.LoadBalancer
+	region string
+}
+
+func (s *Server) registerLoadBalancers(mux *http.ServeMux) {
+	for _, prefix := range []string{"/v2/loadbalancers", "/v2/vpc/loadbalancers"} {
+		mux.HandleFunc("GET "+prefix, s.listLoadBalancers)
+		mux.HandleFunc("POST "+prefix, s.createLoadBalancer)
+		mux.HandleFunc("GET "+prefix+"/{id}", s.getLoadBalancer)
+		mux.HandleFunc("PUT "+prefix+"/{id}", s.updateLoadBalancer)
+		mux.HandleFunc("DELETE "+prefix+"/{id}", s.deleteLoadBalancer)
+	}
+}
+
+// loadBalancer returns the load balancer visible to the request
+func (s *Server) loadBalancer(r *http.Request, id string) (*loadBalancer, bool) {
+	lb, ok := s.loadBalancers[id]
+	if !ok || !inRegion(r, lb.region) {
+		return nil, false
+	}
+	return lb, true
+}
+
+func (s *Server) loadBalancerView(lb *loadBalancer) civogo.LoadBalancer {
+	result := lb.LoadBalancer
+	result.State = s.status(lb.ID, lb.State)
+	return result
+}
+
+// loadBalancerBackends converts the requested backends, defaulting the protocol
+func loadBalancerBackends(config []civogo.LoadBalancerBackendConfig) []civogo.LoadBalancerBackend {
+	backends := make([]civogo.LoadBalancerBackend, 0, len(config))
+	for _, b := range config {
+		protocol := strings.ToUpper(b.Protocol)
+		if protocol == "" {
+			protocol = "TCP"
+		}
+		backends = append(backends, civogo.LoadBalancerBackend{
+			IP:              b.IP,
+			Protocol:        protocol,
+			SourcePort:      b.SourcePort,
+			TargetPort:      b.TargetPort,
+			HealthCheckPort: b.HealthCheckPort,
+		})
+	}
+	return backends
+}
+
+// loadBalancerInstancePools converts the requested instance pools, defaulting the protocol
+func loadBalancerInstancePools(config []civogo.LoadBalancerInstancePoolConfig) []civogo.InstancePool {
+	pools := make([]civogo.InstancePool, 0, len(config))
+	for _, p := range config {
+		protocol := strings.ToUpper(p.Protocol)
+		if protocol == "" {
+			protocol = "TCP"
+		}
+		pools = append(pools, civogo.InstancePool{
+			Tags:        p.Tags,
+			Names:       p.Names,
+			Protocol:    protocol,
+			SourcePort:  p.SourcePort,
+			TargetPort:  p.TargetPort,
+			HealthCheck: p.HealthCheck,
+		})
+	}
+	return pools
+}
+
+func (s *Server) listLoadBalancers(w http.ResponseWriter, r *http.Request) {
+	result := []civogo.LoadBalancer{}
+	for _, lb := range s.loadBalancers {
+		if inRegion(r, lb.region) {
+			result = append(result, s.loadBalancerView(lb))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) getLoadBalancer(w http.ResponseWriter, r *http.Request) {
+	lb, ok := s.loadBalancer(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_loadbalancer_not_found", "load balancer", r.PathValue("id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.loadBalancerView(lb))
+}
+
+func (s *Server) createLoadBalancer(w http.ResponseWriter, r *http.Request) {
+	var req civogo.LoadBalancerConfig
+	if err := decode(r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+
+	rg := region(r)
+	for _, lb := range s.loadBalancers {
+		if lb.region == rg && lb.Name == req.Name {
+			writeError(w, http.StatusConflict, "database_loadbalancer_duplicate_name", fmt.Sprintf("a load balancer named %s already exists", req.Name))
+			return
+		}
+	}
+
+	networkID := req.NetworkID
+	if networkID == "" {
+		networkID = s.defaultNetwork(rg).ID
+	} else if _, ok := s.network(r, networkID); !ok {
+		notFound(w, "database_network_not_found", "network", networkID)
+		return
+	}
+
+	firewallID := req.FirewallID
+	if firewallID == "" {
+		f := s.newFirewall(rg, fmt.Sprintf("%s-loadbalancer", req.Name), networkID, false)
+		rule := req.FirewallRules
+		if rule == "" || rule == "all" {
+			rule = "1-65535"
+		}
+		f.addRule(civogo.FirewallRule{Protocol: "tcp", Ports: rule, Cidr: []string{"0.0.0.0/0"}, Label: "Load balancer access"})
+		firewallID = f.ID
+	} else if _, ok := s.firewall(r, firewallID); !ok {
+		notFound(w, "database_firewall_not_found", "firewall", firewallID)
+		return
+	}
+
+	algorithm := req.Algorithm
+	if algorithm == "" {
+		algorithm = "round_robin"
+	}
+	maxConcurrentRequests := 10000
+	if req.MaxConcurrentRequests != nil {
+		maxConcurrentRequests = *req.MaxConcurrentRequests
+	}
+
+	lb := &loadBalancer{
+		LoadBalancer: civogo.LoadBalancer{
+			ID:                           newID(),
+			Name:                         req.Name,
+			ServiceName:                  req.ServiceName,
+			NetworkID:                    networkID,
+			Algorithm:                    algorithm,
+			Backends:                     loadBalancerBackends(req.Backends),
+			InstancePool:                 loadBalancerInstancePools(req.InstancePools),
+			ExternalTrafficPolicy:        req.ExternalTrafficPolicy,
+			SessionAffinity:              req.SessionAffinity,
+			SessionAffinityConfigTimeout: req.SessionAffinityConfigTimeout,
+			EnableProxyProtocol:          req.EnableProxyProtocol,
+			PublicIP:                     s.publicAddress(),
+			PrivateIP:                    s.privateAddress(networkID),
+			FirewallID:                   firewallID,
+			ClusterID:                    req.ClusterID,
+			State:                        "available",
+			MaxConcurrentRequests:        maxConcurrentRequests,
+			Options:                      req.LoadBalancerOptions,
+		},
+		region: rg,
+	}
+	s.loadBalancers[lb.ID] = lb
+
+	s.markPending(lb.ID, "pending")
+	writeJSON(w, http.StatusOK, s.loadBalancerView(lb))
+}
+
+func (s *Server) updateLoadBalancer(w http.ResponseWriter, r *http.Request) {
+	lb, ok := s.loadBalancer(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_loadbalancer_not_found", "load balancer", r.PathValue("id"))
+		return
+	}
+
+	var req civogo.LoadBalancerUpdateConfig
+	if err := decode(r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if req.FirewallID != "" {
+		if _, ok := s.firewall(r, req.FirewallID); !ok {
+			notFound(w, "database_firewall_not_found", "firewall", req.FirewallID)
+			return
+		}
+		lb.FirewallID = req.FirewallID
+	}
+	if req.Name != "" {
+		lb.Name = req.Name
+	}
+	if req.ServiceName != "" {
+		lb.ServiceName = req.ServiceName
+	}
+	if req.Algorithm != "" {
+		lb.Algorithm = req.Algorithm
+	}
+	if req.Backends != nil {
+		lb.Backends = loadBalancerBackends(req.Backends)
+	}
+	if req.InstancePools != nil {
+		lb.InstancePool = loadBalancerInstancePools(req.InstancePools)
+	}
+	if req.ExternalTrafficPolicy != "" {
+		lb.ExternalTrafficPolicy = req.ExternalTrafficPolicy
+	}
+	if req.SessionAffinity != "" {
+		lb.SessionAffinity = req.SessionAffinity
+	}
+	if req.SessionAffinityConfigTimeout != 0 {
+		lb.SessionAffinityConfigTimeout = req.SessionAffinityConfigTimeout
+	}
+	if req.EnableProxyProtocol != "" {
+		lb.EnableProxyProtocol = req.EnableProxyProtocol
+	}
+	if req.MaxConcurrentRequests != nil {
+		lb.MaxConcurrentRequests = *req.MaxConcurrentRequests
+	}
+	if req.LoadBalancerOptions != nil {
+		lb.Options = req.LoadBalancerOptions
+	}
+
+	s.markPending(lb.ID, "pending")
+	writeJSON(w, http.StatusOK, s.loadBalancerView(lb))
+}
+
+func (s *Server) deleteLoadBalancer(w http.ResponseWriter, r *http.Request) {
+	lb, ok := s.loadBalancer(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_loadbalancer_not_found", "load balancer", r.PathValue("id"))
+		return
+	}
+	for _, ip := range s.ips {
+		if ip.AssignedTo.ID == lb.ID {
+			ip.AssignedTo = civogo.AssignedTo{}
+		}
+	}
+	delete(s.loadBalancers, lb.ID)
+	delete(s.pending, lb.ID)
+	writeSuccess(w, lb.ID)
+}
@@ -0,0 +1,214 @@
+package mockapi
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+
+	"github.com/civo/civogo"
+)
+
+type network struct {
+	civogo.Network
+	region string
+}
+
+type firewall struct {
+	civogo.Firewall
+	region string
+}
+
+func (s *Server) registerNetworks(mux *http.ServeMux) {
+	for _, prefix := range []string{"/v2/networks", "/v2/vpc/networks"} {
+		mux.HandleFunc("GET "+prefix, s.listNetworks)
+		mux.HandleFunc("POST "+prefix, s.createNetwork)
+		mux.HandleFunc("GET "+prefix+"/{id}", s.getNetwork)
+		mux.HandleFunc("PUT "+prefix+"/{id}", s.updateNetwork)
+		mux.HandleFunc("DELETE "+prefix+"/{id}", s.deleteNetwork)
+	}
+}
+
+// defaultNetwork returns the default network of the region, creating it along
+// with its default firewall the first time the region is used
+func (s *Server) defaultNetwork(region string) *network {
+	for _, n := range s.networks {
+		if n.Default && n.region == region {
+			return n
+		}
+	}
+
+	n := &network{
+		Network: civogo.Network{
+			ID:            newID(),
+			Name:          "cust-default-" + region,
+			Label:         "Default",
+			Default:       true,
+			CIDR:          "192.168.1.0/24",
+			Status:        "Active",
+			IPv4Enabled:   true,
+			NameserversV4: []string{"8.8.8.8", "1.1.1.1"},
+		},
+		region: region,
+	}
+	s.networks[n.ID] = n
+	s.newFirewall(region, "default-default", n.ID, true)
+	return n
+}
+
+// networksIn returns the networks of the region sorted by label
+func (s *Server) networksIn(region string) []*network {
+	s.defaultNetwork(region)
+
+	var result []*network
+	for _, n := range s.networks {
+		if n.region == region {
+			result = append(result, n)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Label < result[j].Label })
+	return result
+}
+
+// nextCIDR returns the first free /24 in 192.168.0.0/16 for the region
+func (s *Server) nextCIDR(region string) string {
+	used := map[string]bool{}
+	for _, n := range s.networksIn(region) {
+		used[n.CIDR] = true
+	}
+	for i := 2; i < 256; i++ {
+		cidr := fmt.Sprintf("192.168.%d.0/24", i)
+		if !used[cidr] {
+			return cidr
+		}
+	}
+	return "10.0.0.0/24"
+}
+
+// network returns the network visible to the request
+func (s *Server) network(r *http.Request, id string) (*network, bool) {
+	n, ok := s.networks[id]
+	if !ok || !inRegion(r, n.region) {
+		return nil, false
+	}
+	return n, true
+}
+
+func (s *Server) listNetworks(w http.ResponseWriter, r *http.Request) {
+	result := []civogo.Network{}
+	for _, n := range s.networksIn(region(r)) {
+		result = append(result, n.Network)
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) getNetwork(w http.ResponseWriter, r *http.Request) {
+	n, ok := s.network(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_network_not_found", "network", r.PathValue("id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, n.Network)
+}
+
+func (s *Server) createNetwork(w http.ResponseWriter, r *http.Request) {
+	var req civogo.NetworkConfig
+	if err := decode(r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if req.Label == "" {
+		writeError(w, http.StatusBadRequest, "parameter_label_invalid", "the label of the network is required")
+		return
+	}
+
+	rg := region(r)
+	for _, n := range s.networksIn(rg) {
+		if n.Label == req.Label {
+			writeError(w, http.StatusConflict, "database_network_duplicate_name", fmt.Sprintf("a network named %s already exists", req.Label))
+			return
+		}
+	}
+
+	cidr := req.CIDRv4
+	if cidr == "" {
+		cidr = s.nextCIDR(rg)
+	} else if _, _, err := net.ParseCIDR(cidr); err != nil {
+		writeError(w, http.StatusBadRequest, "parameter_invalid", fmt.Sprintf("invalid CIDR %s", cidr))
+		return
+	}
+
+	n := &network{
+		Network: civogo.Network{
+			ID:            newID(),
+			Name:          "cust-" + req.Label,
+			Label:         req.Label,
+			CIDR:          cidr,
+			Status:        "Active",
+			IPv4Enabled:   true,
+			NameserversV4: req.NameserversV4,
+		},
+		region: rg,
+	}
+	if len(n.NameserversV4) == 0 {
+		n.NameserversV4 = []string{"8.8.8.8", "1.1.1.1"}
+	}
+	if req.VLanConfig != nil {
+		n.VlanID = req.VLanConfig.VlanID
+		n.PhysicalInterface = req.VLanConfig.PhysicalInterface
+		n.GatewayIPv4 = req.VLanConfig.GatewayIPv4
+		n.AllocationPoolV4Start = req.VLanConfig.AllocationPoolV4Start
+		n.AllocationPoolV4End = req.VLanConfig.AllocationPoolV4End
+	}
+	s.networks[n.ID] = n
+
+	writeJSON(w, http.StatusOK, civogo.NetworkResult{ID: n.ID, Label: n.Label, Result: civogo.ResultSuccess})
+}
+
+func (s *Server) updateNetwork(w http.ResponseWriter, r *http.Request) {
+	n, ok := s.network(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_network_not_found", "network", r.PathValue("id"))
+		return
+	}
+
+	var req civogo.NetworkConfig
+	if err := decode(r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if req.Label != "" {
+		n.Label = req.Label
+	}
+	if req.NameserversV4 != nil {
+		n.NameserversV4 = req.NameserversV4
+	}
+
+	writeJSON(w, http.StatusOK, civogo.NetworkResult{ID: n.ID, Label: n.Label, Result: civogo.ResultSuccess})
+}
+
+func (s *Server) deleteNetwork(w http.ResponseWriter, r *http.Request) {
+	n, ok := s.network(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_network_not_found", "network", r.PathValue("id"))
+		return
+	}
+	if n.Default {
+		writeError(w, http.StatusBadRequest, "network_delete_default", "the default network cannot be deleted")
+		return
+	}
+	for _, i := range s.instances {
+		if i.NetworkID == n.ID {
+			writeError(w, http.StatusConflict, "database_network_delete_with_instance", "the network still has instances")
+			return
+		}
+	}
+
+	for id, f := range s.firewalls {
+		if f.NetworkID == n.ID {
+			delete(s.firewalls, id)
+		}
+	}
+	delete(s.networks, n.ID)
+	writeSuccess(w, n.ID)
+}
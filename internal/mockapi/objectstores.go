@@ -0,0 +1,296 @@
+package mockapi
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/civo/civogo"
+)
+
+type objectStore struct {
+	civogo.ObjectStore
+	region string
+}
+
+type objectStoreCredential struct {
+	civogo.ObjectStoreCredential
+	region string
+}
+
+func (s *Server) registerObjectStores(mux *http.ServeMux) {
+	mux.HandleFunc("GET /v2/objectstores", s.listObjectStores)
+	mux.HandleFunc("POST /v2/objectstores", s.createObjectStore)
+	mux.HandleFunc("GET /v2/objectstores/{id}", s.getObjectStore)
+	mux.HandleFunc("PUT /v2/objectstores/{id}", s.updateObjectStore)
+	mux.HandleFunc("DELETE /v2/objectstores/{id}", s.deleteObjectStore)
+	mux.HandleFunc("GET /v2/objectstores/{id}/stats", s.objectStoreStats)
+	mux.HandleFunc("GET /v2/objectstore/credentials", s.listObjectStoreCredentials)
+	mux.HandleFunc("POST /v2/objectstore/credentials", s.createObjectStoreCredential)
+	mux.HandleFunc("GET /v2/objectstore/credentials/{id}", s.getObjectStoreCredential)
+	mux.HandleFunc("PUT /v2/objectstore/credentials/{id}", s.updateObjectStoreCredential)
+	mux.HandleFunc("DELETE /v2/objectstore/credentials/{id}", s.deleteObjectStoreCredential)
+}
+
+// objectStore returns the object store visible to the request
+func (s *Server) objectStore(r *http.Request, id string) (*objectStore, bool) {
+	o, ok := s.objectStores[id]
+	if !ok || !inRegion(r, o.region) {
+		return nil, false
+	}
+	return o, true
+}
+
+// objectStoreCredential returns the credential visible to the request
+func (s *Server) objectStoreCredential(r *http.Request, id string) (*objectStoreCredential, bool) {
+	c, ok := s.objectStoreCreds[id]
+	if !ok || !inRegion(r, c.region) {
+		return nil, false
+	}
+	return c, true
+}
+
+// newObjectStoreCredential stores a credential, generating the keys left empty
+func (s *Server) newObjectStoreCredential(region, name, accessKey, secretKey string, maxSize int) *objectStoreCredential {
+	if accessKey == "" {
+		accessKey = strings.ToUpper(strings.ReplaceAll(newID(), "-", ""))[:20]
+	}
+	if secretKey == "" {
+		secretKey = strings.ReplaceAll(newID()+newID(), "-", "")[:40]
+	}
+
+	c := &objectStoreCredential{
+		ObjectStoreCredential: civogo.ObjectStoreCredential{
+			ID:                newID(),
+			Name:              name,
+			AccessKeyID:       accessKey,
+			SecretAccessKeyID: secretKey,
+			MaxSizeGB:         maxSize,
+			Status:            "ready",
+		},
+		region: region,
+	}
+	s.objectStoreCreds[c.ID] = c
+	s.markPending(c.ID, "pending")
+	return c
+}
+
+func (s *Server) objectStoreView(o *objectStore) civogo.ObjectStore {
+	result := o.ObjectStore
+	result.Status = s.status(o.ID, o.Status)
+	return result
+}
+
+func (s *Server) objectStoreCredentialView(c *objectStoreCredential) civogo.ObjectStoreCredential {
+	result := c.ObjectStoreCredential
+	result.Status = s.status(c.ID, c.Status)
+	return result
+}
+
+func (s *Server) listObjectStores(w http.ResponseWriter, r *http.Request) {
+	all := []civogo.ObjectStore{}
+	for _, o := range s.objectStores {
+		if inRegion(r, o.region) {
+			all = append(all, s.objectStoreView(o))
+		}
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Name < all[j].Name })
+
+	items, current, perPage, pages := page(r, all)
+	writeJSON(w, http.StatusOK, civogo.PaginatedObjectstores{Page: current, PerPage: perPage, Pages: pages, Items: items})
+}
+
+func (s *Server) getObjectStore(w http.ResponseWriter, r *http.Request) {
+	o, ok := s.objectStore(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_objectstore_not_found", "object store", r.PathValue("id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.objectStoreView(o))
+}
+
+func (s *Server) createObjectStore(w http.ResponseWriter, r *http.Request) {
+	var req civogo.CreateObjectStoreRequest
+	if err := decode(r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if req.MaxSizeGB <= 0 || req.MaxSizeGB%500 != 0 {
+		writeError(w, http.StatusBadRequest, "parameter_invalid", "the size of the object store must be a multiple of 500GB")
+		return
+	}
+
+	rg := region(r)
+	for _, o := range s.objectStores {
+		if o.region == rg && o.Name == req.Name {
+			writeError(w, http.StatusConflict, "database_objectstore_duplicate_name", fmt.Sprintf("an object store named %s already exists", req.Name))
+			return
+		}
+	}
+
+	var owner *objectStoreCredential
+	if req.AccessKeyID != "" {
+		for _, c := range s.objectStoreCreds {
+			if c.region == rg && c.AccessKeyID == req.AccessKeyID {
+				owner = c
+			}
+		}
+		if owner == nil {
+			notFound(w, "database_objectstore_credential_not_found", "credential", req.AccessKeyID)
+			return
+		}
+	} else {
+		owner = s.newObjectStoreCredential(rg, req.Name, "", "", 0)
+	}
+
+	o := &objectStore{
+		ObjectStore: civogo.ObjectStore{
+			ID:      newID(),
+			Name:    req.Name,
+			MaxSize: int(req.MaxSizeGB),
+			OwnerInfo: civogo.BucketOwner{
+				AccessKeyID:  owner.AccessKeyID,
+				Name:         owner.Name,
+				CredentialID: owner.ID,
+			},
+			BucketURL: fmt.Sprintf("objectstore.%s.civo.com", strings.ToLower(rg)),
+			Status:    "ready",
+		},
+		region: rg,
+	}
+	s.objectStores[o.ID] = o
+
+	s.markPending(o.ID, "creating")
+	writeJSON(w, http.StatusOK, s.objectStoreView(o))
+}
+
+func (s *Server) updateObjectStore(w http.ResponseWriter, r *http.Request) {
+	o, ok := s.objectStore(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_objectstore_not_found", "object store", r.PathValue("id"))
+		return
+	}
+
+	var req civogo.UpdateObjectStoreRequest
+	if err := decode(r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if req.MaxSizeGB > 0 {
+		o.MaxSize = int(req.MaxSizeGB)
+	}
+	writeJSON(w, http.StatusOK, s.objectStoreView(o))
+}
+
+func (s *Server) deleteObjectStore(w http.ResponseWriter, r *http.Request) {
+	o, ok := s.objectStore(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_objectstore_not_found", "object store", r.PathValue("id"))
+		return
+	}
+	delete(s.objectStores, o.ID)
+	writeSuccess(w, o.ID)
+}
+
+func (s *Server) objectStoreStats(w http.ResponseWriter, r *http.Request) {
+	o, ok := s.objectStore(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_objectstore_not_found", "object store", r.PathValue("id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, civogo.ObjectStoreStats{MaxSizeKB: int64(o.MaxSize) * 1024 * 1024})
+}
+
+func (s *Server) listObjectStoreCredentials(w http.ResponseWriter, r *http.Request) {
+	all := []civogo.ObjectStoreCredential{}
+	for _, c := range s.objectStoreCreds {
+		if inRegion(r, c.region) {
+			all = append(all, s.objectStoreCredentialView(c))
+		}
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Name < all[j].Name })
+
+	items, current, perPage, pages := page(r, all)
+	writeJSON(w, http.StatusOK, civogo.PaginatedObjectStoreCredentials{Page: current, PerPage: perPage, Pages: pages, Items: items})
+}
+
+func (s *Server) getObjectStoreCredential(w http.ResponseWriter, r *http.Request) {
+	c, ok := s.objectStoreCredential(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_objectstore_credential_not_found", "credential", r.PathValue("id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.objectStoreCredentialView(c))
+}
+
+func (s *Server) createObjectStoreCredential(w http.ResponseWriter, r *http.Request) {
+	var req civogo.CreateObjectStoreCredentialRequest
+	if err := decode(r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+
+	rg := region(r)
+	for _, c := range s.objectStoreCreds {
+		if c.region == rg && c.Name == req.Name {
+			writeError(w, http.StatusConflict, "database_objectstore_credential_duplicate_name", fmt.Sprintf("a credential named %s already exists", req.Name))
+			return
+		}
+	}
+
+	var accessKey, secretKey string
+	if req.AccessKeyID != nil {
+		accessKey = *req.AccessKeyID
+	}
+	if req.SecretAccessKeyID != nil {
+		secretKey = *req.SecretAccessKeyID
+	}
+	maxSize := 0
+	if req.MaxSizeGB != nil {
+		maxSize = *req.MaxSizeGB
+	}
+
+	c := s.newObjectStoreCredential(rg, req.Name, accessKey, secretKey, maxSize)
+	writeJSON(w, http.StatusOK, s.objectStoreCredentialView(c))
+}
+
+func (s *Server) updateObjectStoreCredential(w http.ResponseWriter, r *http.Request) {
+	c, ok := s.objectStoreCredential(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_objectstore_credential_not_found", "credential", r.PathValue("id"))
+		return
+	}
+
+	var req civogo.UpdateObjectStoreCredentialRequest
+	if err := decode(r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if req.AccessKeyID != nil {
+		c.AccessKeyID = *req.AccessKeyID
+	}
+	if req.SecretAccessKeyID != nil {
+		c.SecretAccessKeyID = *req.SecretAccessKeyID
+	}
+	if req.MaxSizeGB != nil {
+		c.MaxSizeGB = *req.MaxSizeGB
+	}
+	writeJSON(w, http.StatusOK, s.objectStoreCredentialView(c))
+}
+
+func (s *Server) deleteObjectStoreCredential(w http.ResponseWriter, r *http.Request) {
+	c, ok := s.objectStoreCredential(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_objectstore_credential_not_found", "credential", r.PathValue("id"))
+		return
+	}
+	for _, o := range s.objectStores {
+		if o.OwnerInfo.CredentialID == c.ID {
+			writeError(w, http.StatusConflict, "database_objectstore_credential_in_use", "the credential still owns an object store")
+			return
+		}
+	}
+	delete(s.objectStoreCreds, c.ID)
+	writeSuccess(w, c.ID)
+}
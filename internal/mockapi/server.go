@@ -0,0 +1,312 @@
+// Package mockapi provides an in-process fake of the Civo API, built on
+// httptest, so the provider can be exercised without a real account.
+//
+// The fake keeps state for the objects the provider manages, reports newly
+// created objects as pending for a configurable number of polls so the
+// StateChangeConf wait loops are exercised, and can inject delays and error
+// responses through Inject.
+package mockapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/civo/civogo"
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultRegion is the region reported as default by the fake API and used
+	// for requests that don't set one
+	DefaultRegion = "LON1"
+
+	// Token is the API key the fake API accepts unless Server.Token is changed
+	Token = "mock-token"
+)
+
+// Server is an in-process fake Civo API
+type Server struct {
+	*httptest.Server
+
+	// Token is the API key expected in the Authorization header, when empty
+	// every key is accepted
+	Token string
+
+	mu           sync.Mutex
+	pendingPolls int
+	pending      map[string]*transition
+	addressSeq   int
+	faults       []*Fault
+	requests     []string
+
+	regions        []civogo.Region
+	sizes          []civogo.InstanceSize
+	diskImages     []civogo.DiskImage
+	kubeVersions   []civogo.KubernetesVersion
+	marketplace    []civogo.KubernetesMarketplaceApplication
+	databaseEngine map[string][]civogo.SupportedSoftwareVersion
+
+	networks         map[string]*network
+	firewalls        map[string]*firewall
+	instances        map[string]*civogo.Instance
+	instanceSnaps    map[string]*instanceSnapshot
+	schedules        map[string]*snapshotSchedule
+	volumes          map[string]*volume
+	snapshots        map[string]*volumeSnapshot
+	clusters         map[string]*cluster
+	domains          map[string]*civogo.DNSDomain
+	records          map[string]*civogo.DNSRecord
+	ips              map[string]*reservedIP
+	objectStores     map[string]*objectStore
+	objectStoreCreds map[string]*objectStoreCredential
+	databases        map[string]*database
+	loadBalancers    map[string]*loadBalancer
+	sshKeys          map[string]*civogo.SSHKey
+}
+
+// NewServer starts a new fake Civo API, the caller must Close it when done
+func NewServer() *Server {
+	s := &Server{
+		Token:            Token,
+		pending:          map[string]*transition{},
+		networks:         map[string]*network{},
+		firewalls:        map[string]*firewall{},
+		instances:        map[string]*civogo.Instance{},
+		instanceSnaps:    map[string]*instanceSnapshot{},
+		schedules:        map[string]*snapshotSchedule{},
+		volumes:          map[string]*volume{},
+		snapshots:        map[string]*volumeSnapshot{},
+		clusters:         map[string]*cluster{},
+		domains:          map[string]*civogo.DNSDomain{},
+		records:          map[string]*civogo.DNSRecord{},
+		ips:              map[string]*reservedIP{},
+		objectStores:     map[string]*objectStore{},
+		objectStoreCreds: map[string]*objectStoreCredential{},
+		databases:        map[string]*database{},
+		loadBalancers:    map[string]*loadBalancer{},
+		sshKeys:          map[string]*civogo.SSHKey{},
+	}
+	s.loadCatalog()
+
+	mux := http.NewServeMux()
+	s.registerCatalog(mux)
+	s.registerNetworks(mux)
+	s.registerFirewalls(mux)
+	s.registerInstances(mux)
+	s.registerInstanceSnapshots(mux)
+	s.registerVolumes(mux)
+	s.registerKubernetes(mux)
+	s.registerDNS(mux)
+	s.registerIPs(mux)
+	s.registerObjectStores(mux)
+	s.registerDatabases(mux)
+	s.registerLoadBalancers(mux)
+	s.registerSSHKeys(mux)
+
+	s.Server = httptest.NewServer(s.middleware(mux))
+	return s
+}
+
+// Client returns a civogo client pointed at the fake API in the given region
+func (s *Server) Client(region string) (*civogo.Client, error) {
+	return civogo.NewClientWithURL(Token, s.URL, region)
+}
+
+// SetPendingPolls sets how many reads a newly created or changed object
+// reports its transitional status (e.g. BUILDING) before reaching its final one
+func (s *Server) SetPendingPolls(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pendingPolls = n
+}
+
+// Requests returns every request served so far as "METHOD /path"
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+// RequestCount returns how many requests were served for the method and path
+func (s *Server) RequestCount(method, path string) int {
+	count := 0
+	for _, r := range s.Requests() {
+		if r == method+" "+path {
+			count++
+		}
+	}
+	return count
+}
+
+func (s *Server) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+		fault := s.matchFault(r)
+		token := s.Token
+		s.mu.Unlock()
+
+		if fault != nil && fault.serve(w, r) {
+			return
+		}
+
+		if token != "" && r.Header.Get("Authorization") != "bearer "+token {
+			writeError(w, http.StatusUnauthorized, "database_account_not_found", "the API key is not valid")
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// transition is a status an object reports for a number of polls before
+// settling on its stored status
+type transition struct {
+	status string
+	polls  int
+}
+
+// markPending makes the object report pendingStatus for the configured number
+// of polls, the caller must hold the lock
+func (s *Server) markPending(id, pendingStatus string) {
+	if s.pendingPolls > 0 {
+		s.pending[id] = &transition{status: pendingStatus, polls: s.pendingPolls}
+	}
+}
+
+// status returns the transitional status of the object while it is pending
+// and counts the read as one poll, otherwise it returns finalStatus
+func (s *Server) status(id, finalStatus string) string {
+	t, ok := s.pending[id]
+	if !ok {
+		return finalStatus
+	}
+	t.polls--
+	if t.polls <= 0 {
+		delete(s.pending, id)
+	}
+	return t.status
+}
+
+// publicAddress returns a new unique public IPv4 address
+func (s *Server) publicAddress() string {
+	s.addressSeq++
+	return fmt.Sprintf("74.220.%d.%d", 16+s.addressSeq/250, 1+s.addressSeq%250)
+}
+
+// privateAddress returns a new unique address inside the network's CIDR
+func (s *Server) privateAddress(networkID string) string {
+	s.addressSeq++
+	cidr := "192.168.1.0/24"
+	if n, ok := s.networks[networkID]; ok && n.CIDR != "" {
+		cidr = n.CIDR
+	}
+	ip, _, err := net.ParseCIDR(cidr)
+	if err != nil || ip.To4() == nil {
+		return ""
+	}
+	ip = ip.To4()
+	host := 10 + s.addressSeq%240
+	return fmt.Sprintf("%d.%d.%d.%d", ip[0], ip[1], ip[2], host)
+}
+
+// region returns the region the request is scoped to
+func region(r *http.Request) string {
+	if v := r.URL.Query().Get("region"); v != "" {
+		return strings.ToUpper(v)
+	}
+	return DefaultRegion
+}
+
+// inRegion reports whether an object in objRegion is visible to the request
+func inRegion(r *http.Request, objRegion string) bool {
+	return strings.EqualFold(region(r), objRegion)
+}
+
+func newID() string {
+	return uuid.New().String()
+}
+
+func decode(r *http.Request, v interface{}) error {
+	if r.Body == nil {
+		return nil
+	}
+	dec := json.NewDecoder(r.Body)
+	if err := dec.Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error body in the format civogo decodes into its typed errors
+func writeError(w http.ResponseWriter, status int, code, reason string) {
+	body := map[string]interface{}{
+		"code":   code,
+		"reason": reason,
+	}
+	if status >= 500 {
+		body["status"] = status
+	}
+	writeJSON(w, status, body)
+}
+
+func writeSuccess(w http.ResponseWriter, id string) {
+	writeJSON(w, http.StatusOK, civogo.SimpleResponse{ID: id, Result: civogo.ResultSuccess})
+}
+
+func writeBadRequest(w http.ResponseWriter, err error) {
+	writeError(w, http.StatusBadRequest, "parameter_invalid", err.Error())
+}
+
+// page returns the requested page of items along with the page count
+func page[T any](r *http.Request, items []T) ([]T, int, int, int) {
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if perPage <= 0 {
+		perPage = 20
+	}
+	current, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if current <= 0 {
+		current = 1
+	}
+
+	pages := (len(items) + perPage - 1) / perPage
+	if pages == 0 {
+		pages = 1
+	}
+
+	start := (current - 1) * perPage
+	if start > len(items) {
+		start = len(items)
+	}
+	end := start + perPage
+	if end > len(items) {
+		end = len(items)
+	}
+
+	return items[start:end], current, perPage, pages
+}
+
+// splitTags splits the space separated tags string used by the API
+func splitTags(tags string) []string {
+	return strings.Fields(tags)
+}
+
+func notFound(w http.ResponseWriter, code, kind, id string) {
+	writeError(w, http.StatusNotFound, code, fmt.Sprintf("the requested %s (%s) could not be found", kind, id))
+}
@@ -0,0 +1,252 @@
+package mockapi
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/civo/civogo"
+)
+
+func newTestClient(t *testing.T) (*Server, *civogo.Client) {
+	t.Helper()
+	s := NewServer()
+	t.Cleanup(s.Close)
+
+	client, err := s.Client(DefaultRegion)
+	if err != nil {
+		t.Fatalf("failed to build client: %s", err)
+	}
+	return s, client
+}
+
+func TestInstanceLifecycle(t *testing.T) {
+	s, client := newTestClient(t)
+	s.SetPendingPolls(2)
+
+	network, err := client.GetDefaultNetwork()
+	if err != nil {
+		t.Fatalf("GetDefaultNetwork: %s", err)
+	}
+
+	config, err := client.NewInstanceConfig()
+	if err != nil {
+		t.Fatalf("NewInstanceConfig: %s", err)
+	}
+	config.Hostname = "web-1"
+	config.Size = "g3.small"
+	config.TemplateID = "ubuntu-jammy"
+
+	created, err := client.CreateInstance(config)
+	if err != nil {
+		t.Fatalf("CreateInstance: %s", err)
+	}
+	if created.NetworkID != network.ID {
+		t.Errorf("network = %q, want the default network %q", created.NetworkID, network.ID)
+	}
+
+	var statuses []string
+	for i := 0; i < 3; i++ {
+		instance, err := client.GetInstance(created.ID)
+		if err != nil {
+			t.Fatalf("GetInstance: %s", err)
+		}
+		statuses = append(statuses, instance.Status)
+	}
+	if statuses[0] != "BUILDING" || statuses[2] != "ACTIVE" {
+		t.Errorf("statuses = %v, want the instance to become ACTIVE after the pending polls", statuses)
+	}
+
+	if _, err := client.DeleteInstance(created.ID); err != nil {
+		t.Fatalf("DeleteInstance: %s", err)
+	}
+	if _, err := client.GetInstance(created.ID); !errors.Is(err, civogo.DatabaseInstanceNotFoundError) {
+		t.Errorf("GetInstance after delete: err = %v, want DatabaseInstanceNotFoundError", err)
+	}
+}
+
+func TestRegionScoping(t *testing.T) {
+	s, client := newTestClient(t)
+
+	if _, err := client.NewFirewall(&civogo.FirewallConfig{Name: "web"}); err != nil {
+		t.Fatalf("NewFirewall: %s", err)
+	}
+
+	other, err := s.Client("FRA1")
+	if err != nil {
+		t.Fatalf("failed to build client: %s", err)
+	}
+	if _, err := other.FindFirewall("web"); err == nil {
+		t.Error("a firewall created in LON1 was visible from FRA1")
+	}
+}
+
+func TestInvalidToken(t *testing.T) {
+	s, _ := newTestClient(t)
+
+	client, err := civogo.NewClientWithURL("wrong", s.URL, DefaultRegion)
+	if err != nil {
+		t.Fatalf("failed to build client: %s", err)
+	}
+	if _, err := client.ListRegions(); err == nil {
+		t.Error("expected an error for an invalid API key")
+	}
+}
+
+func TestInjectedFaults(t *testing.T) {
+	s, client := newTestClient(t)
+
+	s.Inject(Fault{Method: http.MethodGet, Path: "/v2/regions", Status: http.StatusTooManyRequests, RetryAfter: 1, Count: 1})
+	if _, err := client.ListRegions(); err == nil {
+		t.Fatal("expected the injected 429 to fail the request")
+	}
+	if _, err := client.ListRegions(); err != nil {
+		t.Fatalf("the fault should apply once: %s", err)
+	}
+
+	s.Inject(Fault{Path: "/v2/volumes/", Status: http.StatusNotFound, Code: "database_volume_not_found"})
+	if _, err := client.GetVolume("missing"); !errors.Is(err, civogo.DatabaseVolumeNotFoundError) {
+		t.Errorf("err = %v, want DatabaseVolumeNotFoundError", err)
+	}
+
+	s.ClearFaults()
+	s.Inject(Fault{Path: "/v2/regions", Delay: 50 * time.Millisecond, Count: 1})
+	start := time.Now()
+	if _, err := client.ListRegions(); err != nil {
+		t.Fatalf("ListRegions: %s", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("request took %s, want the injected delay to apply", elapsed)
+	}
+
+	if got := s.RequestCount(http.MethodGet, "/v2/regions"); got != 3 {
+		t.Errorf("RequestCount = %d, want 3", got)
+	}
+}
+
+func TestPagination(t *testing.T) {
+	s, client := newTestClient(t)
+
+	for _, name := range []string{"a", "b", "c"} {
+		if _, err := client.NewIP(&civogo.CreateIPRequest{Name: name}); err != nil {
+			t.Fatalf("NewIP: %s", err)
+		}
+	}
+
+	ips, err := client.ListIPs()
+	if err != nil {
+		t.Fatalf("ListIPs: %s", err)
+	}
+	if len(ips.Items) != 3 {
+		t.Errorf("got %d reserved IPs, want 3", len(ips.Items))
+	}
+	if s.RequestCount(http.MethodPost, "/v2/ips") != 3 {
+		t.Errorf("requests = %v", s.Requests())
+	}
+}
+
+func TestDatabaseBackups(t *testing.T) {
+	_, client := newTestClient(t)
+
+	db, err := client.NewDatabase(&civogo.CreateDatabaseRequest{
+		Name:     "app",
+		Size:     "g3.db.small",
+		Software: "PostgreSQL",
+	})
+	if err != nil {
+		t.Fatalf("NewDatabase: %s", err)
+	}
+	if db.SoftwareVersion != "16" || db.Port != 5432 {
+		t.Errorf("got version %q port %d, want the PostgreSQL defaults", db.SoftwareVersion, db.Port)
+	}
+
+	backup, err := client.CreateDatabaseBackup(db.ID, &civogo.DatabaseBackupCreateRequest{Name: "nightly"})
+	if err != nil {
+		t.Fatalf("CreateDatabaseBackup: %s", err)
+	}
+	if _, err := client.RestoreDatabase(db.ID, &civogo.RestoreDatabaseRequest{Backup: backup.Name}); err != nil {
+		t.Fatalf("RestoreDatabase: %s", err)
+	}
+
+	restored, err := client.NewDatabase(&civogo.CreateDatabaseRequest{Name: "app-restored", Size: "g3.db.small", Software: "PostgreSQL"})
+	if err != nil {
+		t.Fatalf("NewDatabase: %s", err)
+	}
+	if _, err := client.RestoreDatabase(restored.ID, &civogo.RestoreDatabaseRequest{Backup: backup.Name}); err != nil {
+		t.Errorf("expected the backup to be restored into another database: %s", err)
+	}
+	other, err := client.NewDatabase(&civogo.CreateDatabaseRequest{Name: "other", Size: "g3.db.small", Software: "MySQL"})
+	if err != nil {
+		t.Fatalf("NewDatabase: %s", err)
+	}
+	if _, err := client.RestoreDatabase(other.ID, &civogo.RestoreDatabaseRequest{Backup: backup.Name}); err == nil {
+		t.Error("expected a PostgreSQL backup not to be restored into a MySQL database")
+	}
+
+	if _, err := client.NewDatabase(&civogo.CreateDatabaseRequest{Name: "bad", Size: "g3.db.small", Software: "mysql", SoftwareVersion: "5.7"}); err == nil {
+		t.Error("expected an unsupported version to be rejected")
+	}
+}
+
+func TestDatabaseConnection(t *testing.T) {
+	s, client := newTestClient(t)
+
+	db, err := client.NewDatabase(&civogo.CreateDatabaseRequest{Name: "app", Size: "g3.db.small", Software: "MySQL"})
+	if err != nil {
+		t.Fatalf("NewDatabase: %s", err)
+	}
+	if err := s.SetDatabaseConnection(db.ID, "127.0.0.1", 13306, "admin", "secret"); err != nil {
+		t.Fatalf("SetDatabaseConnection: %s", err)
+	}
+
+	got, err := client.GetDatabase(db.ID)
+	if err != nil {
+		t.Fatalf("GetDatabase: %s", err)
+	}
+	if got.PublicIPv4 != "127.0.0.1" || got.Port != 13306 || got.Username != "admin" || got.Password != "secret" {
+		t.Errorf("got %s:%d as %s, want the database to point at the local server", got.PublicIPv4, got.Port, got.Username)
+	}
+	if err := s.SetDatabaseConnection("missing", "127.0.0.1", 13306, "admin", "secret"); err == nil {
+		t.Error("expected an unknown database to be rejected")
+	}
+}
+
+func TestInstanceFromSnapshot(t *testing.T) {
+	_, client := newTestClient(t)
+
+	config, err := client.NewInstanceConfig()
+	if err != nil {
+		t.Fatalf("NewInstanceConfig: %s", err)
+	}
+	config.Hostname = "golden"
+	config.Size = "g3.medium"
+	config.TemplateID = "debian-12"
+	source, err := client.CreateInstance(config)
+	if err != nil {
+		t.Fatalf("CreateInstance: %s", err)
+	}
+
+	snapshot, err := client.CreateInstanceSnapshot(source.ID, &civogo.CreateInstanceSnapshotParams{Name: "golden"})
+	if err != nil {
+		t.Fatalf("CreateInstanceSnapshot: %s", err)
+	}
+
+	config.Hostname = "too-small"
+	config.Size = "g3.small"
+	config.TemplateID = ""
+	config.SnapshotID = snapshot.ID
+	if _, err := client.CreateInstance(config); !errors.Is(err, civogo.DatabaseInstanceSnapshotTooBigError) {
+		t.Errorf("err = %v, want DatabaseInstanceSnapshotTooBigError", err)
+	}
+
+	config.Hostname = "restored"
+	config.Size = "g3.large"
+	restored, err := client.CreateInstance(config)
+	if err != nil {
+		t.Fatalf("CreateInstance from snapshot: %s", err)
+	}
+	if restored.SourceType != "snapshot" || restored.SnapshotID != snapshot.ID {
+		t.Errorf("got source %s/%s, want the snapshot", restored.SourceType, restored.SnapshotID)
+	}
+}
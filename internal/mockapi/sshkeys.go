@@ -0,0 +1,94 @@
+package mockapi
+
+import (
+	"crypto/md5"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/civo/civogo"
+)
+
+func (s *Server) registerSSHKeys(mux *http.ServeMux) {
+	mux.HandleFunc("GET /v2/sshkeys", s.listSSHKeys)
+	mux.HandleFunc("POST /v2/sshkeys", s.createSSHKey)
+	mux.HandleFunc("GET /v2/sshkeys/{id}", s.getSSHKey)
+	mux.HandleFunc("PUT /v2/sshkeys/{id}", s.updateSSHKey)
+	mux.HandleFunc("DELETE /v2/sshkeys/{id}", s.deleteSSHKey)
+}
+
+func (s *Server) listSSHKeys(w http.ResponseWriter, _ *http.Request) {
+	result := []civogo.SSHKey{}
+	for _, k := range s.sshKeys {
+		result = append(result, *k)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) getSSHKey(w http.ResponseWriter, r *http.Request) {
+	k, ok := s.sshKeys[r.PathValue("id")]
+	if !ok {
+		notFound(w, "database_ssh_key_not_found", "SSH key", r.PathValue("id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, k)
+}
+
+func (s *Server) createSSHKey(w http.ResponseWriter, r *http.Request) {
+	var req map[string]string
+	if err := decode(r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if strings.TrimSpace(req["public_key"]) == "" {
+		writeError(w, http.StatusBadRequest, "parameter_public_key_empty", "the public key is required")
+		return
+	}
+	for _, k := range s.sshKeys {
+		if k.Name == req["name"] {
+			writeError(w, http.StatusConflict, "database_ssh_key_duplicate_name", fmt.Sprintf("an SSH key named %s already exists", req["name"]))
+			return
+		}
+	}
+
+	k := &civogo.SSHKey{
+		ID:          newID(),
+		Name:        req["name"],
+		PublicKey:   req["public_key"],
+		Fingerprint: fmt.Sprintf("MD5:%x", md5.Sum([]byte(req["public_key"]))),
+		CreatedAt:   time.Now().UTC(),
+	}
+	s.sshKeys[k.ID] = k
+	writeSuccess(w, k.ID)
+}
+
+func (s *Server) updateSSHKey(w http.ResponseWriter, r *http.Request) {
+	k, ok := s.sshKeys[r.PathValue("id")]
+	if !ok {
+		notFound(w, "database_ssh_key_not_found", "SSH key", r.PathValue("id"))
+		return
+	}
+
+	var req map[string]string
+	if err := decode(r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if req["name"] != "" {
+		k.Name = req["name"]
+	}
+	writeJSON(w, http.StatusOK, k)
+}
+
+func (s *Server) deleteSSHKey(w http.ResponseWriter, r *http.Request) {
+	k, ok := s.sshKeys[r.PathValue("id")]
+	if !ok {
+		notFound(w, "database_ssh_key_not_found", "SSH key", r.PathValue("id"))
+		return
+	}
+	delete(s.sshKeys, k.ID)
+	writeSuccess(w, k.ID)
+}
@@ -0,0 +1,337 @@
+package mockapi
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/civo/civogo"
+)
+
+type volume struct {
+	civogo.Volume
+	region string
+}
+
+type volumeSnapshot struct {
+	civogo.VolumeSnapshot
+	region string
+}
+
+func (s *Server) registerVolumes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /v2/volumes", s.listVolumes)
+	mux.HandleFunc("POST /v2/volumes", s.createVolume)
+	mux.HandleFunc("GET /v2/volumes/{id}", s.getVolume)
+	mux.HandleFunc("DELETE /v2/volumes/{id}", s.deleteVolume)
+	mux.HandleFunc("PUT /v2/volumes/{id}/resize", s.resizeVolume)
+	mux.HandleFunc("PUT /v2/volumes/{id}/attach", s.attachVolume)
+	mux.HandleFunc("PUT /v2/volumes/{id}/detach", s.detachVolume)
+	mux.HandleFunc("GET /v2/volumes/{id}/snapshots", s.listVolumeSnapshots)
+	mux.HandleFunc("POST /v2/volumes/{id}/snapshots", s.createVolumeSnapshot)
+	mux.HandleFunc("GET /v2/volumes/{id}/snapshots/{snapshot}", s.getVolumeSnapshot)
+	mux.HandleFunc("GET /v2/snapshots", s.listSnapshots)
+	mux.HandleFunc("GET /v2/snapshots/{snapshot}", s.getSnapshot)
+	mux.HandleFunc("DELETE /v2/snapshots/{snapshot}", s.deleteSnapshot)
+}
+
+// volume returns the volume visible to the request
+func (s *Server) volume(r *http.Request, id string) (*volume, bool) {
+	v, ok := s.volumes[id]
+	if !ok || !inRegion(r, v.region) {
+		return nil, false
+	}
+	return v, true
+}
+
+// snapshot returns the volume snapshot visible to the request
+func (s *Server) snapshot(r *http.Request, id string) (*volumeSnapshot, bool) {
+	snap, ok := s.snapshots[id]
+	if !ok || !inRegion(r, snap.region) {
+		return nil, false
+	}
+	return snap, true
+}
+
+// volumeView returns a copy of the volume with its current status
+func (s *Server) volumeView(v *volume) civogo.Volume {
+	result := v.Volume
+	result.Status = s.status(v.ID, v.Status)
+	return result
+}
+
+// snapshotView returns a copy of the snapshot with its current state
+func (s *Server) snapshotView(snap *volumeSnapshot) civogo.VolumeSnapshot {
+	result := snap.VolumeSnapshot
+	result.State = s.status(snap.SnapshotID, snap.State)
+	return result
+}
+
+func (s *Server) listVolumes(w http.ResponseWriter, r *http.Request) {
+	result := []civogo.Volume{}
+	for _, v := range s.volumes {
+		if inRegion(r, v.region) {
+			result = append(result, s.volumeView(v))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) getVolume(w http.ResponseWriter, r *http.Request) {
+	v, ok := s.volume(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_volume_not_found", "volume", r.PathValue("id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.volumeView(v))
+}
+
+func (s *Server) createVolume(w http.ResponseWriter, r *http.Request) {
+	var req civogo.VolumeConfig
+	if err := decode(r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+
+	rg := region(r)
+	for _, v := range s.volumes {
+		if v.region == rg && v.Name == req.Name {
+			writeError(w, http.StatusConflict, "database_volume_duplicate_name", fmt.Sprintf("a volume named %s already exists", req.Name))
+			return
+		}
+	}
+
+	if req.SnapshotID != "" {
+		snap, ok := s.snapshot(r, req.SnapshotID)
+		if !ok {
+			notFound(w, "database_snapshot_not_found", "snapshot", req.SnapshotID)
+			return
+		}
+		if req.SizeGigabytes == 0 {
+			req.SizeGigabytes = snap.RestoreSize
+		}
+		if req.SizeGigabytes < snap.RestoreSize {
+			writeError(w, http.StatusBadRequest, "volume_invalid_size", "the volume cannot be smaller than the snapshot it is restored from")
+			return
+		}
+	}
+	if req.SizeGigabytes <= 0 {
+		writeError(w, http.StatusBadRequest, "parameter_volume_size_incorrect", "the size of the volume must be positive")
+		return
+	}
+
+	networkID := req.NetworkID
+	if networkID == "" {
+		networkID = s.defaultNetwork(rg).ID
+	} else if _, ok := s.network(r, networkID); !ok {
+		notFound(w, "database_network_not_found", "network", networkID)
+		return
+	}
+
+	volumeType := req.VolumeType
+	if volumeType == "" {
+		volumeType = "csi-s3"
+	}
+
+	v := &volume{
+		Volume: civogo.Volume{
+			ID:            newID(),
+			Name:          req.Name,
+			ClusterID:     req.ClusterID,
+			NetworkID:     networkID,
+			Status:        "available",
+			VolumeType:    volumeType,
+			SizeGigabytes: req.SizeGigabytes,
+			Bootable:      req.Bootable,
+			CreatedAt:     time.Now().UTC(),
+		},
+		region: rg,
+	}
+	s.volumes[v.ID] = v
+
+	s.markPending(v.ID, "creating")
+	writeJSON(w, http.StatusOK, civogo.VolumeResult{ID: v.ID, Name: v.Name, Result: civogo.ResultSuccess})
+}
+
+func (s *Server) deleteVolume(w http.ResponseWriter, r *http.Request) {
+	v, ok := s.volume(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_volume_not_found", "volume", r.PathValue("id"))
+		return
+	}
+	if v.InstanceID != "" {
+		writeError(w, http.StatusConflict, "database_volume_delete_failed", "the volume is still attached")
+		return
+	}
+	if r.URL.Query().Get("delete_snapshot") == "true" {
+		for id, snap := range s.snapshots {
+			if snap.VolumeID == v.ID {
+				delete(s.snapshots, id)
+			}
+		}
+	}
+	delete(s.volumes, v.ID)
+	writeSuccess(w, v.ID)
+}
+
+func (s *Server) resizeVolume(w http.ResponseWriter, r *http.Request) {
+	v, ok := s.volume(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_volume_not_found", "volume", r.PathValue("id"))
+		return
+	}
+
+	var req struct {
+		SizeGigabytes int `json:"size_gb"`
+	}
+	if err := decode(r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if v.InstanceID != "" {
+		writeError(w, http.StatusConflict, "database_volume_still_attached_cannot_resize", "the volume must be detached to be resized")
+		return
+	}
+	if req.SizeGigabytes <= v.SizeGigabytes {
+		writeError(w, http.StatusBadRequest, "parameter_volume_size_must_increase", "the new size must be bigger than the current one")
+		return
+	}
+
+	v.SizeGigabytes = req.SizeGigabytes
+	s.markPending(v.ID, "resizing")
+	writeSuccess(w, v.ID)
+}
+
+func (s *Server) attachVolume(w http.ResponseWriter, r *http.Request) {
+	v, ok := s.volume(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_volume_not_found", "volume", r.PathValue("id"))
+		return
+	}
+
+	var req civogo.VolumeAttachConfig
+	if err := decode(r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if _, ok := s.instance(r, req.InstanceID); !ok {
+		notFound(w, "database_instance_find", "instance", req.InstanceID)
+		return
+	}
+	if v.InstanceID != "" && v.InstanceID != req.InstanceID {
+		writeError(w, http.StatusConflict, "database_volume_cannot_multiple_attach", "the volume is already attached to another instance")
+		return
+	}
+
+	v.InstanceID = req.InstanceID
+	v.MountPoint = "/dev/vdb"
+	v.Status = "attached"
+	s.markPending(v.ID, "attaching")
+	writeSuccess(w, v.ID)
+}
+
+func (s *Server) detachVolume(w http.ResponseWriter, r *http.Request) {
+	v, ok := s.volume(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_volume_not_found", "volume", r.PathValue("id"))
+		return
+	}
+	if v.InstanceID == "" {
+		writeError(w, http.StatusBadRequest, "database_volume_not_attached", "the volume is not attached")
+		return
+	}
+
+	v.InstanceID = ""
+	v.MountPoint = ""
+	v.Status = "available"
+	s.markPending(v.ID, "detaching")
+	writeSuccess(w, v.ID)
+}
+
+func (s *Server) listVolumeSnapshots(w http.ResponseWriter, r *http.Request) {
+	v, ok := s.volume(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_volume_not_found", "volume", r.PathValue("id"))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.snapshotsOf(r, v.ID))
+}
+
+// snapshotsOf returns the snapshots visible to the request, of every volume
+// when volumeID is empty
+func (s *Server) snapshotsOf(r *http.Request, volumeID string) []civogo.VolumeSnapshot {
+	result := []civogo.VolumeSnapshot{}
+	for _, snap := range s.snapshots {
+		if inRegion(r, snap.region) && (volumeID == "" || snap.VolumeID == volumeID) {
+			result = append(result, s.snapshotView(snap))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+func (s *Server) createVolumeSnapshot(w http.ResponseWriter, r *http.Request) {
+	v, ok := s.volume(r, r.PathValue("id"))
+	if !ok {
+		notFound(w, "database_volume_not_found", "volume", r.PathValue("id"))
+		return
+	}
+
+	var req civogo.VolumeSnapshotConfig
+	if err := decode(r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+
+	snap := &volumeSnapshot{
+		VolumeSnapshot: civogo.VolumeSnapshot{
+			Name:                req.Name,
+			SnapshotID:          newID(),
+			SnapshotDescription: req.Description,
+			VolumeID:            v.ID,
+			InstanceID:          v.InstanceID,
+			SourceVolumeName:    v.Name,
+			RestoreSize:         v.SizeGigabytes,
+			State:               "Ready",
+			CreationTime:        time.Now().UTC().Format(time.RFC3339),
+		},
+		region: v.region,
+	}
+	s.snapshots[snap.SnapshotID] = snap
+
+	s.markPending(snap.SnapshotID, "Creating")
+	writeJSON(w, http.StatusOK, s.snapshotView(snap))
+}
+
+func (s *Server) getVolumeSnapshot(w http.ResponseWriter, r *http.Request) {
+	snap, ok := s.snapshot(r, r.PathValue("snapshot"))
+	if !ok || snap.VolumeID != r.PathValue("id") {
+		notFound(w, "database_snapshot_not_found", "snapshot", r.PathValue("snapshot"))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.snapshotView(snap))
+}
+
+func (s *Server) listSnapshots(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.snapshotsOf(r, ""))
+}
+
+func (s *Server) getSnapshot(w http.ResponseWriter, r *http.Request) {
+	snap, ok := s.snapshot(r, r.PathValue("snapshot"))
+	if !ok {
+		notFound(w, "database_snapshot_not_found", "snapshot", r.PathValue("snapshot"))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.snapshotView(snap))
+}
+
+func (s *Server) deleteSnapshot(w http.ResponseWriter, r *http.Request) {
+	snap, ok := s.snapshot(r, r.PathValue("snapshot"))
+	if !ok {
+		notFound(w, "database_snapshot_not_found", "snapshot", r.PathValue("snapshot"))
+		return
+	}
+	delete(s.snapshots, snap.SnapshotID)
+	writeSuccess(w, snap.SnapshotID)
+}
@@ -0,0 +1,104 @@
+package transport
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+)
+
+// Forwarder is a loopback HTTP server that sends the requests it receives to the
+// API through a RoundTripper. The requests must start with a random path prefix,
+// so other processes of the machine can't use it
+type Forwarder struct {
+	target *url.URL
+	prefix string
+
+	listener net.Listener
+	server   *http.Server
+}
+
+// NewForwarder starts a Forwarder sending the requests to target through rt
+func NewForwarder(target string, rt http.RoundTripper) (*Forwarder, error) {
+	targetURL, err := url.Parse(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid API URL %q: %s", target, err)
+	}
+	if targetURL.Scheme == "" || targetURL.Host == "" {
+		return nil, fmt.Errorf("invalid API URL %q: the scheme and host are required", target)
+	}
+
+	secret := make([]byte, 16)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to start the API forwarder: %s", err)
+	}
+
+	f := &Forwarder{
+		target:   targetURL,
+		prefix:   "/" + hex.EncodeToString(secret),
+		listener: listener,
+	}
+
+	proxy := &httputil.ReverseProxy{
+		Rewrite:      f.rewrite,
+		Transport:    rt,
+		ErrorHandler: f.handleError,
+	}
+	f.server = &http.Server{Handler: f.handler(proxy)}
+
+	go func() {
+		if err := f.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Printf("[ERROR] the API forwarder stopped: %s", err)
+		}
+	}()
+
+	return f, nil
+}
+
+// URL returns the base URL to give to the civogo client in place of the API URL
+func (f *Forwarder) URL() string {
+	return "http://" + f.listener.Addr().String() + f.prefix
+}
+
+// Close stops the Forwarder
+func (f *Forwarder) Close() error {
+	return f.server.Close()
+}
+
+func (f *Forwarder) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != f.prefix && !strings.HasPrefix(r.URL.Path, f.prefix+"/") {
+			http.NotFound(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// rewrite points the request at the API, keeping the path of the API URL
+func (f *Forwarder) rewrite(r *httputil.ProxyRequest) {
+	path := strings.TrimPrefix(r.In.URL.Path, f.prefix)
+	r.Out.URL.Scheme = f.target.Scheme
+	r.Out.URL.Host = f.target.Host
+	r.Out.URL.Path = strings.TrimSuffix(f.target.Path, "/") + path
+	r.Out.URL.RawPath = ""
+	r.Out.Host = f.target.Host
+}
+
+// handleError answers with a 502 that carries the error, so it shows in the error of civogo
+func (f *Forwarder) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	log.Printf("[ERROR] %s %s failed: %s", r.Method, strings.TrimPrefix(r.URL.Path, f.prefix), err)
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusBadGateway)
+	fmt.Fprintf(w, "the request to the Civo API failed: %s", err)
+}
@@ -0,0 +1,113 @@
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// HTTPOptions holds the TLS and proxy settings used to reach the API
+type HTTPOptions struct {
+	// CACertFile is a PEM bundle trusted in addition to the system certificates
+	CACertFile string
+	// ClientCertFile and ClientKeyFile are the PEM certificate and key presented to the server
+	ClientCertFile string
+	ClientKeyFile  string
+	// HTTPProxy is the proxy used for every request, the HTTPS_PROXY and HTTP_PROXY
+	// environment variables are used when it is empty
+	HTTPProxy string
+	// NoProxy lists the hosts reached without the proxy, in the format of NO_PROXY
+	NoProxy string
+	// InsecureSkipVerify disables the verification of the server certificate
+	InsecureSkipVerify bool
+}
+
+// NewHTTPTransport returns an http.Transport using the options, the files are read and
+// the proxy parsed here so a wrong setting fails straight away
+func NewHTTPTransport(options HTTPOptions) (*http.Transport, error) {
+	base := http.DefaultTransport.(*http.Transport).Clone()
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if options.CACertFile != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			log.Printf("[WARN] failed to load the system certificates, only the CA bundle will be trusted: %s", err)
+			pool = x509.NewCertPool()
+		}
+		data, err := os.ReadFile(options.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the CA bundle: %s", err)
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("the CA bundle %s doesn't contain any PEM certificate", options.CACertFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if options.ClientCertFile != "" || options.ClientKeyFile != "" {
+		if options.ClientCertFile == "" || options.ClientKeyFile == "" {
+			return nil, fmt.Errorf("the client certificate and key must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(options.ClientCertFile, options.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the client certificate: %s", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if options.InsecureSkipVerify {
+		log.Printf("[WARN] the certificate of the Civo API is not verified, this should only be used for testing")
+		tlsConfig.InsecureSkipVerify = true
+	}
+
+	base.TLSClientConfig = tlsConfig
+
+	proxy, err := proxyFunc(options.HTTPProxy, options.NoProxy)
+	if err != nil {
+		return nil, err
+	}
+	base.Proxy = proxy
+
+	return base, nil
+}
+
+// proxyFunc returns the proxy selection of the transport, the environment variables
+// are used for the settings that are not given
+func proxyFunc(proxy, noProxy string) (func(*http.Request) (*url.URL, error), error) {
+	if proxy == "" && noProxy == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+
+	config := httpproxy.FromEnvironment()
+	if proxy != "" {
+		proxyURL, err := url.Parse(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL %q: %s", proxy, err)
+		}
+		switch proxyURL.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return nil, fmt.Errorf("invalid proxy URL %q: the scheme must be http, https or socks5", proxy)
+		}
+		if proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q: the host is required", proxy)
+		}
+		config.HTTPProxy = proxy
+		config.HTTPSProxy = proxy
+	}
+	if noProxy != "" {
+		config.NoProxy = noProxy
+	}
+
+	selectProxy := config.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return selectProxy(req.URL)
+	}, nil
+}
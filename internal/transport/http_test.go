@@ -0,0 +1,204 @@
+package transport
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeServerCA writes the certificate of a TLS test server as a CA bundle
+func writeServerCA(t *testing.T, server *httptest.Server) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("failed to write the CA bundle: %s", err)
+	}
+	return path
+}
+
+// writeClientCert writes a self-signed client certificate and its key, and returns them with the certificate
+func writeClientCert(t *testing.T) (string, string, *x509.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate the key: %s", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "terraform"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create the certificate: %s", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse the certificate: %s", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal the key: %s", err)
+	}
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client-key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatalf("failed to write the certificate: %s", err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatalf("failed to write the key: %s", err)
+	}
+	return certFile, keyFile, cert
+}
+
+func get(t *testing.T, rt http.RoundTripper, url string) error {
+	t.Helper()
+	resp, err := (&http.Client{Transport: rt}).Get(url)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func TestHTTPTransportCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	rt, err := NewHTTPTransport(HTTPOptions{})
+	if err != nil {
+		t.Fatalf("NewHTTPTransport: %s", err)
+	}
+	if err := get(t, rt, server.URL); err == nil {
+		t.Error("expected the certificate of the test server not to be trusted")
+	}
+
+	rt, err = NewHTTPTransport(HTTPOptions{CACertFile: writeServerCA(t, server)})
+	if err != nil {
+		t.Fatalf("NewHTTPTransport: %s", err)
+	}
+	if err := get(t, rt, server.URL); err != nil {
+		t.Errorf("expected the CA bundle to be trusted: %s", err)
+	}
+
+	rt, err = NewHTTPTransport(HTTPOptions{InsecureSkipVerify: true})
+	if err != nil {
+		t.Fatalf("NewHTTPTransport: %s", err)
+	}
+	if err := get(t, rt, server.URL); err != nil {
+		t.Errorf("expected the certificate not to be verified: %s", err)
+	}
+}
+
+func TestHTTPTransportClientCertificate(t *testing.T) {
+	certFile, keyFile, cert := writeClientCert(t)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(cert)
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+
+	caFile := writeServerCA(t, server)
+
+	rt, err := NewHTTPTransport(HTTPOptions{CACertFile: caFile})
+	if err != nil {
+		t.Fatalf("NewHTTPTransport: %s", err)
+	}
+	if err := get(t, rt, server.URL); err == nil {
+		t.Error("expected the server to require a client certificate")
+	}
+
+	rt, err = NewHTTPTransport(HTTPOptions{CACertFile: caFile, ClientCertFile: certFile, ClientKeyFile: keyFile})
+	if err != nil {
+		t.Fatalf("NewHTTPTransport: %s", err)
+	}
+	if err := get(t, rt, server.URL); err != nil {
+		t.Errorf("expected the client certificate to be accepted: %s", err)
+	}
+}
+
+func TestHTTPTransportProxy(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.URL.String())
+	}))
+	defer proxy.Close()
+
+	rt, err := NewHTTPTransport(HTTPOptions{HTTPProxy: proxy.URL, NoProxy: "internal.example.com"})
+	if err != nil {
+		t.Fatalf("NewHTTPTransport: %s", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "http://api.example.com/v2/regions", nil)
+	proxyURL, err := rt.Proxy(req)
+	if err != nil || proxyURL == nil || proxyURL.String() != proxy.URL {
+		t.Errorf("proxy = %v, %v, want %s", proxyURL, err, proxy.URL)
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "https://internal.example.com/v2/regions", nil)
+	if proxyURL, err := rt.Proxy(req); err != nil || proxyURL != nil {
+		t.Errorf("proxy = %v, %v, want no proxy for a host in no_proxy", proxyURL, err)
+	}
+
+	if err := get(t, rt, "http://api.example.com/v2/regions"); err != nil {
+		t.Fatalf("GET: %s", err)
+	}
+	if len(proxied) != 1 || proxied[0] != "http://api.example.com/v2/regions" {
+		t.Errorf("proxied = %v, want the request to go through the proxy", proxied)
+	}
+}
+
+func TestHTTPTransportInvalidOptions(t *testing.T) {
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "not-pem")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0600); err != nil {
+		t.Fatalf("failed to write the file: %s", err)
+	}
+	certFile, _, _ := writeClientCert(t)
+	_, otherKey, _ := writeClientCert(t)
+
+	tests := []struct {
+		name    string
+		options HTTPOptions
+		err     string
+	}{
+		{name: "missing CA bundle", options: HTTPOptions{CACertFile: filepath.Join(dir, "missing.pem")}, err: "failed to read the CA bundle"},
+		{name: "CA bundle without certificates", options: HTTPOptions{CACertFile: notPEM}, err: "doesn't contain any PEM certificate"},
+		{name: "certificate without key", options: HTTPOptions{ClientCertFile: certFile}, err: "must be set together"},
+		{name: "key of another certificate", options: HTTPOptions{ClientCertFile: certFile, ClientKeyFile: otherKey}, err: "failed to load the client certificate"},
+		{name: "proxy with unknown scheme", options: HTTPOptions{HTTPProxy: "ftp://proxy:21"}, err: "the scheme must be"},
+		{name: "proxy without host", options: HTTPOptions{HTTPProxy: "http://"}, err: "the host is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewHTTPTransport(tt.options)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("err = %v, want it to contain %q", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+// Package transport provides the HTTP transport shared by every resource of
+// the provider. It retries throttled and failed requests with an exponential
+// backoff, honours the Retry-After header of the API and limits how many
+// requests run at the same time in each region.
+//
+// civogo replaces the transport of its HTTP client on every request, so the
+// Transport can't be given to the client directly. Instead the client talks
+// to a Forwarder, a loopback server that sends the requests to the API
+// through the Transport.
+package transport
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultMaxRetries is the number of retries used when Config.MaxRetries is negative
+	DefaultMaxRetries = 4
+	// DefaultRetryWaitMin is the shortest wait between two attempts
+	DefaultRetryWaitMin = 1 * time.Second
+	// DefaultRetryWaitMax is the longest wait between two attempts, unless the API asks for more with Retry-After
+	DefaultRetryWaitMax = 30 * time.Second
+	// DefaultMaxConcurrentRequests is the number of requests that can run at the same time in a region
+	DefaultMaxConcurrentRequests = 10
+)
+
+// Config holds the retry and concurrency settings of a Transport
+type Config struct {
+	// MaxRetries is how many times a request is retried after the first attempt, zero disables retries
+	MaxRetries int
+	// RetryWaitMin and RetryWaitMax bound the exponential backoff between attempts
+	RetryWaitMin time.Duration
+	RetryWaitMax time.Duration
+	// MaxConcurrentRequests limits the requests running at the same time in each region, zero means no limit
+	MaxConcurrentRequests int
+}
+
+// Transport is an http.RoundTripper that retries requests and limits the concurrency per region
+type Transport struct {
+	base   http.RoundTripper
+	config Config
+
+	mu     sync.Mutex
+	slots  map[string]chan struct{}
+	random *rand.Rand
+}
+
+// New returns a Transport sending the requests through base, http.DefaultTransport is used when base is nil
+func New(base http.RoundTripper, config Config) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if config.MaxRetries < 0 {
+		config.MaxRetries = DefaultMaxRetries
+	}
+	if config.RetryWaitMin <= 0 {
+		config.RetryWaitMin = DefaultRetryWaitMin
+	}
+	if config.RetryWaitMax < config.RetryWaitMin {
+		config.RetryWaitMax = config.RetryWaitMin
+	}
+
+	return &Transport{
+		base:   base,
+		config: config,
+		slots:  map[string]chan struct{}{},
+		random: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// RoundTrip sends the request, retrying it while the API throttles it or fails with a transient error
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// the body is sent again on every attempt
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	region := strings.ToUpper(req.URL.Query().Get("region"))
+
+	for attempt := 0; ; attempt++ {
+		attemptReq := req.Clone(req.Context())
+		if body != nil {
+			attemptReq.Body = io.NopCloser(bytes.NewReader(body))
+			attemptReq.ContentLength = int64(len(body))
+		}
+
+		resp, err := t.send(attemptReq, region)
+		if attempt >= t.config.MaxRetries || !shouldRetry(req, resp, err) {
+			return resp, err
+		}
+
+		wait := t.backoff(attempt, resp)
+		if err != nil {
+			log.Printf("[WARN] %s %s failed, retrying in %s (%d/%d): %s", req.Method, req.URL.Path, wait, attempt+1, t.config.MaxRetries, err)
+		} else {
+			log.Printf("[WARN] %s %s returned %s, retrying in %s (%d/%d)", req.Method, req.URL.Path, resp.Status, wait, attempt+1, t.config.MaxRetries)
+			// the connection can only be reused once the body has been read
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+			resp.Body.Close()
+		}
+
+		if err := sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// send runs one attempt, waiting for a free slot in the region first
+func (t *Transport) send(req *http.Request, region string) (*http.Response, error) {
+	slot := t.slot(region)
+	if slot == nil {
+		return t.base.RoundTrip(req)
+	}
+
+	select {
+	case slot <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+	release := func() { <-slot }
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	// the slot is kept until the response has been read
+	resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// releaseOnClose frees the slot of a request when its response body is closed
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+	once    sync.Once
+}
+
+func (r *releaseOnClose) Close() error {
+	err := r.ReadCloser.Close()
+	r.once.Do(r.release)
+	return err
+}
+
+// slot returns the semaphore of a region, nil when the concurrency is not limited
+func (t *Transport) slot(region string) chan struct{} {
+	if t.config.MaxConcurrentRequests <= 0 {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	slot, ok := t.slots[region]
+	if !ok {
+		slot = make(chan struct{}, t.config.MaxConcurrentRequests)
+		t.slots[region] = slot
+	}
+	return slot
+}
+
+// backoff returns the wait before the next attempt, the Retry-After header of the
+// response is used when present, otherwise the wait doubles on every attempt and
+// a random jitter is applied so concurrent requests don't retry together
+func (t *Transport) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if wait, ok := retryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			if wait < t.config.RetryWaitMin {
+				return t.config.RetryWaitMin
+			}
+			return wait
+		}
+	}
+
+	wait := t.config.RetryWaitMax
+	if exp := math.Pow(2, float64(attempt)) * float64(t.config.RetryWaitMin); exp < float64(t.config.RetryWaitMax) {
+		wait = time.Duration(exp)
+	}
+
+	// full jitter on the upper half, the wait never goes below half of the backoff
+	t.mu.Lock()
+	jitter := time.Duration(t.random.Int63n(int64(wait/2) + 1))
+	t.mu.Unlock()
+	wait = wait/2 + jitter
+
+	if wait < t.config.RetryWaitMin {
+		return t.config.RetryWaitMin
+	}
+	return wait
+}
+
+// shouldRetry reports whether the attempt failed in a way that can be retried. Throttled
+// requests (429) and unavailable services (503) are retried for every method, as the API
+// didn't process them, other server and network errors only for idempotent methods
+func shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+	if err != nil {
+		return isIdempotent(req.Method)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode == http.StatusServiceUnavailable:
+		return true
+	case resp.StatusCode == http.StatusNotImplemented:
+		return false
+	case resp.StatusCode >= 500:
+		return isIdempotent(req.Method)
+	}
+	return false
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryAfter parses a Retry-After header, given in seconds or as an HTTP date
+func retryAfter(header string, now time.Time) (time.Duration, bool) {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		if wait := date.Sub(now); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// sleep waits for d or until the context is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,199 @@
+package transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/civo/civogo"
+	"github.com/civo/terraform-provider-civo/internal/mockapi"
+)
+
+func newForwardedClient(t *testing.T, config Config) (*mockapi.Server, *civogo.Client) {
+	t.Helper()
+	s := mockapi.NewServer()
+	t.Cleanup(s.Close)
+
+	f, err := NewForwarder(s.URL, New(nil, config))
+	if err != nil {
+		t.Fatalf("NewForwarder: %s", err)
+	}
+	t.Cleanup(func() { f.Close() })
+
+	client, err := civogo.NewClientWithURL(mockapi.Token, f.URL(), mockapi.DefaultRegion)
+	if err != nil {
+		t.Fatalf("failed to build client: %s", err)
+	}
+	return s, client
+}
+
+func fastRetries(maxRetries int) Config {
+	return Config{MaxRetries: maxRetries, RetryWaitMin: time.Millisecond, RetryWaitMax: 5 * time.Millisecond}
+}
+
+func TestRetriesThrottledRequests(t *testing.T) {
+	s, client := newForwardedClient(t, fastRetries(3))
+
+	s.Inject(mockapi.Fault{Method: http.MethodGet, Path: "/v2/regions", Status: http.StatusTooManyRequests, Count: 2})
+	if _, err := client.ListRegions(); err != nil {
+		t.Fatalf("ListRegions: %s", err)
+	}
+	if got := s.RequestCount(http.MethodGet, "/v2/regions"); got != 3 {
+		t.Errorf("RequestCount = %d, want 3", got)
+	}
+}
+
+func TestGivesUpAfterMaxRetries(t *testing.T) {
+	s, client := newForwardedClient(t, fastRetries(2))
+
+	s.Inject(mockapi.Fault{Method: http.MethodGet, Path: "/v2/regions", Status: http.StatusServiceUnavailable})
+	if _, err := client.ListRegions(); err == nil {
+		t.Fatal("expected the request to fail once the retries are exhausted")
+	}
+	if got := s.RequestCount(http.MethodGet, "/v2/regions"); got != 3 {
+		t.Errorf("RequestCount = %d, want 3", got)
+	}
+}
+
+func TestDoesNotRetryNonIdempotentServerErrors(t *testing.T) {
+	s, client := newForwardedClient(t, fastRetries(3))
+
+	s.Inject(mockapi.Fault{Method: http.MethodPost, Path: "/v2/sshkeys", Status: http.StatusInternalServerError, Count: 1})
+	if _, err := client.NewSSHKey("key", "ssh-ed25519 AAAA"); err == nil {
+		t.Fatal("expected the injected 500 to fail the request")
+	}
+	if got := s.RequestCount(http.MethodPost, "/v2/sshkeys"); got != 1 {
+		t.Errorf("RequestCount = %d, want the POST to be sent once", got)
+	}
+
+	// throttled requests were not processed, so they are retried for every method
+	s.Inject(mockapi.Fault{Method: http.MethodPost, Path: "/v2/sshkeys", Status: http.StatusTooManyRequests, Count: 1})
+	if _, err := client.NewSSHKey("key", "ssh-ed25519 AAAA"); err != nil {
+		t.Fatalf("NewSSHKey: %s", err)
+	}
+	if got := s.RequestCount(http.MethodPost, "/v2/sshkeys"); got != 3 {
+		t.Errorf("RequestCount = %d, want 3", got)
+	}
+}
+
+func TestHonoursRetryAfter(t *testing.T) {
+	s, client := newForwardedClient(t, fastRetries(1))
+
+	s.Inject(mockapi.Fault{Method: http.MethodGet, Path: "/v2/regions", Status: http.StatusTooManyRequests, RetryAfter: 1, Count: 1})
+	start := time.Now()
+	if _, err := client.ListRegions(); err != nil {
+		t.Fatalf("ListRegions: %s", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("request took %s, want the retry to wait for Retry-After", elapsed)
+	}
+}
+
+func TestForwarderRejectsRequestsWithoutPrefix(t *testing.T) {
+	s := mockapi.NewServer()
+	defer s.Close()
+
+	f, err := NewForwarder(s.URL, New(nil, Config{}))
+	if err != nil {
+		t.Fatalf("NewForwarder: %s", err)
+	}
+	defer f.Close()
+
+	resp, err := http.Get("http://" + f.listener.Addr().String() + "/v2/regions")
+	if err != nil {
+		t.Fatalf("GET: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", resp.StatusCode)
+	}
+	if got := s.RequestCount(http.MethodGet, "/v2/regions"); got != 0 {
+		t.Errorf("RequestCount = %d, want the request not to be forwarded", got)
+	}
+}
+
+func TestConcurrencyIsLimitedPerRegion(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: New(nil, Config{MaxConcurrentRequests: 2})}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(server.URL + "/v2/instances?region=LON1")
+			if err != nil {
+				t.Errorf("GET: %s", err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	if maxInFlight > 2 {
+		t.Errorf("%d requests ran at the same time, want at most 2", maxInFlight)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tr := New(nil, Config{MaxRetries: 5, RetryWaitMin: time.Second, RetryWaitMax: 8 * time.Second})
+
+	for attempt := 0; attempt < 6; attempt++ {
+		want := time.Second << attempt
+		if want > 8*time.Second {
+			want = 8 * time.Second
+		}
+		for i := 0; i < 20; i++ {
+			wait := tr.backoff(attempt, nil)
+			if wait < time.Second || wait < want/2 || wait > want {
+				t.Fatalf("attempt %d: wait = %s, want between %s and %s", attempt, wait, want/2, want)
+			}
+		}
+	}
+
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"20"}}}
+	if wait := tr.backoff(0, resp); wait != 20*time.Second {
+		t.Errorf("wait = %s, want the 20s asked by Retry-After", wait)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		header string
+		wait   time.Duration
+		ok     bool
+	}{
+		{header: "", ok: false},
+		{header: "3", wait: 3 * time.Second, ok: true},
+		{header: "-1", ok: false},
+		{header: "Mon, 01 Jan 2024 12:00:30 GMT", wait: 30 * time.Second, ok: true},
+		{header: "Mon, 01 Jan 2024 11:00:00 GMT", wait: 0, ok: true},
+		{header: "soon", ok: false},
+	}
+
+	for _, tt := range tests {
+		wait, ok := retryAfter(tt.header, now)
+		if ok != tt.ok || wait != tt.wait {
+			t.Errorf("retryAfter(%q) = %s, %t, want %s, %t", tt.header, wait, ok, tt.wait, tt.ok)
+		}
+	}
+}
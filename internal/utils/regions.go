@@ -0,0 +1,154 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/agext/levenshtein"
+	"github.com/civo/civogo"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// regionCache holds the regions of a provider configuration, they are only listed once
+type regionCache struct {
+	mu      sync.Mutex
+	regions []civogo.Region
+}
+
+// providerRegions holds the regions of every provider configuration, keyed like defaultTags
+var providerRegions sync.Map
+
+func regionCacheFor(apiClient *civogo.Client) *regionCache {
+	key := ""
+	if apiClient.BaseURL != nil {
+		key = apiClient.BaseURL.String()
+	}
+	cache, _ := providerRegions.LoadOrStore(key, &regionCache{})
+	return cache.(*regionCache)
+}
+
+// SetRegions records the regions listed while configuring the provider, so they are not listed again
+func SetRegions(apiClient *civogo.Client, regions []civogo.Region) {
+	cache := regionCacheFor(apiClient)
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.regions = regions
+}
+
+// Regions returns the regions of the provider configuration of the client, they are listed
+// the first time they are needed and cached for the other resources
+func Regions(apiClient *civogo.Client) ([]civogo.Region, error) {
+	cache := regionCacheFor(apiClient)
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if cache.regions == nil {
+		regions, err := apiClient.ListRegions()
+		if err != nil {
+			return nil, err
+		}
+		cache.regions = regions
+	}
+	return cache.regions, nil
+}
+
+// ResolveRegion returns the code of the region as the API lists it, the match ignores the case.
+// The error suggests the closest code when the region doesn't exist
+func ResolveRegion(apiClient *civogo.Client, region string) (string, error) {
+	regions, err := Regions(apiClient)
+	if err != nil {
+		return "", fmt.Errorf("failed to list the regions: %s", err)
+	}
+	match, err := matchRegion(regions, region)
+	if err != nil {
+		return "", err
+	}
+	return match.Code, nil
+}
+
+// matchRegion finds the region by code, ignoring the case
+func matchRegion(regions []civogo.Region, region string) (*civogo.Region, error) {
+	codes := make([]string, 0, len(regions))
+	for i := range regions {
+		if strings.EqualFold(regions[i].Code, region) {
+			return &regions[i], nil
+		}
+		codes = append(codes, regions[i].Code)
+	}
+	sort.Strings(codes)
+
+	if closest := closestRegion(codes, region); closest != "" {
+		return nil, fmt.Errorf("the region %q doesn't exist, did you mean %q? The available regions are: %s", region, closest, strings.Join(codes, ", "))
+	}
+	return nil, fmt.Errorf("the region %q doesn't exist, the available regions are: %s", region, strings.Join(codes, ", "))
+}
+
+// closestRegion returns the code with the smallest edit distance to the region, or
+// nothing when every code is too different to be a typo
+func closestRegion(codes []string, region string) string {
+	region = strings.ToUpper(region)
+	closest, best := "", len(region)/2+1
+	for _, code := range codes {
+		if distance := levenshtein.Distance(strings.ToUpper(code), region, nil); distance < best {
+			closest, best = code, distance
+		}
+	}
+	return closest
+}
+
+// CustomizeDiffRegion checks the region of a resource exists when it is created or changed,
+// so a typo fails at plan time instead of inside the create
+func CustomizeDiffRegion(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" && !d.HasChange("region") {
+		return nil
+	}
+
+	raw := d.GetRawConfig()
+	if raw.IsNull() || !raw.IsKnown() {
+		return nil
+	}
+	region := raw.GetAttr("region")
+	if region.IsNull() || !region.IsKnown() || region.AsString() == "" {
+		return nil
+	}
+
+	apiClient, ok := meta.(*civogo.Client)
+	if !ok {
+		return nil
+	}
+	regions, err := Regions(apiClient)
+	if err != nil {
+		return fmt.Errorf("failed to list the regions: %s", err)
+	}
+	match, err := matchRegion(regions, region.AsString())
+	if err != nil {
+		return err
+	}
+	if match.OutOfCapacity {
+		log.Printf("[WARN] the region %s is out of capacity, new resources may fail to be created", match.Code)
+	}
+	return nil
+}
+
+// NormalizeRegion stores the region in the state as the API lists it, so every resource
+// uses the same case. The region is kept as it is when the regions can't be listed
+func NormalizeRegion(d *schema.ResourceData, meta interface{}) {
+	region, ok := d.Get("region").(string)
+	apiClient, isClient := meta.(*civogo.Client)
+	if !ok || region == "" || !isClient {
+		return
+	}
+
+	code, err := ResolveRegion(apiClient, region)
+	if err != nil {
+		log.Printf("[DEBUG] the region %s was not normalized: %s", region, err)
+		return
+	}
+	if code != region {
+		d.Set("region", code)
+	}
+}
@@ -0,0 +1,113 @@
+package utils
+
+import (
+	"sync"
+
+	"github.com/civo/civogo"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// defaultTags holds the default_tags of every provider configuration, keyed by the
+// base URL of its client. The URL is unique to each configuration and is kept by
+// the copies made by RegionalClient, so every resource finds the tags of its provider
+var defaultTags sync.Map
+
+// SetDefaultTags records the default_tags of the provider configuration of the client
+func SetDefaultTags(apiClient *civogo.Client, tags []string) {
+	defaultTags.Store(apiClient.BaseURL.String(), append([]string(nil), tags...))
+}
+
+// DefaultTags returns the default_tags of the provider configuration of the client
+func DefaultTags(apiClient *civogo.Client) []string {
+	if apiClient == nil || apiClient.BaseURL == nil {
+		return nil
+	}
+	tags, ok := defaultTags.Load(apiClient.BaseURL.String())
+	if !ok {
+		return nil
+	}
+	return tags.([]string)
+}
+
+// MergeTags returns the tags of a resource followed by the default tags it doesn't
+// already have, this is the list of tags sent to the API and stored in tags_all
+func MergeTags(tags, defaults []string) []string {
+	seen := map[string]bool{}
+	merged := []string{}
+	for _, list := range [][]string{tags, defaults} {
+		for _, tag := range list {
+			if tag == "" || seen[tag] {
+				continue
+			}
+			seen[tag] = true
+			merged = append(merged, tag)
+		}
+	}
+	return merged
+}
+
+// ResourceTags returns the tags read from the API without the default tags, unless they
+// are also configured in the resource, so the tags owned by the provider don't show a diff
+func ResourceTags(remote, configured, defaults []string) []string {
+	owned := map[string]bool{}
+	for _, tag := range defaults {
+		owned[tag] = true
+	}
+	for _, tag := range configured {
+		delete(owned, tag)
+	}
+
+	tags := []string{}
+	for _, tag := range remote {
+		if !owned[tag] {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// TagsAllSchema returns the schema of tags_all, the tags of a resource with the default_tags of the provider
+func TagsAllSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeSet,
+		Computed:    true,
+		Description: "All the tags of the resource, including the `default_tags` of the provider",
+		Elem:        &schema.Schema{Type: schema.TypeString},
+	}
+}
+
+// ExpandTags converts a set or list of tags to a slice
+func ExpandTags(raw interface{}) []string {
+	var list []interface{}
+	switch v := raw.(type) {
+	case *schema.Set:
+		list = v.List()
+	case []interface{}:
+		list = v
+	}
+
+	tags := make([]string, 0, len(list))
+	for _, tag := range list {
+		if s, ok := tag.(string); ok {
+			tags = append(tags, s)
+		}
+	}
+	return tags
+}
+
+// CustomizeDiffTagsAll sets tags_all to the configured tags merged with the default_tags
+// of the provider, so a change of the default_tags updates the resource
+func CustomizeDiffTagsAll(d *schema.ResourceDiff, meta interface{}, tags []string) error {
+	if raw := d.GetRawConfig(); !raw.IsNull() && raw.IsKnown() && !raw.GetAttr("tags").IsWhollyKnown() {
+		return d.SetNewComputed("tags_all")
+	}
+
+	apiClient, _ := meta.(*civogo.Client)
+	merged := MergeTags(tags, DefaultTags(apiClient))
+	values := make([]interface{}, len(merged))
+	for i, tag := range merged {
+		values[i] = tag
+	}
+
+	return d.SetNew("tags_all", schema.NewSet(schema.HashString, values))
+}
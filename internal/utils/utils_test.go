@@ -2,10 +2,14 @@ package utils
 
 import (
 	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
 	"sync"
 	"testing"
 
 	"github.com/civo/civogo"
+	"github.com/civo/terraform-provider-civo/internal/mockapi"
 )
 
 func TestIgnoreCaseDiff(t *testing.T) {
@@ -132,3 +136,128 @@ func TestRegionalClientConcurrent(t *testing.T) {
 		t.Errorf("shared.Region = %q after concurrent scoping, want %q", shared.Region, "lon1")
 	}
 }
+
+func TestDefaultTags(t *testing.T) {
+	shared, err := civogo.NewClientWithURL("secret", "http://127.0.0.1:1/provider-a", "lon1")
+	if err != nil {
+		t.Fatalf("failed to build client: %s", err)
+	}
+	other, err := civogo.NewClientWithURL("secret", "http://127.0.0.1:1/provider-b", "lon1")
+	if err != nil {
+		t.Fatalf("failed to build client: %s", err)
+	}
+
+	SetDefaultTags(shared, []string{"owner:platform"})
+
+	if got := DefaultTags(RegionalClient(shared, "fra1")); !reflect.DeepEqual(got, []string{"owner:platform"}) {
+		t.Errorf("DefaultTags(regional copy) = %v, want the tags of its provider", got)
+	}
+	if got := DefaultTags(other); got != nil {
+		t.Errorf("DefaultTags(other provider) = %v, want none", got)
+	}
+	if got := DefaultTags(nil); got != nil {
+		t.Errorf("DefaultTags(nil) = %v, want none", got)
+	}
+}
+
+func TestMergeTags(t *testing.T) {
+	got := MergeTags([]string{"web", "owner:platform", "web"}, []string{"owner:platform", "cost-center:1234", ""})
+	want := []string{"web", "owner:platform", "cost-center:1234"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeTags = %v, want %v", got, want)
+	}
+}
+
+func TestResourceTags(t *testing.T) {
+	defaults := []string{"owner:platform", "cost-center:1234"}
+
+	tests := []struct {
+		name       string
+		remote     []string
+		configured []string
+		want       []string
+	}{
+		{
+			name:   "default tags are hidden",
+			remote: []string{"web", "owner:platform", "cost-center:1234"},
+			want:   []string{"web"},
+		},
+		{
+			name:       "default tags that are also configured are kept",
+			remote:     []string{"web", "owner:platform", "cost-center:1234"},
+			configured: []string{"web", "owner:platform"},
+			want:       []string{"web", "owner:platform"},
+		},
+		{
+			name:   "tags added outside of terraform are kept",
+			remote: []string{"manual", "owner:platform"},
+			want:   []string{"manual"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ResourceTags(tt.remote, tt.configured, defaults); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ResourceTags = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchRegion(t *testing.T) {
+	regions := []civogo.Region{{Code: "LON1"}, {Code: "FRA1"}, {Code: "NYC1"}, {Code: "PHX1"}}
+
+	tests := []struct {
+		region string
+		code   string
+		err    string
+	}{
+		{region: "LON1", code: "LON1"},
+		{region: "lon1", code: "LON1"},
+		{region: "lon2", err: `the region "lon2" doesn't exist, did you mean "LON1"? The available regions are: FRA1, LON1, NYC1, PHX1`},
+		{region: "FRA", err: `did you mean "FRA1"?`},
+		{region: "amsterdam", err: `the region "amsterdam" doesn't exist, the available regions are: FRA1, LON1, NYC1, PHX1`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.region, func(t *testing.T) {
+			match, err := matchRegion(regions, tt.region)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("err = %v, want it to contain %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if match.Code != tt.code {
+				t.Errorf("code = %q, want %q", match.Code, tt.code)
+			}
+		})
+	}
+}
+
+func TestResolveRegionIsCached(t *testing.T) {
+	s := mockapi.NewServer()
+	defer s.Close()
+
+	client, err := s.Client(mockapi.DefaultRegion)
+	if err != nil {
+		t.Fatalf("failed to build client: %s", err)
+	}
+
+	for _, region := range []string{"lon1", "fra1", "NYC1"} {
+		code, err := ResolveRegion(RegionalClient(client, region), region)
+		if err != nil {
+			t.Fatalf("ResolveRegion(%s): %s", region, err)
+		}
+		if code != strings.ToUpper(region) {
+			t.Errorf("ResolveRegion(%s) = %q, want %q", region, code, strings.ToUpper(region))
+		}
+	}
+
+	if got := s.RequestCount(http.MethodGet, "/v2/regions"); got != 1 {
+		t.Errorf("the regions were listed %d times, want once", got)
+	}
+}
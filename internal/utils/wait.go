@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+// TransitionTimeout is how long a resource is given to leave its status after a change, a quick
+// change can complete between two reads and is then taken as done
+const TransitionTimeout = 2 * time.Minute
+
+// WaitForTransition function to wait until a resource reaches one of the target statuses after a
+// change. The resource is still in its status right after the request, so it is first waited
+// for to leave that status, otherwise the wait would return before the change has started. name
+// is used in the logs, e.g. "the instance <id>"
+func WaitForTransition(ctx context.Context, name, status string, pending, target []string, timeout time.Duration, refresh resource.StateRefreshFunc) error {
+	transitionTimeout := timeout
+	if transitionTimeout > TransitionTimeout {
+		transitionTimeout = TransitionTimeout
+	}
+
+	transitionStateConf := &resource.StateChangeConf{
+		Pending: []string{status},
+		Target:  []string{"changed"},
+		Refresh: func() (interface{}, string, error) {
+			resp, state, err := refresh()
+			if err != nil || state == status {
+				return resp, state, err
+			}
+			return resp, "changed", nil
+		},
+		Timeout:    transitionTimeout,
+		Delay:      time.Second,
+		MinTimeout: time.Second,
+	}
+	_, err := transitionStateConf.WaitForStateContext(ctx)
+	var timeoutErr *resource.TimeoutError
+	if errors.As(err, &timeoutErr) {
+		log.Printf("[WARN] %s stayed %s, the change is taken as done", name, status)
+	} else if err != nil {
+		return err
+	}
+
+	stateConf := &resource.StateChangeConf{
+		Pending:        pending,
+		Target:         target,
+		Refresh:        refresh,
+		Timeout:        timeout,
+		Delay:          3 * time.Second,
+		MinTimeout:     3 * time.Second,
+		NotFoundChecks: 60,
+	}
+	_, err = stateConf.WaitForStateContext(ctx)
+	return err
+}
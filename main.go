@@ -1,12 +1,20 @@
 package main
 
 import (
+	"context"
+	"log"
+
 	"github.com/civo/terraform-provider-civo/civo"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5/tf5server"
 )
 
 func main() {
-	plugin.Serve(&plugin.ServeOpts{
-		ProviderFunc: civo.Provider,
-	})
+	providerServer, err := civo.ProviderServer(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := tf5server.Serve("registry.terraform.io/civo/civo", providerServer); err != nil {
+		log.Fatal(err)
+	}
 }